@@ -1,7 +1,17 @@
 package main
 
-import "github.com/eth-bridging/internal/app"
+import (
+	"os"
+
+	"github.com/eth-bridging/internal/app"
+)
 
 func main() {
+	// Any argument means a one off command, e.g. `rollup rebuild`
+	if len(os.Args) > 1 {
+		app.RunCommand(os.Args[1:])
+		return
+	}
+
 	app.Run()
 }
@@ -3,8 +3,10 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/eth-bridging/pkg/decimal"
 	"github.com/joho/godotenv"
 )
 
@@ -16,19 +18,110 @@ type CurrencyConfig struct {
 
 type CurrencyConfigMap map[string]CurrencyConfig
 
+// TokenConfig holds the symbol and number of decimals of an ERC20 token
+type TokenConfig struct {
+	Symbol   string
+	Decimals uint
+}
+
+// TokenConfigMap is keyed by lowercase token address
+type TokenConfigMap map[string]TokenConfig
+
 type Config struct {
 	PostgresURL     string
 	RedisURL        string
 	RedisStreamName string
 	RedisStreamDlq  string
-	EthereumRPCURL  string
+	// StreamProducerID identifies the instance in the envelope of every event it publishes, whose payload
+	// is encoded as `StreamEncoding`, `json` or `protobuf`, see `internal/envelope`
+	StreamProducerID string
+	StreamEncoding   string
+	// EthereumRPCURLs are the endpoints events are read from, calls go to the healthiest one
+	EthereumRPCURLs []string
 	SocketGateAddr  string
 	ServerPort      string
 	ContractABI     string
 	TopicHex        string
 
+	// Contracts and events watched besides `SocketGateAddr` and `TopicHex`, ABIs are read from
+	// `ContractABIFiles` in addition to `ContractABI`. Events are given as topic hashes or names,
+	// every event with a decoder is watched when there are none, see `pkg/go-eth/decoders.go`
+	ContractAddresses []string
+	ContractABIFiles  []string
+	EventTopics       []string
+
+	// MigrateOnStart applies pending migrations on startup, otherwise the server
+	// refuses to start while migrations are pending
+	MigrateOnStart bool
+
+	// SMTP relay used by the `email` alert notifier
+	SMTPAddr     string
+	SMTPFrom     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Object storage old events are archived to, `ArchiveBackend` is `fs` or `s3`
+	ArchiveBackend       string
+	ArchiveDir           string
+	ArchiveS3Endpoint    string
+	ArchiveS3Bucket      string
+	ArchiveS3AccessKey   string
+	ArchiveS3SecretKey   string
+	ArchiveS3UseSSL      bool
+	ArchiveRetentionDays int
+
+	// Source of historical USD prices, `db`, `file` or `none`, see `internal/pricing`
+	PriceSource string
+	PriceFile   string
+	// PriceMaxAgeHours is how old a price can be and still be used to value an event, 0 is any age
+	PriceMaxAgeHours int
+
+	// BridgeNames are names of bridges identified by the keccak256 hash of their name,
+	// either the plain name or `0x<hash>=name`, see `pkg/go-eth/bridge_name.go`
+	BridgeNames []string
+
+	// Health checks of the RPC endpoints, see `pkg/go-eth/endpoints.go`
+	RPCCheckIntervalSeconds int
+	RPCMaxHeadLag           int
+
+	// IngestMode is how events are read, `auto`, `subscribe` or `poll`, polling reads
+	// confirmed blocks only, `PollStartBlock` 0 continues after the last covered block
+	IngestMode          string
+	PollIntervalSeconds int
+	PollConfirmations   int
+	PollMaxRange        int
+	PollStartBlock      int
+
+	// TransfersFile is the JSON file of tracked bridges and the destination chains their fills are
+	// read from, see `internal/transfers/spec.go`. Nothing is tracked when it's empty
+	TransfersFile                string
+	TransferMatchIntervalSeconds int
+	TransferMatchRetryMinutes    int
+
+	// Transfers not completed within their SLA are checked for every `SLACheckIntervalSeconds`,
+	// going back `SLALookbackHours`. Completion percentiles are computed over `SLAMetricsWindowHours`
+	SLACheckIntervalSeconds int
+	SLALookbackHours        int
+	SLAMetricsWindowHours   int
+
+	// Transfers move through their lifecycle every `LifecycleIntervalSeconds`, they're confirmed
+	// at `LifecycleConfirmations` and followed for `LifecycleLookbackHours` after being observed
+	LifecycleIntervalSeconds int
+	LifecycleConfirmations   int
+	LifecycleLookbackHours   int
+
+	// SourceChainID is the chain ID of the `EthereumRPCURLs` chain. Ingested block ranges of every chain are
+	// recorded, gaps are checked for every `CoverageCheckIntervalSeconds` and backfilled once they're
+	// `CoverageBackfillAfterMinutes` old, 0 only reports them
+	SourceChainID                string
+	CoverageCheckIntervalSeconds int
+	CoverageBackfillAfterMinutes int
+
 	// Define currency configurations
 	CurrencyConfigs CurrencyConfigMap
+
+	// Define known tokens, used to format amounts per token
+	TokenConfigs TokenConfigMap
 }
 
 func LoadConfig(envPath ...string) *Config {
@@ -46,21 +139,81 @@ func LoadConfig(envPath ...string) *Config {
 	}
 
 	return &Config{
-		PostgresURL:     os.Getenv("DATABASE_URL"),
-		RedisURL:        os.Getenv("REDIS_URL"),
-		RedisStreamName: os.Getenv("REDIS_STREAM"),
-		RedisStreamDlq:  os.Getenv("REDIS_STREAM_DLQ"),
-		EthereumRPCURL:  os.Getenv("ETHEREUM_RPC_URL"),
-		SocketGateAddr:  os.Getenv("SOCKETGATE_CONTRACT"),
-		ServerPort:      os.Getenv("SERVER_PORT"),
-		ContractABI:     `[{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"token","type":"address"},{"indexed":false,"internalType":"uint256","name":"toChainId","type":"uint256"},{"indexed":false,"internalType":"bytes32","name":"bridgeName","type":"bytes32"},{"indexed":false,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"},{"indexed":false,"internalType":"bytes32","name":"metadata","type":"bytes32"}],"name":"SocketBridge","type":"event"}]`,
-		TopicHex:        os.Getenv("SOCKET_TOPIC_HEX"),
+		PostgresURL:      os.Getenv("DATABASE_URL"),
+		RedisURL:         os.Getenv("REDIS_URL"),
+		RedisStreamName:  os.Getenv("REDIS_STREAM"),
+		RedisStreamDlq:   os.Getenv("REDIS_STREAM_DLQ"),
+		StreamProducerID: getEnvDefault("STREAM_PRODUCER_ID", hostname()),
+		StreamEncoding:   getEnvDefault("STREAM_ENCODING", "json"),
+		EthereumRPCURLs:  ethereumRPCURLs(),
+		SocketGateAddr:   os.Getenv("SOCKETGATE_CONTRACT"),
+		ServerPort:       os.Getenv("SERVER_PORT"),
+		ContractABI:      `[{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"token","type":"address"},{"indexed":false,"internalType":"uint256","name":"toChainId","type":"uint256"},{"indexed":false,"internalType":"bytes32","name":"bridgeName","type":"bytes32"},{"indexed":false,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"},{"indexed":false,"internalType":"bytes32","name":"metadata","type":"bytes32"}],"name":"SocketBridge","type":"event"}]`,
+		TopicHex:         os.Getenv("SOCKET_TOPIC_HEX"),
+		MigrateOnStart:   os.Getenv("MIGRATE_ON_START") != "false",
+		SMTPAddr:         os.Getenv("SMTP_ADDR"),
+		SMTPFrom:         os.Getenv("SMTP_FROM"),
+		SMTPUsername:     os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
+
+		ContractAddresses: getEnvList("CONTRACT_ADDRESSES"),
+		ContractABIFiles:  getEnvList("CONTRACT_ABI_FILES"),
+		EventTopics:       getEnvList("EVENT_TOPICS"),
+
+		ArchiveBackend:       getEnvDefault("ARCHIVE_BACKEND", "fs"),
+		ArchiveDir:           getEnvDefault("ARCHIVE_DIR", "./archive"),
+		ArchiveS3Endpoint:    os.Getenv("ARCHIVE_S3_ENDPOINT"),
+		ArchiveS3Bucket:      os.Getenv("ARCHIVE_S3_BUCKET"),
+		ArchiveS3AccessKey:   os.Getenv("ARCHIVE_S3_ACCESS_KEY"),
+		ArchiveS3SecretKey:   os.Getenv("ARCHIVE_S3_SECRET_KEY"),
+		ArchiveS3UseSSL:      os.Getenv("ARCHIVE_S3_USE_SSL") == "true",
+		ArchiveRetentionDays: getEnvInt("ARCHIVE_RETENTION_DAYS", 90),
+
+		PriceSource:      getEnvDefault("PRICE_SOURCE", "db"),
+		PriceFile:        getEnvDefault("PRICE_FILE", "./prices.csv"),
+		PriceMaxAgeHours: getEnvInt("PRICE_MAX_AGE_HOURS", 24),
+
+		BridgeNames: getEnvList("BRIDGE_NAMES"),
+
+		RPCCheckIntervalSeconds: getEnvInt("RPC_CHECK_INTERVAL_SECONDS", 15),
+		RPCMaxHeadLag:           getEnvInt("RPC_MAX_HEAD_LAG", 3),
+
+		IngestMode:          getEnvDefault("INGEST_MODE", "auto"),
+		PollIntervalSeconds: getEnvInt("POLL_INTERVAL_SECONDS", 12),
+		PollConfirmations:   getEnvInt("POLL_CONFIRMATIONS", 12),
+		PollMaxRange:        getEnvInt("POLL_MAX_RANGE", 2000),
+		PollStartBlock:      getEnvInt("POLL_START_BLOCK", 0),
+
+		TransfersFile:                os.Getenv("TRANSFERS_FILE"),
+		TransferMatchIntervalSeconds: getEnvInt("TRANSFER_MATCH_INTERVAL_SECONDS", 30),
+		TransferMatchRetryMinutes:    getEnvInt("TRANSFER_MATCH_RETRY_MINUTES", 60),
+
+		SLACheckIntervalSeconds: getEnvInt("SLA_CHECK_INTERVAL_SECONDS", 60),
+		SLALookbackHours:        getEnvInt("SLA_LOOKBACK_HOURS", 24),
+		SLAMetricsWindowHours:   getEnvInt("SLA_METRICS_WINDOW_HOURS", 24),
+
+		LifecycleIntervalSeconds: getEnvInt("LIFECYCLE_INTERVAL_SECONDS", 30),
+		LifecycleConfirmations:   getEnvInt("LIFECYCLE_CONFIRMATIONS", 12),
+		LifecycleLookbackHours:   getEnvInt("LIFECYCLE_LOOKBACK_HOURS", 24),
+
+		SourceChainID:                getEnvDefault("SOURCE_CHAIN_ID", "1"),
+		CoverageCheckIntervalSeconds: getEnvInt("COVERAGE_CHECK_INTERVAL_SECONDS", 60),
+		CoverageBackfillAfterMinutes: getEnvInt("COVERAGE_BACKFILL_AFTER_MINUTES", 10),
+
 		CurrencyConfigs: map[string]CurrencyConfig{
 			"ETH":     {Factor: 18, Currency: "ETH"},
 			"USDT":    {Factor: 16, Currency: "USDT"},
 			"DAI":     {Factor: 18, Currency: "DAI"},
 			"BTC":     {Factor: 18, Currency: "BTC"},
-			"DEFAULT": {Factor: 1, Currency: "WEI"},
+			"DEFAULT": {Factor: 0, Currency: "WEI"},
+		},
+		TokenConfigs: map[string]TokenConfig{
+			"0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee": {Symbol: "ETH", Decimals: 18},
+			"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2": {Symbol: "WETH", Decimals: 18},
+			"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": {Symbol: "USDC", Decimals: 6},
+			"0xdac17f958d2ee523a2206206994597c13d831ec7": {Symbol: "USDT", Decimals: 6},
+			"0x6b175474e89094c44da98b954eedeac495271d0f": {Symbol: "DAI", Decimals: 18},
+			"0x2260fac5e5542a773aa44fbcfedf7c193bc2c599": {Symbol: "WBTC", Decimals: 8},
 		},
 	}
 }
@@ -85,3 +238,74 @@ func (c Config) GetCurrencyDetails(currency string) CurrencyConfig {
 
 	return currConf
 }
+
+// ConvertAmount converts a raw WEI amount to the currency exactly, trailing zeros are trimmed.
+// Returns the currency the amount ended up in, which is `WEI` for unsupported currencies
+func (c Config) ConvertAmount(raw string, currency string) (string, CurrencyConfig, error) {
+	currConf := c.GetCurrencyDetails(currency)
+
+	converted, err := decimal.FormatString(raw, currConf.Factor)
+	if err != nil {
+		return "", currConf, err
+	}
+
+	return decimal.Trim(converted), currConf, nil
+}
+
+// GetTokenDetails returns the symbol and decimals of a token by its address
+//
+// Address is case insensitive, ok is false for unknown tokens
+func (c Config) GetTokenDetails(address string) (TokenConfig, bool) {
+	tokenConf, ok := c.TokenConfigs[strings.ToLower(address)]
+	return tokenConf, ok
+}
+
+// getEnvDefault returns the env variable or `fallback` if it's not set
+func getEnvDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// hostname is the host's name, or `producer` if it's unknown
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "producer"
+	}
+	return name
+}
+
+// getEnvList splits a comma separated env variable, nil if it's not set
+func getEnvList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// ethereumRPCURLs reads the comma separated `ETHEREUM_RPC_URLS`,
+// falling back to the single `ETHEREUM_RPC_URL` used before failover
+func ethereumRPCURLs() []string {
+	if urls := getEnvList("ETHEREUM_RPC_URLS"); urls != nil {
+		return urls
+	}
+	return getEnvList("ETHEREUM_RPC_URL")
+}
+
+// getEnvInt returns the env variable as an int or `fallback` if it's not set or invalid
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, using %d", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,153 @@
+// Package db embeds the SQL migrations, so the binary can bring the schema up to date on its own
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Status is the schema version of the database compared to the embedded migrations
+type Status struct {
+	// Current is the applied version, 0 if nothing has been applied
+	Current uint `json:"current"`
+	// Latest is the version of the newest embedded migration
+	Latest uint `json:"latest"`
+	// Dirty means a migration failed half way and has to be fixed by hand
+	Dirty bool `json:"dirty"`
+}
+
+// Behind reports whether there are embedded migrations which haven't been applied
+func (s Status) Behind() bool {
+	return s.Current < s.Latest
+}
+
+// Migrator applies the embedded migrations, it's compatible with the `migrate` CLI
+// as both keep the version in `schema_migrations`.
+//
+// Up and Down hold a postgres advisory lock while running, so replicas starting
+// at the same time apply every migration exactly once
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+}
+
+// migrateLogger routes migrate's progress to the standard logger
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...interface{}) {
+	log.Printf("migrate: "+format, v...)
+}
+
+func (migrateLogger) Verbose() bool {
+	return false
+}
+
+// NewMigrator connects to the database on its own connection, released by Close
+func NewMigrator(databaseURL string) (*Migrator, error) {
+	src, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := pgx.WithInstance(conn, &pgx.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	m.Log = migrateLogger{}
+
+	return &Migrator{migrate: m, source: src}, nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down reverts the last `steps` migrations, every migration if steps is 0
+func (m *Migrator) Down(steps int) error {
+	var err error
+	if steps == 0 {
+		err = m.migrate.Down()
+	} else {
+		err = m.migrate.Steps(-steps)
+	}
+
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Status returns the applied and latest embedded versions
+func (m *Migrator) Status() (Status, error) {
+	var status Status
+
+	latest, err := latestVersion(m.source)
+	if err != nil {
+		return status, err
+	}
+	status.Latest = latest
+
+	status.Current, status.Dirty, err = m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return status, nil
+	}
+
+	return status, err
+}
+
+// Close releases the database connection
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+// latestVersion walks the migrations to the newest one
+func latestVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestVersion(t *testing.T) {
+	src, err := iofs.New(migrationFiles, "migrations")
+	assert.NoError(t, err)
+
+	entries, err := migrationFiles.ReadDir("migrations")
+	assert.NoError(t, err)
+
+	// Migrations are numbered sequentially, so the latest is the number of up migrations
+	var ups uint
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".up.sql") {
+			ups++
+		}
+	}
+
+	latest, err := latestVersion(src)
+	assert.NoError(t, err)
+	assert.Equal(t, ups, latest)
+}
+
+func TestMigrationsArePaired(t *testing.T) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	assert.NoError(t, err)
+
+	files := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		files[entry.Name()] = true
+	}
+
+	// Every migration has to be revertible by `migrate down`
+	for name := range files {
+		if strings.HasSuffix(name, ".up.sql") {
+			assert.True(t, files[strings.TrimSuffix(name, ".up.sql")+".down.sql"], "%s has no down migration", name)
+		}
+	}
+}
+
+func TestStatusBehind(t *testing.T) {
+	assert.True(t, Status{Current: 7, Latest: 9}.Behind())
+	assert.False(t, Status{Current: 9, Latest: 9}.Behind())
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_route_timestamp;
+
+ALTER TABLE bridge_events
+    DROP COLUMN IF EXISTS dest_chain_id,
+    DROP COLUMN IF EXISTS bridge_name;
@@ -0,0 +1,5 @@
+ALTER TABLE bridge_events
+    ADD COLUMN IF NOT EXISTS dest_chain_id VARCHAR(78) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bridge_name VARCHAR(66) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_route_timestamp ON bridge_events (token, dest_chain_id, bridge_name, timestamp);
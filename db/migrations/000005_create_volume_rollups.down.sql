@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS bridge_volume_daily;
+DROP TABLE IF EXISTS bridge_volume_hourly;
+
+DROP INDEX IF EXISTS idx_uniq_tx_log;
+
+ALTER TABLE bridge_events
+    DROP COLUMN IF EXISTS block_number,
+    DROP COLUMN IF EXISTS log_index;
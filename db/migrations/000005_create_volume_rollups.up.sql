@@ -0,0 +1,38 @@
+ALTER TABLE bridge_events
+    ADD COLUMN IF NOT EXISTS block_number BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS log_index INTEGER NOT NULL DEFAULT 0;
+
+-- Events saved before block_number was tracked can't be told apart, so they're left out
+CREATE UNIQUE INDEX IF NOT EXISTS idx_uniq_tx_log ON bridge_events (transaction_hash, log_index) WHERE block_number > 0;
+
+CREATE TABLE IF NOT EXISTS bridge_volume_hourly (
+    bucket TIMESTAMP NOT NULL,
+    token VARCHAR(100) NOT NULL,
+    dest_chain_id VARCHAR(78) NOT NULL,
+    bridge_name VARCHAR(66) NOT NULL,
+    event_count BIGINT NOT NULL DEFAULT 0,
+    total_amount NUMERIC NOT NULL DEFAULT 0,
+    PRIMARY KEY (bucket, token, dest_chain_id, bridge_name)
+);
+
+CREATE TABLE IF NOT EXISTS bridge_volume_daily (
+    bucket TIMESTAMP NOT NULL,
+    token VARCHAR(100) NOT NULL,
+    dest_chain_id VARCHAR(78) NOT NULL,
+    bridge_name VARCHAR(66) NOT NULL,
+    event_count BIGINT NOT NULL DEFAULT 0,
+    total_amount NUMERIC NOT NULL DEFAULT 0,
+    PRIMARY KEY (bucket, token, dest_chain_id, bridge_name)
+);
+
+INSERT INTO bridge_volume_hourly (bucket, token, dest_chain_id, bridge_name, event_count, total_amount)
+SELECT date_trunc('hour', timestamp), token, dest_chain_id, bridge_name, COUNT(*), SUM(amount)
+FROM bridge_events
+GROUP BY 1, 2, 3, 4
+ON CONFLICT DO NOTHING;
+
+INSERT INTO bridge_volume_daily (bucket, token, dest_chain_id, bridge_name, event_count, total_amount)
+SELECT date_trunc('day', timestamp), token, dest_chain_id, bridge_name, COUNT(*), SUM(amount)
+FROM bridge_events
+GROUP BY 1, 2, 3, 4
+ON CONFLICT DO NOTHING;
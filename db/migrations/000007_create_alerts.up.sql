@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    token VARCHAR(100) NOT NULL DEFAULT '',
+    min_amount NUMERIC(78, 0) NULL,
+    addresses TEXT NOT NULL DEFAULT '',
+    dest_chain_id VARCHAR(78) NOT NULL DEFAULT '',
+    bridge_name VARCHAR(66) NOT NULL DEFAULT '',
+    notifier VARCHAR(20) NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id SERIAL PRIMARY KEY,
+    rule_id INTEGER NOT NULL,
+    rule_name VARCHAR(100) NOT NULL,
+    event_id INTEGER NOT NULL,
+    transaction_hash VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    notifier VARCHAR(20) NOT NULL,
+    notified BOOLEAN NOT NULL DEFAULT FALSE,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Rules may be deleted, history is kept, hence no foreign key on rule_id
+CREATE INDEX IF NOT EXISTS idx_alerts_rule ON alerts (rule_id, id DESC);
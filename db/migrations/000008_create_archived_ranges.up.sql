@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS archived_ranges (
+    id SERIAL PRIMARY KEY,
+    range_start TIMESTAMP NOT NULL,
+    range_end TIMESTAMP NOT NULL,
+    object_key TEXT NOT NULL UNIQUE,
+    row_count BIGINT NOT NULL,
+    min_event_id INTEGER NOT NULL,
+    max_event_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_ranges_start ON archived_ranges (range_start);
@@ -0,0 +1,33 @@
+ALTER TABLE bridge_events RENAME TO bridge_events_partitioned;
+ALTER TABLE bridge_events_partitioned RENAME CONSTRAINT bridge_events_pkey TO bridge_events_partitioned_pkey;
+DROP INDEX IF EXISTS idx_rev_id;
+DROP INDEX IF EXISTS idx_rev_timestamp;
+DROP INDEX IF EXISTS idx_route_timestamp;
+
+CREATE TABLE bridge_events (
+    id INTEGER NOT NULL DEFAULT nextval('bridge_events_id_seq') PRIMARY KEY,
+    token VARCHAR(100) NOT NULL,
+    amount NUMERIC(78, 0) NOT NULL,
+    from_chain VARCHAR(100) NOT NULL,
+    to_chain VARCHAR(100) NOT NULL,
+    transaction_hash VARCHAR(255) NOT NULL,
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dest_chain_id VARCHAR(78) NOT NULL DEFAULT '',
+    bridge_name VARCHAR(66) NOT NULL DEFAULT '',
+    block_number BIGINT NOT NULL DEFAULT 0,
+    log_index INTEGER NOT NULL DEFAULT 0
+);
+
+ALTER SEQUENCE bridge_events_id_seq OWNED BY bridge_events.id;
+
+INSERT INTO bridge_events (id, token, amount, from_chain, to_chain, transaction_hash, timestamp, dest_chain_id, bridge_name, block_number, log_index)
+SELECT id, token, amount, from_chain, to_chain, transaction_hash, timestamp, dest_chain_id, bridge_name, block_number, log_index
+FROM bridge_events_partitioned;
+
+DROP TABLE bridge_events_partitioned;
+DROP TABLE IF EXISTS bridge_event_keys;
+
+CREATE INDEX idx_rev_id ON bridge_events (id DESC);
+CREATE INDEX idx_rev_timestamp ON bridge_events (timestamp DESC);
+CREATE INDEX idx_route_timestamp ON bridge_events (token, dest_chain_id, bridge_name, timestamp);
+CREATE UNIQUE INDEX idx_uniq_tx_log ON bridge_events (transaction_hash, log_index) WHERE block_number > 0;
@@ -0,0 +1,73 @@
+-- bridge_events becomes a table partitioned by month on `timestamp`,
+-- existing rows are moved into it and the old table is dropped
+
+ALTER TABLE bridge_events RENAME TO bridge_events_unpartitioned;
+ALTER TABLE bridge_events_unpartitioned RENAME CONSTRAINT bridge_events_pkey TO bridge_events_unpartitioned_pkey;
+DROP INDEX IF EXISTS idx_rev_id;
+DROP INDEX IF EXISTS idx_rev_timestamp;
+DROP INDEX IF EXISTS idx_route_timestamp;
+DROP INDEX IF EXISTS idx_uniq_tx_log;
+
+-- Primary key of a partitioned table has to include the partition key,
+-- ids keep coming from the same sequence so they stay unique on their own
+CREATE TABLE bridge_events (
+    id INTEGER NOT NULL DEFAULT nextval('bridge_events_id_seq'),
+    token VARCHAR(100) NOT NULL,
+    amount NUMERIC(78, 0) NOT NULL,
+    from_chain VARCHAR(100) NOT NULL,
+    to_chain VARCHAR(100) NOT NULL,
+    transaction_hash VARCHAR(255) NOT NULL,
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dest_chain_id VARCHAR(78) NOT NULL DEFAULT '',
+    bridge_name VARCHAR(66) NOT NULL DEFAULT '',
+    block_number BIGINT NOT NULL DEFAULT 0,
+    log_index INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+ALTER SEQUENCE bridge_events_id_seq OWNED BY bridge_events.id;
+
+CREATE INDEX idx_rev_id ON bridge_events (id DESC);
+CREATE INDEX idx_rev_timestamp ON bridge_events (timestamp DESC);
+CREATE INDEX idx_route_timestamp ON bridge_events (token, dest_chain_id, bridge_name, timestamp);
+
+-- Catches rows no monthly partition exists for, partitions are created ahead
+-- of time by the partition maintainer so it's expected to stay empty
+CREATE TABLE bridge_events_default PARTITION OF bridge_events DEFAULT;
+
+-- One partition per month holding data, up to 3 months ahead
+DO $$
+DECLARE
+    partition_month DATE;
+BEGIN
+    SELECT date_trunc('month', COALESCE(MIN(timestamp), CURRENT_TIMESTAMP))::date INTO partition_month FROM bridge_events_unpartitioned;
+
+    WHILE partition_month <= (date_trunc('month', CURRENT_TIMESTAMP) + INTERVAL '3 months')::date LOOP
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF bridge_events FOR VALUES FROM (%L) TO (%L)',
+            'bridge_events_p' || to_char(partition_month, 'YYYYMM'),
+            partition_month,
+            (partition_month + INTERVAL '1 month')::date
+        );
+        partition_month := (partition_month + INTERVAL '1 month')::date;
+    END LOOP;
+END $$;
+
+-- Uniqueness of (transaction_hash, log_index) can't be enforced across partitions as
+-- it doesn't include the partition key, keys are claimed in this table instead.
+-- Keys of archived events are kept, so they're never ingested again
+CREATE TABLE IF NOT EXISTS bridge_event_keys (
+    transaction_hash VARCHAR(255) NOT NULL,
+    log_index INTEGER NOT NULL,
+    PRIMARY KEY (transaction_hash, log_index)
+);
+
+INSERT INTO bridge_event_keys (transaction_hash, log_index)
+SELECT transaction_hash, log_index FROM bridge_events_unpartitioned WHERE block_number > 0
+ON CONFLICT DO NOTHING;
+
+INSERT INTO bridge_events (id, token, amount, from_chain, to_chain, transaction_hash, timestamp, dest_chain_id, bridge_name, block_number, log_index)
+SELECT id, token, amount, from_chain, to_chain, transaction_hash, timestamp, dest_chain_id, bridge_name, block_number, log_index
+FROM bridge_events_unpartitioned;
+
+DROP TABLE bridge_events_unpartitioned;
@@ -0,0 +1,13 @@
+ALTER TABLE bridge_volume_daily
+    DROP COLUMN IF EXISTS unpriced_count,
+    DROP COLUMN IF EXISTS total_usd;
+
+ALTER TABLE bridge_volume_hourly
+    DROP COLUMN IF EXISTS unpriced_count,
+    DROP COLUMN IF EXISTS total_usd;
+
+ALTER TABLE bridge_events
+    DROP COLUMN IF EXISTS usd_value,
+    DROP COLUMN IF EXISTS block_timestamp;
+
+DROP TABLE IF EXISTS token_prices;
@@ -0,0 +1,25 @@
+-- Historical USD prices, one row per symbol and point in time, see `internal/pricing`
+CREATE TABLE IF NOT EXISTS token_prices (
+    symbol VARCHAR(20) NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    usd_price NUMERIC NOT NULL,
+    PRIMARY KEY (symbol, timestamp)
+);
+
+-- Both are NULL for events saved before valuation, or without a known price
+ALTER TABLE bridge_events
+    ADD COLUMN IF NOT EXISTS block_timestamp TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS usd_value NUMERIC;
+
+-- unpriced_count keeps track of events missing from total_usd, so partial totals can be told apart
+ALTER TABLE bridge_volume_hourly
+    ADD COLUMN IF NOT EXISTS total_usd NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS unpriced_count BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE bridge_volume_daily
+    ADD COLUMN IF NOT EXISTS total_usd NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS unpriced_count BIGINT NOT NULL DEFAULT 0;
+
+-- No existing event has a USD value yet
+UPDATE bridge_volume_hourly SET unpriced_count = event_count;
+UPDATE bridge_volume_daily SET unpriced_count = event_count;
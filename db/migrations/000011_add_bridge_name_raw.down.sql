@@ -0,0 +1,4 @@
+-- Decoded names are lost, bridge_name goes back to the hex encoded value
+UPDATE bridge_events SET bridge_name = bridge_name_raw WHERE bridge_name_raw <> '';
+
+ALTER TABLE bridge_events DROP COLUMN IF EXISTS bridge_name_raw;
@@ -0,0 +1,5 @@
+-- bridge_name used to be the hex encoded bytes32 as emitted, it's kept as is in bridge_name_raw
+-- and bridge_name becomes the decoded name. Existing rows are decoded by `bridges redecode`
+ALTER TABLE bridge_events ADD COLUMN IF NOT EXISTS bridge_name_raw VARCHAR(66) NOT NULL DEFAULT '';
+
+UPDATE bridge_events SET bridge_name_raw = bridge_name WHERE bridge_name_raw = '';
@@ -0,0 +1,6 @@
+ALTER TABLE bridge_events
+    DROP COLUMN IF EXISTS tx_to,
+    DROP COLUMN IF EXISTS tx_from,
+    DROP COLUMN IF EXISTS tx_status,
+    DROP COLUMN IF EXISTS effective_gas_price,
+    DROP COLUMN IF EXISTS gas_used;
@@ -0,0 +1,7 @@
+-- Taken from the transaction receipt of the event, NULL if it couldn't be fetched
+ALTER TABLE bridge_events
+    ADD COLUMN IF NOT EXISTS gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS effective_gas_price NUMERIC(78, 0),
+    ADD COLUMN IF NOT EXISTS tx_status SMALLINT,
+    ADD COLUMN IF NOT EXISTS tx_from VARCHAR(42),
+    ADD COLUMN IF NOT EXISTS tx_to VARCHAR(42);
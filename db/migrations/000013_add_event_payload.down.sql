@@ -0,0 +1,9 @@
+-- Events other than SocketBridge have no amount, they can't be kept
+DELETE FROM bridge_events WHERE event_name <> 'SocketBridge';
+
+DROP INDEX IF EXISTS idx_event_name_id;
+
+ALTER TABLE bridge_events
+    ALTER COLUMN amount SET NOT NULL,
+    DROP COLUMN IF EXISTS payload,
+    DROP COLUMN IF EXISTS event_name;
@@ -0,0 +1,9 @@
+-- Events other than SocketBridge are kept as their decoded arguments in payload,
+-- their typed columns are left empty and amount NULL. Existing rows are SocketBridge
+-- events and keep a NULL payload, it can't be rebuilt as metadata was never stored
+ALTER TABLE bridge_events
+    ADD COLUMN IF NOT EXISTS event_name VARCHAR(100) NOT NULL DEFAULT 'SocketBridge',
+    ADD COLUMN IF NOT EXISTS payload JSONB,
+    ALTER COLUMN amount DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_event_name_id ON bridge_events (event_name, id DESC);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS bridge_fills;
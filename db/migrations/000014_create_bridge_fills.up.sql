@@ -0,0 +1,26 @@
+-- Destination legs of transfers, events bridges emit once funds are released on the
+-- destination chain. source_event_id is the SocketBridge event a fill completes, it isn't
+-- a foreign key as ids of partitioned bridge_events are only unique along with timestamp
+CREATE TABLE IF NOT EXISTS bridge_fills (
+    id SERIAL PRIMARY KEY,
+    chain_id VARCHAR(78) NOT NULL,
+    bridge_name VARCHAR(66) NOT NULL,
+    event_name VARCHAR(100) NOT NULL,
+    transaction_hash VARCHAR(255) NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number BIGINT NOT NULL DEFAULT 0,
+    block_timestamp TIMESTAMP,
+    receiver VARCHAR(100) NOT NULL DEFAULT '',
+    amount NUMERIC(78, 0),
+    deposit_id VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB,
+    source_event_id INTEGER,
+    latency_ms BIGINT,
+    matched_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fill_chain_tx_log ON bridge_fills (chain_id, transaction_hash, log_index);
+-- A transfer is completed by a single fill
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fill_source_event ON bridge_fills (source_event_id);
+CREATE INDEX IF NOT EXISTS idx_fill_unmatched ON bridge_fills (created_at) WHERE source_event_id IS NULL;
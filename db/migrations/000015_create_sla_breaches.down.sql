@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_fill_matched_at;
+DROP TABLE IF EXISTS sla_breaches;
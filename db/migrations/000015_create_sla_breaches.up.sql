@@ -0,0 +1,20 @@
+-- Transfers which weren't completed within the SLA of their route, resolved once they complete
+CREATE TABLE IF NOT EXISTS sla_breaches (
+    id SERIAL PRIMARY KEY,
+    source_event_id INTEGER NOT NULL,
+    transaction_hash VARCHAR(255) NOT NULL,
+    bridge_name VARCHAR(66) NOT NULL,
+    dest_chain_id VARCHAR(78) NOT NULL,
+    token VARCHAR(100) NOT NULL,
+    sla_seconds BIGINT NOT NULL,
+    source_timestamp TIMESTAMP NOT NULL,
+    detected_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    latency_ms BIGINT
+);
+
+-- A transfer breaches its SLA once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_breach_source_event ON sla_breaches (source_event_id);
+CREATE INDEX IF NOT EXISTS idx_breach_open ON sla_breaches (id DESC) WHERE resolved_at IS NULL;
+-- Completion metrics are computed over recently matched fills
+CREATE INDEX IF NOT EXISTS idx_fill_matched_at ON bridge_fills (matched_at) WHERE matched_at IS NOT NULL;
@@ -0,0 +1,31 @@
+-- Lifecycle of transfers, kept apart from their SocketBridge events. source_event_id isn't
+-- a foreign key as ids of partitioned bridge_events are only unique along with timestamp
+CREATE TABLE IF NOT EXISTS transfers (
+    id SERIAL PRIMARY KEY,
+    source_event_id INTEGER NOT NULL,
+    transaction_hash VARCHAR(255) NOT NULL,
+    log_index INTEGER NOT NULL,
+    bridge_name VARCHAR(66) NOT NULL,
+    dest_chain_id VARCHAR(78) NOT NULL,
+    block_number BIGINT NOT NULL DEFAULT 0,
+    state VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_source_event ON transfers (source_event_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_tx ON transfers (transaction_hash);
+-- Transfers which aren't completed or refunded yet are followed
+CREATE INDEX IF NOT EXISTS idx_transfer_open ON transfers (state, id) WHERE state NOT IN ('completed', 'refunded');
+
+-- Audit history of every state a transfer went through
+CREATE TABLE IF NOT EXISTS transfer_transitions (
+    id SERIAL PRIMARY KEY,
+    transfer_id INTEGER NOT NULL REFERENCES transfers (id) ON DELETE CASCADE,
+    from_state VARCHAR(20) NOT NULL DEFAULT '',
+    to_state VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transition_transfer ON transfer_transitions (transfer_id, id);
@@ -0,0 +1,2 @@
+-- Reconciliation reads stored events by block range
+CREATE INDEX IF NOT EXISTS idx_block_log ON bridge_events (block_number, log_index);
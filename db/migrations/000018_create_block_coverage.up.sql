@@ -0,0 +1,11 @@
+-- Ranges of blocks per chain whose logs were all ingested, holes between them are gaps
+CREATE TABLE IF NOT EXISTS block_coverage (
+    id SERIAL PRIMARY KEY,
+    chain_id VARCHAR(78) NOT NULL,
+    from_block BIGINT NOT NULL,
+    to_block BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_coverage_chain_block ON block_coverage (chain_id, from_block);
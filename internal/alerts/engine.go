@@ -0,0 +1,180 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+const (
+	// rulesCacheTTL is how long active rules are cached before being reloaded
+	rulesCacheTTL = 30 * time.Second
+	// notifyTimeout bounds a single notification
+	notifyTimeout = 10 * time.Second
+	// queueSize is the number of pending matches buffered before new ones are dropped
+	queueSize = 1000
+)
+
+// Engine evaluates alert rules against every consumed event, records matches
+// as alerts and notifies about them using the notifier configured on the rule.
+//
+// It's fed by the consumer as an event listener, evaluation happens inline while
+// alerts are saved and notifications sent from a background worker, so consuming is never held up
+type Engine struct {
+	repo      repositories.AlertRepository
+	notifiers map[string]Notifier
+	queue     chan Notification
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+
+	cacheMu       sync.Mutex
+	cachedRules   []models.AlertRule
+	cacheLoadedAt time.Time
+}
+
+func NewEngine(repo repositories.AlertRepository, notifiers ...Notifier) *Engine {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	engine := &Engine{
+		repo:      repo,
+		notifiers: make(map[string]Notifier),
+		queue:     make(chan Notification, queueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	for _, notifier := range notifiers {
+		engine.notifiers[notifier.Name()] = notifier
+	}
+
+	return engine
+}
+
+// HasNotifier reports whether a notifier with the name is registered
+func (e *Engine) HasNotifier(name string) bool {
+	_, ok := e.notifiers[name]
+	return ok
+}
+
+// Start spawns the notification worker
+func (e *Engine) Start() {
+	e.wg.Add(1)
+	go e.work()
+}
+
+// Stop abandons pending notifications and waits for the in flight one
+func (e *Engine) Stop() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+// OnEventSaved evaluates every active rule against the event and enqueues matches,
+// they are recorded as alerts by the worker so a slow database doesn't hold up consuming
+func (e *Engine) OnEventSaved(event models.BridgeEvent) {
+	for _, rule := range e.activeRules() {
+		if !Matches(rule, event) {
+			continue
+		}
+
+		select {
+		case e.queue <- Notification{Rule: rule, Event: event}:
+		default:
+			log.Printf("Alert queue is full, match of rule %d for event %d is dropped", rule.ID, event.ID)
+		}
+	}
+}
+
+// activeRules returns active rules, cached for `rulesCacheTTL`
+func (e *Engine) activeRules() []models.AlertRule {
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+
+	if time.Since(e.cacheLoadedAt) < rulesCacheTTL {
+		return e.cachedRules
+	}
+
+	rules, err := e.repo.ListActiveRules()
+	if err != nil {
+		// Keep evaluating stale rules rather than missing alerts
+		log.Printf("Error loading active alert rules: %v", err)
+		return e.cachedRules
+	}
+
+	e.cachedRules = rules
+	e.cacheLoadedAt = time.Now()
+	return rules
+}
+
+func (e *Engine) work() {
+	defer e.wg.Done()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case notification := <-e.queue:
+			if e.record(&notification) {
+				e.notify(notification)
+			}
+		}
+	}
+}
+
+// record saves the match of the notification as an alert, reports whether it was saved
+func (e *Engine) record(notification *Notification) bool {
+	rule, event := notification.Rule, notification.Event
+
+	notification.Alert = models.Alert{
+		RuleID:          rule.ID,
+		RuleName:        rule.Name,
+		EventID:         event.ID,
+		TransactionHash: event.TransactionHash,
+		Message:         describeMatch(rule, event),
+		Notifier:        rule.Notifier,
+	}
+	if err := e.repo.CreateAlert(&notification.Alert); err != nil {
+		log.Printf("Error saving alert of rule %d for event %d: %v", rule.ID, event.ID, err)
+		return false
+	}
+
+	return true
+}
+
+// notify sends the notification and records the outcome on the alert
+func (e *Engine) notify(notification Notification) {
+	alert := notification.Alert
+
+	notifier, ok := e.notifiers[notification.Rule.Notifier]
+	if !ok {
+		alert.Error = fmt.Sprintf("unknown notifier %q", notification.Rule.Notifier)
+	} else {
+		ctx, cancel := context.WithTimeout(e.ctx, notifyTimeout)
+		err := notifier.Notify(ctx, notification)
+		cancel()
+
+		if err != nil {
+			alert.Error = err.Error()
+		} else {
+			alert.Notified = true
+		}
+	}
+
+	if alert.Error != "" {
+		log.Printf("Error notifying alert %d: %s", alert.ID, alert.Error)
+	}
+
+	if err := e.repo.UpdateAlertNotification(&alert); err != nil {
+		log.Printf("Error updating alert %d: %v", alert.ID, err)
+	}
+}
+
+// describeMatch builds a human readable alert message
+func describeMatch(rule models.AlertRule, event models.BridgeEvent) string {
+	return fmt.Sprintf("Rule %q matched event %d: %s WEI of %s from %s to %s on chain %s",
+		rule.Name, event.ID, event.Amount, event.Token, event.FromChain, event.ToChain, event.DestChainID)
+}
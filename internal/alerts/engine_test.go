@@ -0,0 +1,75 @@
+package alerts_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/alerts"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAlertRepository struct {
+	repositories.AlertRepository
+	rules   []models.AlertRule
+	created chan models.Alert
+	updated chan models.Alert
+}
+
+func (r *fakeAlertRepository) ListActiveRules() ([]models.AlertRule, error) {
+	return r.rules, nil
+}
+
+func (r *fakeAlertRepository) CreateAlert(alert *models.Alert) error {
+	alert.ID = 1
+	r.created <- *alert
+	return nil
+}
+
+func (r *fakeAlertRepository) UpdateAlertNotification(alert *models.Alert) error {
+	r.updated <- *alert
+	return nil
+}
+
+type stubNotifier struct{}
+
+func (stubNotifier) Name() string {
+	return "log"
+}
+
+func (stubNotifier) Notify(context.Context, alerts.Notification) error {
+	return nil
+}
+
+func TestEngine_SavesAlertsInWorker(t *testing.T) {
+	repo := &fakeAlertRepository{
+		rules:   []models.AlertRule{{ID: 3, Name: "hop", BridgeName: "hop", Notifier: "log", Active: true}},
+		created: make(chan models.Alert, 1),
+		updated: make(chan models.Alert, 1),
+	}
+	engine := alerts.NewEngine(repo, stubNotifier{})
+
+	engine.OnEventSaved(models.BridgeEvent{ID: 9, EventName: models.EventSocketBridge, BridgeName: "hop"})
+
+	// Nothing is saved inline
+	assert.Empty(t, repo.created)
+
+	engine.Start()
+	defer engine.Stop()
+
+	select {
+	case alert := <-repo.created:
+		assert.Equal(t, 3, alert.RuleID)
+		assert.Equal(t, 9, alert.EventID)
+	case <-time.After(time.Second):
+		t.Fatal("alert wasn't saved")
+	}
+	select {
+	case alert := <-repo.updated:
+		assert.True(t, alert.Notified)
+	case <-time.After(time.Second):
+		t.Fatal("alert wasn't notified")
+	}
+}
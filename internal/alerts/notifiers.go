@@ -0,0 +1,114 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+)
+
+// Notification is what notifiers are asked to deliver for every rule match
+type Notification struct {
+	Rule  models.AlertRule   `json:"rule"`
+	Alert models.Alert       `json:"alert"`
+	Event models.BridgeEvent `json:"event"`
+}
+
+// Notifier delivers alert notifications, implementations are registered
+// on the Engine by name and selected per rule using `AlertRule.Notifier`
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier writes alerts to the application log, useful for local setups
+type LogNotifier struct{}
+
+func (LogNotifier) Name() string {
+	return "log"
+}
+
+func (LogNotifier) Notify(_ context.Context, notification Notification) error {
+	log.Printf("ALERT [%s]: %s", notification.Rule.Name, notification.Alert.Message)
+	return nil
+}
+
+// WebhookNotifier POSTs the notification as JSON to the rule's target URL
+type WebhookNotifier struct {
+	Client *http.Client
+}
+
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{Client: &http.Client{Timeout: timeout}}
+}
+
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notification.Rule.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// headerReplacer strips line breaks from values written into email headers
+var headerReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+// EmailNotifier sends a plain text email to the rule's target address through an SMTP relay,
+// locally a stand-in like MailHog can be used
+type EmailNotifier struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+func (n *EmailNotifier) Notify(_ context.Context, notification Notification) error {
+	if n.Addr == "" {
+		return fmt.Errorf("SMTP_ADDR is not configured")
+	}
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		host := strings.Split(n.Addr, ":")[0]
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+
+	// Line breaks would let the rule name inject headers, names are validated but older rules may have them
+	subject := fmt.Sprintf("Bridge alert: %s", headerReplacer.Replace(notification.Rule.Name))
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n\r\nTransaction: %s\r\n",
+		n.From, notification.Rule.Target, subject, notification.Alert.Message, notification.Event.TransactionHash)
+
+	return smtp.SendMail(n.Addr, auth, n.From, []string{notification.Rule.Target}, []byte(message))
+}
@@ -0,0 +1,69 @@
+package alerts
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/eth-bridging/internal/models"
+)
+
+// Matches reports whether every non empty condition of the rule holds for the event
+//
+// A rule without any condition never matches, so a half configured rule can't flood notifiers
+func Matches(rule models.AlertRule, event models.BridgeEvent) bool {
+	conditions := 0
+
+	if rule.Token != "" {
+		conditions++
+		if !strings.EqualFold(rule.Token, event.Token) {
+			return false
+		}
+	}
+
+	if rule.MinAmount != nil && *rule.MinAmount != "" {
+		conditions++
+		minAmount, ok := new(big.Int).SetString(*rule.MinAmount, 10)
+		if !ok {
+			return false
+		}
+		amount, ok := new(big.Int).SetString(event.Amount, 10)
+		if !ok || amount.Cmp(minAmount) < 0 {
+			return false
+		}
+	}
+
+	if addresses := rule.AddressList(); len(addresses) > 0 {
+		conditions++
+		if !containsAddress(addresses, event.FromChain) && !containsAddress(addresses, event.ToChain) {
+			return false
+		}
+	}
+
+	if rule.DestChainID != "" {
+		conditions++
+		if rule.DestChainID != event.DestChainID {
+			return false
+		}
+	}
+
+	if rule.BridgeName != "" {
+		conditions++
+		if rule.BridgeName != event.BridgeName {
+			return false
+		}
+	}
+
+	return conditions > 0
+}
+
+// containsAddress checks the address against a lowercase watchlist
+func containsAddress(addresses []string, address string) bool {
+	address = strings.ToLower(address)
+	for _, watched := range addresses {
+		if watched == address {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package alerts_test
+
+import (
+	"testing"
+
+	"github.com/eth-bridging/internal/alerts"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func amount(value string) *string {
+	return &value
+}
+
+func TestMatches(t *testing.T) {
+	event := models.BridgeEvent{
+		ID:          1,
+		Token:       "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Amount:      "5000000000",
+		FromChain:   "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+		ToChain:     "0x0e186b704783Ba103eE32723084eef498475d50B",
+		DestChainID: "10",
+		BridgeName:  "0xhop",
+	}
+
+	tests := []struct {
+		name     string
+		rule     models.AlertRule
+		expected bool
+	}{
+		{
+			name:     "threshold reached for token",
+			rule:     models.AlertRule{Token: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", MinAmount: amount("1000000000")},
+			expected: true,
+		},
+		{
+			name:     "threshold not reached",
+			rule:     models.AlertRule{Token: event.Token, MinAmount: amount("5000000001")},
+			expected: false,
+		},
+		{
+			name:     "threshold for another token",
+			rule:     models.AlertRule{Token: "0x6B175474E89094C44Da98b954EedeAC495271d0F", MinAmount: amount("1")},
+			expected: false,
+		},
+		{
+			name:     "watched receiver",
+			rule:     models.AlertRule{Addresses: "0x1111111111111111111111111111111111111111, 0x0E186B704783BA103EE32723084EEF498475D50B"},
+			expected: true,
+		},
+		{
+			name:     "unwatched addresses",
+			rule:     models.AlertRule{Addresses: "0x1111111111111111111111111111111111111111"},
+			expected: false,
+		},
+		{
+			name:     "destination chain and bridge",
+			rule:     models.AlertRule{DestChainID: "10", BridgeName: "0xhop"},
+			expected: true,
+		},
+		{
+			name:     "bridge mismatch",
+			rule:     models.AlertRule{DestChainID: "10", BridgeName: "0xstargate"},
+			expected: false,
+		},
+		{
+			name:     "rule without conditions",
+			rule:     models.AlertRule{},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, alerts.Matches(test.rule, event))
+		})
+	}
+}
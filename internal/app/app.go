@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -11,9 +12,9 @@ import (
 	"time"
 
 	"github.com/eth-bridging/config"
-	"github.com/eth-bridging/internal/consumer"
-	"github.com/eth-bridging/internal/producer"
+	"github.com/eth-bridging/db"
 	"github.com/eth-bridging/internal/routers"
+	"github.com/eth-bridging/internal/transfers"
 	"github.com/eth-bridging/pkg/di"
 	ethereum "github.com/eth-bridging/pkg/go-eth"
 )
@@ -22,13 +23,33 @@ func Run() {
 	cfg := config.LoadConfig()
 	wg := &sync.WaitGroup{}
 
-	ethClient, err := ethereum.NewEthereumClient(cfg.EthereumRPCURL, cfg.SocketGateAddr, cfg.ContractABI, cfg.TopicHex)
+	// Schema has to be up to date before anything touches the database
+	if err := ensureSchema(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	poolOptions := ethereum.DefaultPoolOptions
+	poolOptions.CheckInterval = time.Duration(cfg.RPCCheckIntervalSeconds) * time.Second
+	poolOptions.MaxHeadLag = uint64(cfg.RPCMaxHeadLag)
+
+	ethClient, err := newEthereumClient(cfg, poolOptions)
 	if err != nil {
-		log.Fatalf("Failed to initialize Ethereum client: %v", err)
+		log.Fatal(err)
+	}
+	ethClient.Endpoints().Start()
+
+	// Fills of tracked bridges are read from their destination chains
+	tracked, err := transfers.LoadSpec(cfg.TransfersFile)
+	if err != nil {
+		log.Fatalf("Invalid TRANSFERS_FILE: %v", err)
+	}
+	destinations, err := transfers.DialDestinations(tracked, poolOptions)
+	if err != nil {
+		log.Fatalf("Failed to connect to destination chains: %v", err)
 	}
 
 	// Initialize the DI container
-	container := di.InitializeContainer(cfg, ethClient, wg)
+	container := di.InitializeContainer(cfg, ethClient, tracked, destinations, wg)
 
 	// Initialize Router
 	router := routers.SetupRouter(container)
@@ -47,13 +68,75 @@ func Run() {
 		wg.Done()
 	}()
 
-	go GracefulShutdown(server, &container.Consumer, &container.Producer)
+	go GracefulShutdown(server, container)
 	wg.Wait()
 }
 
+// newEthereumClient connects to `ETHEREUM_RPC_URLS` and watches the configured contracts and events,
+// health checks of the endpoints aren't started
+func newEthereumClient(cfg *config.Config, poolOptions ethereum.PoolOptions) (*ethereum.EthereumClient, error) {
+	bridgeNames, err := ethereum.NewBridgeNameDecoder(cfg.BridgeNames)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BRIDGE_NAMES: %w", err)
+	}
+
+	endpoints, err := ethereum.NewEndpointPool(cfg.EthereumRPCURLs, poolOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Ethereum client: %w", err)
+	}
+
+	contractABIs, err := ethereum.ReadABIFiles(cfg.ContractABIFiles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CONTRACT_ABI_FILES: %w", err)
+	}
+
+	ethClient, err := ethereum.NewEthereumClient(
+		endpoints,
+		append([]string{cfg.SocketGateAddr}, cfg.ContractAddresses...),
+		append([]string{cfg.ContractABI}, contractABIs...),
+		append([]string{cfg.TopicHex}, cfg.EventTopics...),
+		bridgeNames,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Ethereum client: %w", err)
+	}
+
+	return ethClient, nil
+}
+
+// ensureSchema applies pending migrations if `MIGRATE_ON_START` is enabled,
+// otherwise it refuses to continue while the schema is behind the binary
+func ensureSchema(cfg *config.Config) error {
+	migrator, err := db.NewMigrator(cfg.PostgresURL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize migrations: %w", err)
+	}
+	defer migrator.Close()
+
+	if cfg.MigrateOnStart {
+		if err := migrator.Up(); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if status.Dirty {
+		return fmt.Errorf("schema version %d is dirty, fix it by hand and run `migrate up`", status.Current)
+	}
+	if status.Behind() {
+		return fmt.Errorf("schema version %d is behind %d, run `migrate up` first", status.Current, status.Latest)
+	}
+
+	log.Printf("Schema is at version %d", status.Current)
+	return nil
+}
+
 // GracefulShutdown: Handles stopping the server, producer, and consumer gracefully
 // to ensure no abrupt server stopping during deployments
-func GracefulShutdown(server *http.Server, consumer *consumer.RedisStreamConsumer, producer *producer.RedisProducer) {
+func GracefulShutdown(server *http.Server, container *di.Container) {
 	// Create a channel to listen for OS signals (e.g., CTRL+C)
 	stopSignal := make(chan os.Signal, 1)
 	signal.Notify(stopSignal, syscall.SIGINT, syscall.SIGTERM)
@@ -62,11 +145,35 @@ func GracefulShutdown(server *http.Server, consumer *consumer.RedisStreamConsume
 	sig := <-stopSignal
 	log.Printf("Received shutdown signal: %+v, initiating graceful shutdown...", sig)
 
+	// Stop backfilling coverage gaps, backfilled events are published through the producer
+	container.Coverage.Stop()
+
 	// Stop the consumer gracefully
-	consumer.Stop()
+	container.Consumer.Stop()
 
 	// Stop the producer gracefully
-	producer.Stop()
+	container.Producer.Stop()
+
+	// Stop webhook deliveries, consumer is already stopped so nothing new is queued
+	container.Dispatcher.Stop()
+
+	// Stop alert notifications
+	container.AlertEngine.Stop()
+
+	// Stop partition maintenance
+	container.Partitions.Stop()
+
+	// Stop ingesting and matching fills of destination chains
+	container.Transfers.Stop()
+
+	// Stop SLA checks
+	container.SLAMonitor.Stop()
+
+	// Stop following transfer lifecycles
+	container.Lifecycle.Stop()
+
+	// Stop RPC health checks
+	container.Endpoints.Stop()
 
 	// Stop the API server from accepting new requests
 	// Allow current requests to complete
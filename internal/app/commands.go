@@ -0,0 +1,469 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/db"
+	"github.com/eth-bridging/internal/archive"
+	"github.com/eth-bridging/internal/envelope"
+	"github.com/eth-bridging/internal/export"
+	"github.com/eth-bridging/internal/maintenance"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/pricing"
+	"github.com/eth-bridging/internal/producer"
+	"github.com/eth-bridging/internal/reconcile"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/eth-bridging/pkg/di"
+	ethereum "github.com/eth-bridging/pkg/go-eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// RunCommand runs a one off maintenance command instead of the server
+//
+// Supported commands:
+//
+//	migrate up|down|status [-steps N]
+//	rollup rebuild [-from RFC3339] [-to RFC3339]
+//	export -out FILE [-format csv|ndjson] [-quote USD] [-from RFC3339] [-to RFC3339] [-token ADDRESS] [-dest-chain-id ID] [-bridge-name NAME]
+//	parquet-export -dir DIR
+//	archive [-retention-days N] [-batch-size N] [-dry-run]
+//	partitions list|ensure [-months N]
+//	prices import -file CSV
+//	bridges redecode
+//	reconcile -from BLOCK -to BLOCK [-range-size N] [-repair]
+func RunCommand(args []string) {
+	cfg := config.LoadConfig()
+
+	switch args[0] {
+	case "migrate":
+		runMigrateCommand(cfg, args[1:])
+	case "rollup":
+		runRollupCommand(cfg, args[1:])
+	case "export":
+		runExportCommand(cfg, args[1:])
+	case "parquet-export":
+		runParquetExportCommand(cfg, args[1:])
+	case "archive":
+		runArchiveCommand(cfg, args[1:])
+	case "partitions":
+		runPartitionsCommand(cfg, args[1:])
+	case "prices":
+		runPricesCommand(cfg, args[1:])
+	case "bridges":
+		runBridgesCommand(cfg, args[1:])
+	case "reconcile":
+		runReconcileCommand(cfg, args[1:])
+	default:
+		log.Fatalf("Unknown command: %s", args[0])
+	}
+}
+
+// runMigrateCommand applies, reverts or reports the embedded migrations,
+// it takes the same advisory lock as the server does on startup
+func runMigrateCommand(cfg *config.Config, args []string) {
+	usage := "Usage: migrate up|down|status [-steps N]"
+	if len(args) == 0 {
+		log.Fatal(usage)
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	steps := flags.Int("steps", 1, "number of migrations to revert, 0 reverts all of them")
+	flags.Parse(args[1:])
+
+	migrator, err := db.NewMigrator(cfg.PostgresURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize migrations: %v", err)
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		if *steps < 0 {
+			log.Fatal(usage)
+		}
+		err = migrator.Down(*steps)
+	case "status":
+	default:
+		log.Fatal(usage)
+	}
+	if err != nil {
+		log.Fatalf("Failed to migrate %s: %v", args[0], err)
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		log.Fatalf("Failed to read schema version: %v", err)
+	}
+
+	log.Printf("Schema version %d of %d, dirty: %t", status.Current, status.Latest, status.Dirty)
+}
+
+// runRollupCommand recomputes the volume rollup tables from raw events,
+// useful after backfills or if rollups ever drift from `bridge_events`
+func runRollupCommand(cfg *config.Config, args []string) {
+	if len(args) == 0 || args[0] != "rebuild" {
+		log.Fatal("Usage: rollup rebuild [-from RFC3339] [-to RFC3339]")
+	}
+
+	flags := flag.NewFlagSet("rollup rebuild", flag.ExitOnError)
+	fromStr := flags.String("from", "", "start of the range to rebuild (RFC3339), defaults to the beginning")
+	toStr := flags.String("to", "", "end of the range to rebuild (RFC3339), defaults to the end")
+	flags.Parse(args[1:])
+
+	rebuildRollups(di.OpenDatabase(cfg), parseTimeFlag("from", *fromStr), parseTimeFlag("to", *toStr))
+}
+
+// rebuildRollups recomputes the rollups of [from, to), leaving archived days as they are
+func rebuildRollups(db *gorm.DB, from, to time.Time) {
+	// Archived events are gone from `bridge_events`, rebuilding their buckets would wipe them
+	archivedBefore, err := repositories.NewArchiveRepository(db).ArchivedBefore()
+	if err != nil {
+		log.Fatalf("Failed to read archived ranges: %v", err)
+	}
+	if !archivedBefore.IsZero() && from.Before(archivedBefore) {
+		log.Printf("Events before %s are archived, rebuilding from there", archivedBefore.Format(time.RFC3339))
+		from = archivedBefore
+	}
+
+	repo := repositories.NewRollupRepository(db)
+	if err := repo.Rebuild(from, to); err != nil {
+		log.Fatalf("Failed to rebuild rollups: %v", err)
+	}
+
+	log.Println("Rollups rebuilt successfully")
+}
+
+// runExportCommand writes every event matching the filter to a file,
+// it's the command line counterpart of `GET /api/v1/events/export`
+func runExportCommand(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", export.FormatCSV, "output format, csv or ndjson")
+	quote := flags.String("quote", "", "include the USD value of events with USD")
+	out := flags.String("out", "", "file to write to, - for stdout")
+	fromStr := flags.String("from", "", "only events at or after (RFC3339)")
+	toStr := flags.String("to", "", "only events before (RFC3339)")
+	token := flags.String("token", "", "only events of this token")
+	destChainID := flags.String("dest-chain-id", "", "only events to this chain")
+	bridgeName := flags.String("bridge-name", "", "only events through this bridge")
+	flags.Parse(args)
+
+	if *out == "" {
+		log.Fatal("Usage: export -out FILE [-format csv|ndjson] [-quote USD] [-from RFC3339] [-to RFC3339] [-token ADDRESS] [-dest-chain-id ID] [-bridge-name NAME]")
+	}
+
+	filter := models.EventFilter{
+		Token:       *token,
+		DestChainID: *destChainID,
+		BridgeName:  *bridgeName,
+		From:        parseTimeFlag("from", *fromStr),
+		To:          parseTimeFlag("to", *toStr),
+	}
+	if *quote != "" && strings.ToUpper(*quote) != models.QuoteUSD {
+		log.Fatalf("Invalid -quote value %q, only USD is supported", *quote)
+	}
+	if common.IsHexAddress(filter.Token) {
+		filter.Token = common.HexToAddress(filter.Token).Hex()
+	}
+
+	file := os.Stdout
+	if *out != "-" {
+		var err error
+		if file, err = os.Create(*out); err != nil {
+			log.Fatalf("Failed to create %s: %v", *out, err)
+		}
+		defer file.Close()
+	}
+
+	buffered := bufio.NewWriter(file)
+	writer, err := export.NewWriter(*format, buffered, cfg, strings.ToUpper(*quote))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rows := 0
+	repo := repositories.NewBridgeEventRepository(di.OpenDatabase(cfg), cfg)
+	err = repo.StreamEvents(context.Background(), filter, func(event models.BridgeEvent) error {
+		rows++
+		return writer.WriteEvent(event)
+	})
+	if err != nil {
+		log.Fatalf("Failed to export events: %v", err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		log.Fatalf("Failed to write export: %v", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		log.Fatalf("Failed to write export: %v", err)
+	}
+
+	log.Printf("Exported %d events to %s", rows, *out)
+}
+
+// runParquetExportCommand appends events saved since the previous run to a
+// date partitioned parquet directory, meant to be run periodically e.g. from cron
+func runParquetExportCommand(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("parquet-export", flag.ExitOnError)
+	dir := flags.String("dir", "", "directory of the partitioned dataset")
+	lag := flags.Duration("lag", 5*time.Minute, "how long events are visible before they are exported, so late commits aren't skipped")
+	flags.Parse(args)
+
+	if *dir == "" {
+		log.Fatal("Usage: parquet-export -dir DIR [-lag 5m]")
+	}
+
+	repo := repositories.NewBridgeEventRepository(di.OpenDatabase(cfg), cfg)
+	exporter := export.NewParquetExporter(*dir, *lag, cfg)
+
+	rows, err := exporter.Export(context.Background(), repo.StreamEvents)
+	if err != nil {
+		log.Fatalf("Failed to export events to parquet: %v", err)
+	}
+
+	log.Printf("Exported %d events to %s", rows, *dir)
+}
+
+// runArchiveCommand moves events older than the retention period to object storage
+// and deletes them from `bridge_events`, meant to be run periodically e.g. from cron
+func runArchiveCommand(cfg *config.Config, args []string) {
+	opts := archive.DefaultOptions
+	opts.RetentionDays = cfg.ArchiveRetentionDays
+
+	flags := flag.NewFlagSet("archive", flag.ExitOnError)
+	flags.IntVar(&opts.RetentionDays, "retention-days", opts.RetentionDays, "days of events kept in the database")
+	flags.IntVar(&opts.BatchSize, "batch-size", opts.BatchSize, "events deleted per statement")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "only report what would be archived")
+	flags.Parse(args)
+
+	if opts.RetentionDays <= 0 || opts.BatchSize <= 0 {
+		log.Fatal("Usage: archive [-retention-days N] [-batch-size N] [-dry-run], N must be positive")
+	}
+
+	ctx := context.Background()
+	store, err := archive.NewStore(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to open archive store: %v", err)
+	}
+
+	db := di.OpenDatabase(cfg)
+	archiver := archive.NewArchiver(
+		repositories.NewBridgeEventRepository(db, cfg),
+		repositories.NewArchiveRepository(db),
+		store,
+		cfg,
+		opts,
+	)
+
+	ranges, err := archiver.Run(ctx, time.Now())
+	if err != nil {
+		log.Fatalf("Failed to archive events: %v", err)
+	}
+
+	var rows int64
+	for _, rng := range ranges {
+		rows += rng.RowCount
+	}
+	log.Printf("Archived %d events across %d days", rows, len(ranges))
+}
+
+// runPartitionsCommand lists the monthly `bridge_events` partitions or creates missing ones,
+// the server does the latter on its own, this is for creating them further ahead
+func runPartitionsCommand(cfg *config.Config, args []string) {
+	usage := "Usage: partitions list|ensure [-months N]"
+	if len(args) == 0 {
+		log.Fatal(usage)
+	}
+
+	repo := repositories.NewPartitionRepository(di.OpenDatabase(cfg))
+
+	switch args[0] {
+	case "list":
+		names, err := repo.ListPartitions()
+		if err != nil {
+			log.Fatalf("Failed to list partitions: %v", err)
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	case "ensure":
+		flags := flag.NewFlagSet("partitions ensure", flag.ExitOnError)
+		months := flags.Int("months", maintenance.PartitionMonthsAhead, "months ahead of the current one to create partitions for")
+		flags.Parse(args[1:])
+
+		created, err := repo.EnsurePartitions(time.Now().UTC(), *months)
+		if err != nil {
+			log.Fatalf("Failed to create partitions: %v", err)
+		}
+		log.Printf("Created %d partitions %v", len(created), created)
+	default:
+		log.Fatal(usage)
+	}
+}
+
+// runPricesCommand loads historical USD prices into `token_prices`, used when `PRICE_SOURCE=db`
+func runPricesCommand(cfg *config.Config, args []string) {
+	usage := "Usage: prices import -file CSV"
+	if len(args) == 0 || args[0] != "import" {
+		log.Fatal(usage)
+	}
+
+	flags := flag.NewFlagSet("prices import", flag.ExitOnError)
+	path := flags.String("file", "", "csv file with symbol,timestamp,usd_price columns")
+	flags.Parse(args[1:])
+
+	if *path == "" {
+		log.Fatal(usage)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *path, err)
+	}
+	defer file.Close()
+
+	prices, err := pricing.ReadPrices(file)
+	if err != nil {
+		log.Fatalf("Failed to read prices: %v", err)
+	}
+
+	repo := repositories.NewPriceRepository(di.OpenDatabase(cfg))
+	if err := repo.UpsertPrices(prices); err != nil {
+		log.Fatalf("Failed to import prices: %v", err)
+	}
+
+	log.Printf("Imported %d prices from %s", len(prices), *path)
+}
+
+// runBridgesCommand decodes bridge names of saved events again with the current `BRIDGE_NAMES`,
+// e.g. after adding names reported by `/api/v1/bridges/unknown`. Rollups are rebuilt if any changed
+func runBridgesCommand(cfg *config.Config, args []string) {
+	if len(args) == 0 || args[0] != "redecode" {
+		log.Fatal("Usage: bridges redecode")
+	}
+
+	decoder, err := ethereum.NewBridgeNameDecoder(cfg.BridgeNames)
+	if err != nil {
+		log.Fatalf("Invalid BRIDGE_NAMES: %v", err)
+	}
+
+	db := di.OpenDatabase(cfg)
+	repo := repositories.NewBridgeNameRepository(db)
+
+	stored, err := repo.StoredNames()
+	if err != nil {
+		log.Fatalf("Failed to read bridge names: %v", err)
+	}
+
+	var renamed int64
+	for _, name := range stored {
+		decoded, known := decoder.DecodeHex(name.Raw)
+		if !known {
+			log.Printf("Unknown bridge name %s", name.Raw)
+		}
+		if decoded == name.Name {
+			continue
+		}
+
+		count, err := repo.Rename(name.Raw, name.Name, decoded)
+		if err != nil {
+			log.Fatalf("Failed to rename %s to %s: %v", name.Name, decoded, err)
+		}
+		log.Printf("Renamed %d events from %s to %s", count, name.Name, decoded)
+		renamed += count
+	}
+
+	// Rollups are grouped by bridge name, so they're stale now
+	if renamed > 0 {
+		rebuildRollups(db, time.Time{}, time.Time{})
+	}
+}
+
+// runReconcileCommand checks stored events of a block range against the chain and reports
+// missing, extra, mismatched and undecodable ones, missing events are republished with -repair.
+// It exits with status 1 when there are differences, so it can be alerted on e.g. from cron
+func runReconcileCommand(cfg *config.Config, args []string) {
+	opts := reconcile.DefaultOptions
+
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	from := flags.Uint64("from", 0, "first block to reconcile")
+	to := flags.Uint64("to", 0, "last block to reconcile")
+	flags.Uint64Var(&opts.RangeSize, "range-size", opts.RangeSize, "blocks read from the chain at once")
+	flags.BoolVar(&opts.Repair, "repair", false, "republish missing events to the stream, the server saves them")
+	flags.Parse(args)
+
+	if *from == 0 || *to < *from || opts.RangeSize == 0 {
+		log.Fatal("Usage: reconcile -from BLOCK -to BLOCK [-range-size N] [-repair]")
+	}
+
+	ethClient, err := newEthereumClient(cfg, ethereum.DefaultPoolOptions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer ethClient.Endpoints().Stop()
+
+	var streamProducer producer.Producer
+	if opts.Repair {
+		codec, err := envelope.CodecByName(cfg.StreamEncoding)
+		if err != nil {
+			log.Fatal(err)
+		}
+		redisClient := redis.NewClient(&redis.Options{Addr: cfg.RedisURL})
+		defer redisClient.Close()
+		streamProducer = producer.NewRedisProducer(redisClient, cfg.RedisStreamName, cfg.StreamProducerID, codec, &sync.WaitGroup{})
+	}
+
+	repo := repositories.NewBridgeEventRepository(di.OpenDatabase(cfg), cfg)
+	reconciler := reconcile.NewReconciler(ethClient, repo, streamProducer, opts)
+
+	report, err := reconciler.Reconcile(context.Background(), *from, *to)
+	if err != nil {
+		log.Fatalf("Failed to reconcile blocks %d to %d: %v", *from, *to, err)
+	}
+
+	for _, event := range report.Missing {
+		log.Printf("Missing %s event %s:%d in block %d", event.EventName, event.TransactionHash, event.LogIndex, event.BlockNumber)
+	}
+	for _, event := range report.Extra {
+		log.Printf("Extra %s event %s:%d in block %d, id %d", event.EventName, event.TransactionHash, event.LogIndex, event.BlockNumber, event.ID)
+	}
+	for _, mismatch := range report.Mismatched {
+		log.Printf("Mismatched event %s:%d, id %d differs in %s",
+			mismatch.Stored.TransactionHash, mismatch.Stored.LogIndex, mismatch.Stored.ID, strings.Join(mismatch.Fields, ", "))
+	}
+	for _, failure := range report.Undecodable {
+		log.Printf("Undecodable log %s:%d in block %d: %v", failure.TransactionHash, failure.LogIndex, failure.BlockNumber, failure.Err)
+	}
+	log.Printf("Reconciled blocks %d to %d: %d matched, %d missing, %d extra, %d mismatched, %d undecodable, %d republished",
+		report.From, report.To, report.Matched, len(report.Missing), len(report.Extra), len(report.Mismatched), len(report.Undecodable), report.Repaired)
+
+	if !report.Clean() {
+		os.Exit(1)
+	}
+}
+
+// parseTimeFlag parses an optional RFC3339 flag value, exits on invalid input
+func parseTimeFlag(name, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Fatalf("Invalid -%s value %q: %v", name, value, err)
+	}
+
+	return parsed
+}
@@ -0,0 +1,268 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/export"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// Options tunes the Archiver
+type Options struct {
+	// RetentionDays is the number of days events are kept in `bridge_events`
+	RetentionDays int
+	// BatchSize is the number of events deleted per statement once archived
+	BatchSize int
+	// Prefix is prepended to every object key
+	Prefix string
+	// DryRun only reports what would be archived
+	DryRun bool
+}
+
+// DefaultOptions are sensible defaults for production use
+var DefaultOptions = Options{
+	RetentionDays: 90,
+	BatchSize:     5000,
+	Prefix:        "bridge_events/",
+}
+
+// Archiver moves events older than the retention period from `bridge_events` to object storage.
+//
+// Events are archived one UTC day at a time, oldest first, as gzipped NDJSON
+// (same layout as `/events/export?format=ndjson`). A day is only deleted once the
+// uploaded object has been read back and its row count matches the database.
+// The range is recorded before its events are deleted, deletes interrupted by a failed run
+// are finished by the next one
+type Archiver struct {
+	events   repositories.BridgeEventRepository
+	archives repositories.ArchiveRepository
+	store    Store
+	cfg      *config.Config
+	opts     Options
+}
+
+func NewArchiver(events repositories.BridgeEventRepository, archives repositories.ArchiveRepository, store Store, cfg *config.Config, opts Options) *Archiver {
+	return &Archiver{
+		events:   events,
+		archives: archives,
+		store:    store,
+		cfg:      cfg,
+		opts:     opts,
+	}
+}
+
+// Run archives every full day before `now - RetentionDays`, returns the archived ranges
+func (a *Archiver) Run(ctx context.Context, now time.Time) ([]models.ArchivedRange, error) {
+	var archived []models.ArchivedRange
+
+	cutoff := truncateDay(now.AddDate(0, 0, -a.opts.RetentionDays))
+
+	oldest, ok, err := a.archives.OldestEventTime()
+	if err != nil || !ok {
+		return archived, err
+	}
+
+	ranges, err := a.archives.ListRanges()
+	if err != nil {
+		return archived, err
+	}
+	recorded := make(map[time.Time][]models.ArchivedRange)
+	for _, rng := range ranges {
+		recorded[rng.RangeStart.UTC()] = append(recorded[rng.RangeStart.UTC()], rng)
+	}
+
+	for day := truncateDay(oldest); day.Before(cutoff); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return archived, err
+		}
+
+		rng, err := a.archiveDay(ctx, day, day.AddDate(0, 0, 1), recorded[day])
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive %s: %w", day.Format(time.DateOnly), err)
+		}
+		if rng != nil {
+			archived = append(archived, *rng)
+		}
+	}
+
+	return archived, nil
+}
+
+// archiveDay archives events in [from, to), returns nil if there were none.
+// Events left of the recorded ranges of the day are deleted first
+func (a *Archiver) archiveDay(ctx context.Context, from, to time.Time, recorded []models.ArchivedRange) (*models.ArchivedRange, error) {
+	expected, err := a.archives.CountEvents(from, to)
+	if err != nil || expected == 0 {
+		return nil, err
+	}
+
+	if len(recorded) > 0 && !a.opts.DryRun {
+		if err := a.finishDeletes(ctx, recorded); err != nil {
+			return nil, err
+		}
+		if expected, err = a.archives.CountEvents(from, to); err != nil || expected == 0 {
+			return nil, err
+		}
+	}
+
+	if a.opts.DryRun {
+		log.Printf("Would archive %d events of %s", expected, from.Format(time.DateOnly))
+		return &models.ArchivedRange{RangeStart: from, RangeEnd: to, RowCount: expected}, nil
+	}
+
+	file, err := os.CreateTemp("", "bridge-archive-*.ndjson.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	rng := &models.ArchivedRange{RangeStart: from, RangeEnd: to}
+	if err := a.writeEvents(ctx, file, rng); err != nil {
+		return nil, err
+	}
+	if rng.RowCount != expected {
+		return nil, fmt.Errorf("wrote %d events, expected %d", rng.RowCount, expected)
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	rng.ObjectKey = fmt.Sprintf("%sdate=%s/events-%d-%d.ndjson.gz", a.opts.Prefix, from.Format(time.DateOnly), rng.MinEventID, rng.MaxEventID)
+	if err := a.store.Put(ctx, rng.ObjectKey, file, size); err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", rng.ObjectKey, err)
+	}
+
+	// Never trust the upload blindly, the events are about to be deleted
+	ids, err := a.storedEventIDs(ctx, rng.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify %s: %w", rng.ObjectKey, err)
+	}
+	if int64(len(ids)) != expected {
+		return nil, fmt.Errorf("%s holds %d events, expected %d", rng.ObjectKey, len(ids), expected)
+	}
+
+	if err := a.archives.CreateRange(rng); err != nil {
+		return nil, fmt.Errorf("failed to record archived range: %w", err)
+	}
+
+	// Exactly the archived events are deleted, events saved into the range meanwhile are left for the next run
+	deleted, err := a.archives.DeleteEvents(from, to, ids, a.opts.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete archived events: %w", err)
+	}
+	// An archived event was deleted or moved out of the range meanwhile, the archive no longer matches the table
+	if deleted != int64(len(ids)) {
+		return nil, fmt.Errorf("deleted %d events, archived %d", deleted, len(ids))
+	}
+
+	log.Printf("Archived %d events of %s to %s", rng.RowCount, from.Format(time.DateOnly), rng.ObjectKey)
+	return rng, nil
+}
+
+// finishDeletes deletes the events of ranges recorded by earlier runs which failed while deleting them
+func (a *Archiver) finishDeletes(ctx context.Context, recorded []models.ArchivedRange) error {
+	for _, rng := range recorded {
+		ids, err := a.storedEventIDs(ctx, rng.ObjectKey)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rng.ObjectKey, err)
+		}
+
+		deleted, err := a.archives.DeleteEvents(rng.RangeStart, rng.RangeEnd, ids, a.opts.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to delete events archived to %s: %w", rng.ObjectKey, err)
+		}
+		if deleted > 0 {
+			log.Printf("Deleted %d events left by an earlier run, archived to %s", deleted, rng.ObjectKey)
+		}
+	}
+
+	return nil
+}
+
+// writeEvents writes events of the range to w as gzipped NDJSON, filling in row count and ids of rng
+func (a *Archiver) writeEvents(ctx context.Context, w io.Writer, rng *models.ArchivedRange) error {
+	compressed := gzip.NewWriter(w)
+	buffered := bufio.NewWriter(compressed)
+
+	// USD values are kept, they're stored with the event rather than derived from it
+	writer, err := export.NewWriter(export.FormatNDJSON, buffered, a.cfg, models.QuoteUSD)
+	if err != nil {
+		return err
+	}
+
+	filter := models.EventFilter{From: rng.RangeStart, To: rng.RangeEnd}
+	err = a.events.StreamEvents(ctx, filter, func(event models.BridgeEvent) error {
+		if rng.RowCount == 0 {
+			rng.MinEventID = event.ID
+		}
+		rng.MaxEventID = event.ID
+		rng.RowCount++
+
+		return writer.WriteEvent(event)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	return compressed.Close()
+}
+
+// storedEventIDs reads the object back and returns the ids of its rows
+func (a *Archiver) storedEventIDs(ctx context.Context, key string) ([]int, error) {
+	object, err := a.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	decompressed, err := gzip.NewReader(object)
+	if err != nil {
+		return nil, err
+	}
+	defer decompressed.Close()
+
+	var ids []int
+	scanner := bufio.NewScanner(decompressed)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record export.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid row %d: %w", len(ids)+1, err)
+		}
+		ids = append(ids, record.ID)
+	}
+
+	return ids, scanner.Err()
+}
+
+// truncateDay returns the start of the UTC day of t
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,214 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeEventRepository streams a fixed list of events, other methods are unused
+type fakeEventRepository struct {
+	repositories.BridgeEventRepository
+	events []models.BridgeEvent
+}
+
+func (r *fakeEventRepository) StreamEvents(ctx context.Context, filter models.EventFilter, fn func(models.BridgeEvent) error) error {
+	for _, event := range r.events {
+		if !filter.Matches(event) {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type MockArchiveRepository struct {
+	mock.Mock
+}
+
+func (m *MockArchiveRepository) OldestEventTime() (time.Time, bool, error) {
+	args := m.Called()
+	return args.Get(0).(time.Time), args.Bool(1), args.Error(2)
+}
+
+func (m *MockArchiveRepository) CountEvents(from, to time.Time) (int64, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockArchiveRepository) CreateRange(archived *models.ArchivedRange) error {
+	return m.Called(archived).Error(0)
+}
+
+func (m *MockArchiveRepository) DeleteEvents(from, to time.Time, ids []int, batchSize int) (int64, error) {
+	args := m.Called(from, to, ids, batchSize)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockArchiveRepository) ListRanges() ([]models.ArchivedRange, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ArchivedRange), args.Error(1)
+}
+
+func (m *MockArchiveRepository) ArchivedBefore() (time.Time, error) {
+	args := m.Called()
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func archiveEvent(id int, ts time.Time) models.BridgeEvent {
+	return models.BridgeEvent{
+		ID:              id,
+		Token:           "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Amount:          "1372483935",
+		FromChain:       "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+		ToChain:         "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+		DestChainID:     "10",
+		Timestamp:       ts,
+		TransactionHash: "0x995f960af8eefc632cdd9b89b546f4069a4098b2b40fd25840048f59d5ee5106",
+	}
+}
+
+func TestArchiverRun(t *testing.T) {
+	now := time.Date(2024, 12, 20, 15, 0, 0, 0, time.UTC)
+	day1 := time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day2.AddDate(0, 0, 1)
+
+	events := &fakeEventRepository{events: []models.BridgeEvent{
+		archiveEvent(1, day1.Add(time.Hour)),
+		archiveEvent(2, day1.Add(2*time.Hour)),
+		archiveEvent(3, day3.Add(time.Hour)),
+	}}
+
+	repo := new(MockArchiveRepository)
+	repo.On("OldestEventTime").Return(day1.Add(time.Hour), true, nil)
+	repo.On("ListRanges").Return([]models.ArchivedRange{}, nil)
+	repo.On("CountEvents", day1, day2).Return(int64(2), nil)
+	repo.On("CountEvents", day2, day3).Return(int64(0), nil)
+	repo.On("CreateRange", mock.Anything).Return(nil)
+	repo.On("DeleteEvents", day1, day2, []int{1, 2}, 1).Return(int64(2), nil)
+
+	store := NewFileStore(t.TempDir())
+	opts := Options{RetentionDays: 2, BatchSize: 1, Prefix: "bridge_events/"}
+	archiver := NewArchiver(events, repo, store, config.LoadConfig(), opts)
+
+	// Only 16th and 17th are older than 2 days, the 18th is kept
+	ranges, err := archiver.Run(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Len(t, ranges, 1)
+	assert.Equal(t, "bridge_events/date=2024-12-16/events-1-2.ndjson.gz", ranges[0].ObjectKey)
+	assert.Equal(t, int64(2), ranges[0].RowCount)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CountEvents", day3, mock.Anything)
+
+	object, err := store.Get(context.Background(), ranges[0].ObjectKey)
+	assert.NoError(t, err)
+	defer object.Close()
+
+	decompressed, err := gzip.NewReader(object)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(decompressed)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"id":1`)
+}
+
+func TestArchiverRunCountMismatch(t *testing.T) {
+	now := time.Date(2024, 12, 20, 15, 0, 0, 0, time.UTC)
+	day1 := time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC)
+
+	events := &fakeEventRepository{events: []models.BridgeEvent{archiveEvent(1, day1.Add(time.Hour))}}
+
+	repo := new(MockArchiveRepository)
+	repo.On("OldestEventTime").Return(day1, true, nil)
+	repo.On("ListRanges").Return([]models.ArchivedRange{}, nil)
+	repo.On("CountEvents", day1, day1.AddDate(0, 0, 1)).Return(int64(2), nil)
+
+	archiver := NewArchiver(events, repo, NewFileStore(t.TempDir()), config.LoadConfig(), DefaultOptions)
+
+	_, err := archiver.Run(context.Background(), now.AddDate(0, 0, DefaultOptions.RetentionDays))
+	assert.Error(t, err)
+	// Nothing is recorded or deleted unless every event made it into the archive
+	repo.AssertNotCalled(t, "CreateRange", mock.Anything)
+	repo.AssertNotCalled(t, "DeleteEvents", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestArchiverRunDeleteMismatch(t *testing.T) {
+	now := time.Date(2024, 12, 20, 15, 0, 0, 0, time.UTC)
+	day1 := time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	events := &fakeEventRepository{events: []models.BridgeEvent{
+		archiveEvent(1, day1.Add(time.Hour)),
+		archiveEvent(2, day1.Add(2*time.Hour)),
+	}}
+
+	repo := new(MockArchiveRepository)
+	repo.On("OldestEventTime").Return(day1, true, nil)
+	repo.On("ListRanges").Return([]models.ArchivedRange{}, nil)
+	repo.On("CountEvents", day1, day2).Return(int64(2), nil)
+	repo.On("CreateRange", mock.Anything).Return(nil)
+	// Event 2 was deleted meanwhile, totals are compared across batches
+	repo.On("DeleteEvents", day1, day2, []int{1, 2}, 1).Return(int64(1), nil)
+
+	opts := Options{RetentionDays: 2, BatchSize: 1, Prefix: "bridge_events/"}
+	archiver := NewArchiver(events, repo, NewFileStore(t.TempDir()), config.LoadConfig(), opts)
+
+	_, err := archiver.Run(context.Background(), now)
+	assert.ErrorContains(t, err, "deleted 1 events, archived 2")
+}
+
+func TestArchiverRunFinishesInterruptedDeletes(t *testing.T) {
+	now := time.Date(2024, 12, 20, 15, 0, 0, 0, time.UTC)
+	day1 := time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	events := &fakeEventRepository{events: []models.BridgeEvent{
+		archiveEvent(1, day1.Add(time.Hour)),
+		archiveEvent(2, day1.Add(2*time.Hour)),
+	}}
+	store := NewFileStore(t.TempDir())
+	opts := Options{RetentionDays: 3, BatchSize: 1, Prefix: "bridge_events/"}
+
+	// The first run records the range and fails after deleting event 1
+	repo := new(MockArchiveRepository)
+	repo.On("OldestEventTime").Return(day1, true, nil)
+	repo.On("ListRanges").Return([]models.ArchivedRange{}, nil)
+	repo.On("CountEvents", day1, day2).Return(int64(2), nil)
+	var recorded models.ArchivedRange
+	repo.On("CreateRange", mock.Anything).Run(func(args mock.Arguments) {
+		recorded = *args.Get(0).(*models.ArchivedRange)
+	}).Return(nil)
+	repo.On("DeleteEvents", day1, day2, []int{1, 2}, 1).Return(int64(1), errors.New("connection reset"))
+
+	_, err := NewArchiver(events, repo, store, config.LoadConfig(), opts).Run(context.Background(), now)
+	assert.ErrorContains(t, err, "connection reset")
+
+	// The next one deletes the rest of the recorded range rather than archiving event 2 again
+	repo = new(MockArchiveRepository)
+	repo.On("OldestEventTime").Return(day1, true, nil)
+	repo.On("ListRanges").Return([]models.ArchivedRange{recorded}, nil)
+	repo.On("CountEvents", day1, day2).Return(int64(1), nil).Once()
+	repo.On("DeleteEvents", day1, day2, []int{1, 2}, 1).Return(int64(1), nil)
+	repo.On("CountEvents", day1, day2).Return(int64(0), nil).Once()
+
+	ranges, err := NewArchiver(events, repo, store, config.LoadConfig(), opts).Run(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Empty(t, ranges)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CreateRange", mock.Anything)
+}
@@ -0,0 +1,121 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/eth-bridging/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	BackendFS = "fs"
+	BackendS3 = "s3"
+)
+
+// Store is the object storage archived events are written to
+type Store interface {
+	// Put uploads size bytes of r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// NewStore returns the store configured by `ARCHIVE_BACKEND`
+func NewStore(ctx context.Context, cfg *config.Config) (Store, error) {
+	switch cfg.ArchiveBackend {
+	case BackendFS:
+		return NewFileStore(cfg.ArchiveDir), nil
+	case BackendS3:
+		return NewS3Store(ctx, cfg.ArchiveS3Endpoint, cfg.ArchiveS3AccessKey, cfg.ArchiveS3SecretKey, cfg.ArchiveS3Bucket, cfg.ArchiveS3UseSSL)
+	default:
+		return nil, fmt.Errorf("unknown archive backend %q, must be %s or %s", cfg.ArchiveBackend, BackendFS, BackendS3)
+	}
+}
+
+// FileStore keeps objects as files under a root directory, keys are relative paths
+//
+//	Note:
+//	  Meant for local development and tests, use S3Store in production
+type FileStore struct {
+	root string
+}
+
+func NewFileStore(root string) *FileStore {
+	return &FileStore{root: root}
+}
+
+func (s *FileStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Written under a temporary name, so a failed upload never leaves a partial object behind
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("short upload of %s, wrote %d of %d bytes", key, written, size)
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (s *FileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.root, filepath.FromSlash(key)))
+}
+
+// S3Store keeps objects in a bucket of any S3 compatible service, e.g. AWS S3 or MinIO
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store connects to the endpoint and creates the bucket if it doesn't exist yet
+func NewS3Store(ctx context.Context, endpoint, accessKey, secretKey, bucket string, useSSL bool) (*S3Store, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+		}
+	}
+
+	return &S3Store{client: client, bucket: bucket}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: "application/gzip",
+	})
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+}
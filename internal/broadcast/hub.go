@@ -0,0 +1,99 @@
+package broadcast
+
+import (
+	"log"
+	"sync"
+
+	"github.com/eth-bridging/internal/models"
+)
+
+// Hub fans out persisted bridge events to live subscribers, e.g. SSE and WebSocket connections.
+//
+// Every subscriber gets its own buffered channel, publishing never blocks, instead
+// a subscriber whose buffer is full is dropped, so one slow client can't hold back the consumer.
+// Dropped clients are expected to reconnect and resume using the last event id they received
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+	bufferSize  int
+}
+
+// Subscription is a single live subscriber of the hub
+type Subscription struct {
+	filter models.EventFilter
+	events chan models.BridgeEvent
+	closed chan struct{}
+	once   sync.Once
+}
+
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Events receives every published event matching the subscription filter
+func (s *Subscription) Events() <-chan models.BridgeEvent {
+	return s.events
+}
+
+// Closed is closed once the subscription is removed from the hub,
+// either by Unsubscribe or because the subscriber fell behind
+func (s *Subscription) Closed() <-chan struct{} {
+	return s.closed
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.closed) })
+}
+
+// Subscribe registers a new subscriber for events matching the filter
+func (h *Hub) Subscribe(filter models.EventFilter) *Subscription {
+	sub := &Subscription{
+		filter: filter,
+		events: make(chan models.BridgeEvent, h.bufferSize),
+		closed: make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes the subscriber, it's safe to call more than once
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+
+	sub.close()
+}
+
+// OnEventSaved publishes the event to every matching subscriber
+//
+//	Note: It never blocks, subscribers with a full buffer are dropped
+func (h *Hub) OnEventSaved(event models.BridgeEvent) {
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for sub := range h.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		log.Printf("Dropping slow stream subscriber, buffer of %d events is full", h.bufferSize)
+		h.Unsubscribe(sub)
+	}
+}
@@ -0,0 +1,49 @@
+package broadcast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/broadcast"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_PublishesMatchingEvents(t *testing.T) {
+	hub := broadcast.NewHub(10)
+
+	all := hub.Subscribe(models.EventFilter{})
+	optimism := hub.Subscribe(models.EventFilter{DestChainID: "10"})
+	defer hub.Unsubscribe(all)
+	defer hub.Unsubscribe(optimism)
+
+	hub.OnEventSaved(models.BridgeEvent{ID: 1, DestChainID: "137", Timestamp: time.Now()})
+	hub.OnEventSaved(models.BridgeEvent{ID: 2, DestChainID: "10", Timestamp: time.Now()})
+
+	assert.Len(t, all.Events(), 2)
+	assert.Len(t, optimism.Events(), 1)
+	assert.Equal(t, 2, (<-optimism.Events()).ID)
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	hub := broadcast.NewHub(1)
+
+	sub := hub.Subscribe(models.EventFilter{})
+
+	hub.OnEventSaved(models.BridgeEvent{ID: 1})
+	// Buffer is full, so the subscriber is dropped instead of blocking the publisher
+	hub.OnEventSaved(models.BridgeEvent{ID: 2})
+
+	select {
+	case <-sub.Closed():
+	default:
+		t.Fatal("expected slow subscriber to be closed")
+	}
+
+	// Dropped subscribers don't receive anything else
+	hub.OnEventSaved(models.BridgeEvent{ID: 3})
+	assert.Len(t, sub.Events(), 1)
+
+	// Unsubscribing again is a no-op
+	hub.Unsubscribe(sub)
+}
@@ -2,18 +2,30 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/envelope"
 	"github.com/eth-bridging/internal/models"
 	"github.com/eth-bridging/internal/services"
 	rediscli "github.com/eth-bridging/pkg/redisclient"
 	"github.com/go-redis/redis/v8"
 )
 
+// EventListener is notified about every event right after it's persisted,
+// e.g. to push it to live subscribers
+//
+//	Note: OnEventSaved is called from the consumer loop, so it must not block
+type EventListener interface {
+	OnEventSaved(event models.BridgeEvent)
+}
+
+// dlqReasonField is added to messages moved to the DLQ, saying why they couldn't be processed
+const dlqReasonField = "dlqReason"
+
 type RedisStreamConsumer struct {
 	ctx        context.Context
 	client     rediscli.RedisClient
@@ -24,6 +36,7 @@ type RedisStreamConsumer struct {
 	done       chan bool
 	wg         *sync.WaitGroup
 	cfg        *config.Config
+	listeners  []EventListener
 }
 
 type NewConsumerInput struct {
@@ -34,6 +47,8 @@ type NewConsumerInput struct {
 	Service    services.BridgeEventService
 	Wg         *sync.WaitGroup
 	Cfg        *config.Config
+	// Listeners are notified about every newly saved event
+	Listeners []EventListener
 }
 
 // NewRedisStreamConsumer creates a new Redis stream consumer that listens
@@ -65,6 +80,7 @@ func NewRedisStreamConsumer(input *NewConsumerInput) *RedisStreamConsumer {
 		done:       make(chan bool),
 		wg:         input.Wg,
 		cfg:        input.Cfg,
+		listeners:  input.Listeners,
 	}
 }
 
@@ -80,7 +96,8 @@ func (r *RedisStreamConsumer) Stop() {
 // The method continuously reads from the stream in a loop, handling any errors
 // in reading or decoding. If an error occurs during event saving, it logs the error.
 //
-// It also ensures the stream message is acknowledged once processed.
+// It also ensures the stream message is acknowledged once processed, messages which
+// failed and couldn't be moved to the DLQ are left pending instead.
 //
 //	Note: Since it is a blocking process, please ensure to call it with `go` keyword
 func (r *RedisStreamConsumer) Consume() {
@@ -117,27 +134,28 @@ func (r *RedisStreamConsumer) processStreamEntries(entries []redis.XStream) {
 	for _, stream := range entries {
 		for _, message := range stream.Messages {
 
-			var eventMsg models.BridgeEvent
-			if err := decodeMessage(message, &eventMsg); err != nil {
-				log.Printf("Error decoding message: %v", err)
-				r.moveToDLQ(message)
+			env, err := envelope.Decode(message.Values)
+			if err != nil {
+				log.Printf("Error decoding message %s: %v", message.ID, err)
+				if err := r.moveToDLQ(message, err.Error()); err != nil {
+					// Left pending rather than lost
+					log.Printf("Error moving message %s to DLQ, it's not acknowledged: %v", message.ID, err)
+					continue
+				}
+				r.client.XAck(r.ctx, r.streamName, r.groupName, message.ID)
 				continue
 			}
-
-			event := models.BridgeEvent{
-				Token:           eventMsg.Token,
-				Amount:          eventMsg.Amount,
-				FromChain:       eventMsg.FromChain,
-				ToChain:         eventMsg.ToChain,
-				Timestamp:       eventMsg.Timestamp,
-				TransactionHash: eventMsg.TransactionHash,
-			}
+			event := env.Event()
 
 			if err := r.service.SaveEvent(&event); err != nil {
 				log.Printf("Error saving event: %v", err)
-				r.moveToDLQ(message)
+				if err := r.moveToDLQ(message, fmt.Sprintf("failed to save event: %v", err)); err != nil {
+					log.Printf("Error moving message %s to DLQ, it's not acknowledged: %v", message.ID, err)
+					continue
+				}
 			} else {
 				log.Printf("Processed event: %+v", event)
+				r.notifyListeners(event)
 			}
 
 			r.client.XAck(r.ctx, r.streamName, r.groupName, message.ID)
@@ -145,24 +163,32 @@ func (r *RedisStreamConsumer) processStreamEntries(entries []redis.XStream) {
 	}
 }
 
-// moveToDLQ moves the message to a Dead Letter Queue (DLQ)
-// !Caution: Ideally should move messages to DLQ that failed processing after multiple retries
-func (r *RedisStreamConsumer) moveToDLQ(message redis.XMessage) {
-	// Add the message to a DLQ stream (e.g., "bridging_events_dlq")
-	err := r.client.XAdd(r.ctx, &redis.XAddArgs{
-		Stream: r.cfg.RedisStreamDlq,
-		Values: message.Values,
-	}).Err()
-	if err != nil {
-		log.Printf("Error moving message %s to DLQ: %v", message.ID, err)
+// notifyListeners fans the saved event out to every listener
+//
+// Redelivered events are skipped by the repository and keep a zero ID,
+// listeners have already been notified about them
+func (r *RedisStreamConsumer) notifyListeners(event models.BridgeEvent) {
+	if event.ID == 0 {
+		return
+	}
+
+	for _, listener := range r.listeners {
+		listener.OnEventSaved(event)
 	}
 }
 
-// decodeMessage simply converts redis XMessage into BridgeEvent
-func decodeMessage(msg redis.XMessage, eventMsg *models.BridgeEvent) error {
-	data, err := json.Marshal(msg.Values)
-	if err != nil {
-		return err
+// moveToDLQ moves the message to a Dead Letter Queue (DLQ), along with why it was moved in `dlqReason`
+// !Caution: Ideally should move messages to DLQ that failed processing after multiple retries
+func (r *RedisStreamConsumer) moveToDLQ(message redis.XMessage, reason string) error {
+	values := make(map[string]interface{}, len(message.Values)+1)
+	for name, value := range message.Values {
+		values[name] = value
 	}
-	return json.Unmarshal(data, eventMsg)
+	values[dlqReasonField] = reason
+
+	// Add the message to a DLQ stream (e.g., "bridging_events_dlq")
+	return r.client.XAdd(r.ctx, &redis.XAddArgs{
+		Stream: r.cfg.RedisStreamDlq,
+		Values: values,
+	}).Err()
 }
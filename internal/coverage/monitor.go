@@ -0,0 +1,214 @@
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/producer"
+	"github.com/eth-bridging/internal/repositories"
+	ethereum "github.com/eth-bridging/pkg/go-eth"
+)
+
+// Backfiller publishes logs of a block range, see ethereum.EthereumClient.Backfill
+type Backfiller interface {
+	Backfill(ctx context.Context, from, to, rangeSize uint64, streamProducer producer.Producer) error
+}
+
+// Chain is a chain whose coverage is tracked, gaps are backfilled by publishing their logs to Producer
+type Chain struct {
+	ChainID  string
+	Client   Backfiller
+	Producer producer.Producer
+}
+
+// Options tunes the Monitor
+type Options struct {
+	// Interval is the wait between checks for gaps
+	Interval time.Duration
+	// BackfillAfter is how long a gap is left before it's backfilled, giving ingestion time to
+	// cover it on its own, e.g. after resubscribing. 0 only reports gaps
+	BackfillAfter time.Duration
+	// RangeSize is the number of blocks backfilled at once
+	RangeSize uint64
+}
+
+// DefaultOptions are sensible defaults for production use
+var DefaultOptions = Options{
+	Interval:      time.Minute,
+	BackfillAfter: 10 * time.Minute,
+	RangeSize:     2000,
+}
+
+// Monitor keeps track of the block ranges of every chain whose logs were all ingested,
+// gaps between them are left by restarts or dropped subscriptions and are backfilled
+// once they're older than BackfillAfter
+type Monitor struct {
+	repo   repositories.CoverageRepository
+	chains []Chain
+	opts   Options
+	now    func() time.Time
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewMonitor(repo repositories.CoverageRepository, chains []Chain, opts Options) *Monitor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Monitor{
+		repo:   repo,
+		chains: chains,
+		opts:   opts,
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Recorder returns the recorder of the chain's coverage, it's handed to the chain's client
+func (m *Monitor) Recorder(chainID string) ethereum.CoverageRecorder {
+	return &recorder{monitor: m, chainID: chainID}
+}
+
+// Start checks for gaps every Interval, they're only reported when BackfillAfter is 0
+func (m *Monitor) Start() {
+	if m.opts.BackfillAfter == 0 {
+		return
+	}
+
+	m.wg.Add(1)
+	go m.run()
+}
+
+// Stop waits for an in flight backfill to finish
+func (m *Monitor) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// Coverage returns the covered ranges and gaps of every chain ranges were recorded for
+func (m *Monitor) Coverage() ([]models.ChainCoverage, error) {
+	ranges, err := m.repo.ListRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	chains := []models.ChainCoverage{}
+	for _, rng := range ranges {
+		if len(chains) == 0 || chains[len(chains)-1].ChainID != rng.ChainID {
+			chains = append(chains, models.ChainCoverage{ChainID: rng.ChainID, Ranges: []models.BlockRange{}})
+		}
+		chain := &chains[len(chains)-1]
+		chain.Ranges = append(chain.Ranges, rng)
+	}
+	for i := range chains {
+		chains[i].Gaps = Gaps(chains[i].Ranges)
+	}
+
+	return chains, nil
+}
+
+// Gaps returns the holes between the ranges of a chain, which are ordered by their first block
+func Gaps(ranges []models.BlockRange) []models.CoverageGap {
+	gaps := []models.CoverageGap{}
+	if len(ranges) == 0 {
+		return gaps
+	}
+
+	// covered is the last block covered so far, a range may lie within an earlier one
+	covered := ranges[0].ToBlock
+	for _, rng := range ranges[1:] {
+		if rng.FromBlock > covered+1 {
+			gaps = append(gaps, models.CoverageGap{
+				ChainID:    rng.ChainID,
+				FromBlock:  covered + 1,
+				ToBlock:    rng.FromBlock - 1,
+				DetectedAt: rng.CreatedAt,
+			})
+		}
+		covered = max(covered, rng.ToBlock)
+	}
+
+	return gaps
+}
+
+// Backfill backfills every gap detected more than BackfillAfter ago, returns the number backfilled
+func (m *Monitor) Backfill() (int, error) {
+	coverage, err := m.Coverage()
+	if err != nil {
+		return 0, err
+	}
+
+	chains := make(map[string]Chain, len(m.chains))
+	for _, chain := range m.chains {
+		chains[chain.ChainID] = chain
+	}
+
+	backfilled := 0
+	for _, chainCoverage := range coverage {
+		chain, ok := chains[chainCoverage.ChainID]
+		if !ok {
+			continue
+		}
+
+		for _, gap := range chainCoverage.Gaps {
+			if m.now().Sub(gap.DetectedAt) < m.opts.BackfillAfter || m.ctx.Err() != nil {
+				continue
+			}
+
+			log.Printf("Backfilling blocks %d to %d of chain %s", gap.FromBlock, gap.ToBlock, gap.ChainID)
+			if err := chain.Client.Backfill(m.ctx, gap.FromBlock, gap.ToBlock, m.opts.RangeSize, chain.Producer); err != nil {
+				return backfilled, fmt.Errorf("failed to backfill blocks %d to %d of chain %s: %w", gap.FromBlock, gap.ToBlock, gap.ChainID, err)
+			}
+			backfilled++
+		}
+	}
+
+	return backfilled, nil
+}
+
+func (m *Monitor) run() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		backfilled, err := m.Backfill()
+		if err != nil && m.ctx.Err() == nil {
+			log.Printf("Error backfilling coverage gaps: %v", err)
+		}
+		if backfilled > 0 {
+			log.Printf("Backfilled %d coverage gaps", backfilled)
+		}
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recorder saves covered ranges of a chain
+type recorder struct {
+	monitor *Monitor
+	chainID string
+}
+
+// RecordCoverage implements ethereum.CoverageRecorder, failures are logged as ingestion goes on regardless.
+// The range is then reported as a gap and backfilled again
+func (r *recorder) RecordCoverage(from, to uint64) {
+	if err := r.monitor.repo.AddRange(r.chainID, from, to, r.monitor.now().UTC()); err != nil {
+		log.Printf("Error recording coverage of blocks %d to %d of chain %s: %v", from, to, r.chainID, err)
+	}
+}
+
+// LastCovered implements ethereum.CoverageRecorder
+func (r *recorder) LastCovered() (uint64, bool, error) {
+	return r.monitor.repo.LastCovered(r.chainID)
+}
@@ -0,0 +1,106 @@
+package coverage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/producer"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCoverageRepository returns the ranges it was given, ordered by chain and first block
+type fakeCoverageRepository struct {
+	repositories.CoverageRepository
+
+	ranges []models.BlockRange
+}
+
+func (r *fakeCoverageRepository) ListRanges() ([]models.BlockRange, error) {
+	return r.ranges, nil
+}
+
+// fakeBackfiller keeps the backfilled ranges, failing those starting at failAt
+type fakeBackfiller struct {
+	backfilled [][2]uint64
+	failAt     uint64
+}
+
+func (b *fakeBackfiller) Backfill(ctx context.Context, from, to, rangeSize uint64, streamProducer producer.Producer) error {
+	if from == b.failAt {
+		return errors.New("connection refused")
+	}
+	b.backfilled = append(b.backfilled, [2]uint64{from, to})
+	return nil
+}
+
+func blockRange(chainID string, from, to uint64, createdAt time.Time) models.BlockRange {
+	return models.BlockRange{ChainID: chainID, FromBlock: from, ToBlock: to, CreatedAt: createdAt}
+}
+
+func TestGaps(t *testing.T) {
+	start := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+
+	gaps := Gaps([]models.BlockRange{
+		blockRange("1", 100, 200, start),
+		// Recorded concurrently, not merged yet
+		blockRange("1", 150, 180, start.Add(time.Minute)),
+		blockRange("1", 201, 250, start.Add(2*time.Minute)),
+		blockRange("1", 300, 400, start.Add(3*time.Minute)),
+	})
+
+	assert.Equal(t, []models.CoverageGap{
+		{ChainID: "1", FromBlock: 251, ToBlock: 299, DetectedAt: start.Add(3 * time.Minute)},
+	}, gaps)
+	assert.Empty(t, Gaps(nil))
+}
+
+func TestMonitor_Coverage(t *testing.T) {
+	start := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	repo := &fakeCoverageRepository{ranges: []models.BlockRange{
+		blockRange("1", 100, 200, start),
+		blockRange("1", 300, 400, start),
+		blockRange("10", 5000, 6000, start),
+	}}
+
+	chains, err := NewMonitor(repo, nil, DefaultOptions).Coverage()
+
+	assert.NoError(t, err)
+	if assert.Len(t, chains, 2) {
+		assert.Equal(t, "1", chains[0].ChainID)
+		assert.Len(t, chains[0].Ranges, 2)
+		assert.Len(t, chains[0].Gaps, 1)
+		assert.Equal(t, "10", chains[1].ChainID)
+		assert.Empty(t, chains[1].Gaps)
+	}
+}
+
+func TestMonitor_Backfill(t *testing.T) {
+	now := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	repo := &fakeCoverageRepository{ranges: []models.BlockRange{
+		blockRange("1", 100, 200, now.Add(-time.Hour)),
+		blockRange("1", 300, 400, now.Add(-time.Hour)),
+		// Too recent, ingestion may still cover it
+		blockRange("1", 500, 600, now.Add(-time.Minute)),
+		// Not a tracked chain
+		blockRange("5", 100, 200, now.Add(-time.Hour)),
+		blockRange("5", 300, 400, now.Add(-time.Hour)),
+	}}
+	client := &fakeBackfiller{}
+
+	monitor := NewMonitor(repo, []Chain{{ChainID: "1", Client: client}}, Options{BackfillAfter: 10 * time.Minute, RangeSize: 50})
+	monitor.now = func() time.Time { return now }
+	backfilled, err := monitor.Backfill()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, backfilled)
+	assert.Equal(t, [][2]uint64{{201, 299}}, client.backfilled)
+
+	client.failAt = 201
+	_, err = monitor.Backfill()
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/eth-bridging/internal/envelope/envelopepb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Codec encodes the payload of an envelope, its name is written in the `encoding` field of every
+// message so consumers read messages of any codec, e.g. while producers move to another one
+type Codec interface {
+	Name() string
+	Marshal(event Event) ([]byte, error)
+	Unmarshal(data []byte, event *Event) error
+}
+
+var (
+	// JSON is the default codec, messages without an `encoding` field were encoded with it
+	JSON Codec = jsonCodec{}
+	// Protobuf encodes payloads as envelopepb.BridgeEvent, see `envelopepb/envelope.proto`
+	Protobuf Codec = protobufCodec{}
+)
+
+// codecs are the codecs messages are read with
+var codecs = []Codec{JSON, Protobuf}
+
+// CodecByName returns the codec of the name, `STREAM_ENCODING` picks the codec events are published with
+func CodecByName(name string) (Codec, error) {
+	for _, codec := range codecs {
+		if codec.Name() == name {
+			return codec, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown encoding %q", name)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+func (jsonCodec) Unmarshal(data []byte, event *Event) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(event)
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string {
+	return "protobuf"
+}
+
+func (protobufCodec) Marshal(event Event) ([]byte, error) {
+	message := &envelopepb.BridgeEvent{
+		TransactionHash: event.TransactionHash,
+		LogIndex:        uint32(event.LogIndex),
+		BlockNumber:     event.BlockNumber,
+		Timestamp:       timestamppb.New(event.Timestamp),
+		Token:           event.Token,
+		FromChain:       event.FromChain,
+		ToChain:         event.ToChain,
+		DestChainId:     event.DestChainID,
+		BridgeName:      event.BridgeName,
+		BridgeNameRaw:   event.BridgeNameRaw,
+		GasUsed:         event.GasUsed,
+		TxFrom:          event.TxFrom,
+		TxTo:            event.TxTo,
+	}
+	if event.BlockTimestamp != nil {
+		message.BlockTimestamp = timestamppb.New(*event.BlockTimestamp)
+	}
+
+	var err error
+	if event.Amount != "" {
+		if message.Amount, err = uint256Bytes("amount", event.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if event.EffectiveGasPrice != nil {
+		if message.EffectiveGasPrice, err = uint256Bytes("effective gas price", *event.EffectiveGasPrice); err != nil {
+			return nil, err
+		}
+	}
+	if event.TxStatus != nil {
+		txStatus := uint32(*event.TxStatus)
+		message.TxStatus = &txStatus
+	}
+	if event.Args != nil {
+		if message.Args, err = json.Marshal(event.Args); err != nil {
+			return nil, err
+		}
+	}
+
+	return proto.Marshal(message)
+}
+
+func (protobufCodec) Unmarshal(data []byte, event *Event) error {
+	var message envelopepb.BridgeEvent
+	if err := proto.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	// Fields of a newer schema, dropping them would lose data
+	if len(message.ProtoReflect().GetUnknown()) > 0 {
+		return errors.New("unknown fields")
+	}
+
+	*event = Event{
+		TransactionHash: message.TransactionHash,
+		LogIndex:        uint(message.LogIndex),
+		BlockNumber:     message.BlockNumber,
+		Token:           message.Token,
+		FromChain:       message.FromChain,
+		ToChain:         message.ToChain,
+		DestChainID:     message.DestChainId,
+		BridgeName:      message.BridgeName,
+		BridgeNameRaw:   message.BridgeNameRaw,
+		GasUsed:         message.GasUsed,
+		TxFrom:          message.TxFrom,
+		TxTo:            message.TxTo,
+	}
+	if message.Timestamp != nil {
+		event.Timestamp = message.Timestamp.AsTime()
+	}
+	if message.BlockTimestamp != nil {
+		blockTimestamp := message.BlockTimestamp.AsTime()
+		event.BlockTimestamp = &blockTimestamp
+	}
+	if message.Amount != nil {
+		event.Amount = new(big.Int).SetBytes(message.Amount).String()
+	}
+	if message.EffectiveGasPrice != nil {
+		effectiveGasPrice := new(big.Int).SetBytes(message.EffectiveGasPrice).String()
+		event.EffectiveGasPrice = &effectiveGasPrice
+	}
+	if message.TxStatus != nil {
+		if *message.TxStatus > 1 {
+			return fmt.Errorf("transaction status %d isn't 0 or 1", *message.TxStatus)
+		}
+		txStatus := uint8(*message.TxStatus)
+		event.TxStatus = &txStatus
+	}
+	if message.Args != nil {
+		if err := json.Unmarshal(message.Args, &event.Args); err != nil {
+			return fmt.Errorf("malformed args: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// uint256Bytes returns the decimal integer as big-endian bytes
+func uint256Bytes(name, value string) ([]byte, error) {
+	parsed, ok := new(big.Int).SetString(value, 10)
+	if !ok || parsed.Sign() < 0 || parsed.BitLen() > 256 {
+		return nil, fmt.Errorf("%s %q isn't a uint256", name, value)
+	}
+	return parsed.Bytes(), nil
+}
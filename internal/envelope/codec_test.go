@@ -0,0 +1,87 @@
+package envelope
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestCodecByName(t *testing.T) {
+	codec, err := CodecByName("protobuf")
+	assert.NoError(t, err)
+	assert.Equal(t, Protobuf, codec)
+
+	_, err = CodecByName("avro")
+	assert.Error(t, err)
+}
+
+func TestProtobuf_RoundTrip(t *testing.T) {
+	event := socketBridge()
+	producedAt := time.Date(2024, 12, 14, 15, 0, 3, 0, time.UTC)
+
+	values, err := Encode(Protobuf, event, "ingester-1", producedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "protobuf", values[FieldEncoding])
+
+	env, err := Decode(values)
+
+	assert.NoError(t, err)
+	assert.Equal(t, event, env.Event())
+
+	// uint256 values are carried as bytes
+	jsonValues, err := Encode(JSON, event, "ingester-1", producedAt)
+	assert.NoError(t, err)
+	assert.Less(t, len(values[FieldPayload].(string)), len(jsonValues[FieldPayload].(string))/2)
+}
+
+func TestProtobuf_ZeroValues(t *testing.T) {
+	event := socketBridge()
+	event.Amount = "0"
+	txStatus := uint8(0)
+	event.TxStatus = &txStatus
+	event.BlockTimestamp = nil
+	event.GasUsed = nil
+	event.EffectiveGasPrice = nil
+	event.Payload = nil
+
+	values, err := Encode(Protobuf, event, "ingester-1", time.Now())
+	assert.NoError(t, err)
+	env, err := Decode(values)
+
+	// Zero is told apart from missing
+	assert.NoError(t, err)
+	assert.Equal(t, event, env.Event())
+}
+
+func TestProtobuf_Incompatible(t *testing.T) {
+	valid, err := Encode(Protobuf, socketBridge(), "ingester-1", time.Now())
+	assert.NoError(t, err)
+
+	with := func(name string, value interface{}) map[string]interface{} {
+		values := make(map[string]interface{}, len(valid))
+		for field, value := range valid {
+			values[field] = value
+		}
+		values[name] = value
+		return values
+	}
+
+	// A field added by a newer schema
+	newer := protowire.AppendTag([]byte(valid[FieldPayload].(string)), 99, protowire.VarintType)
+	newer = protowire.AppendVarint(newer, 1)
+
+	for reason, values := range map[string]map[string]interface{}{
+		`unknown encoding "avro"`:   with(FieldEncoding, "avro"),
+		"malformed protobuf":        with(FieldPayload, "\xff\xff"),
+		"unknown fields":            with(FieldPayload, string(newer)),
+		"malformed json payload":    with(FieldEncoding, "json"),
+		"transaction hash \"\" isn": with(FieldPayload, ""),
+	} {
+		_, err := Decode(values)
+
+		assert.ErrorIs(t, err, ErrIncompatible, reason)
+		assert.ErrorContains(t, err, reason)
+	}
+}
@@ -0,0 +1,369 @@
+package envelope
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+)
+
+// SchemaVersion is the version of the envelope written by Encode. Version 1 is the flat
+// map of event fields published before envelopes existed, it's still decoded
+const SchemaVersion = 2
+
+// Stream entry fields of an envelope, the event itself is the payload encoded by the codec named
+// in FieldEncoding. It's left out for JSON, which is how envelopes were first encoded
+const (
+	FieldSchemaVersion = "schemaVersion"
+	FieldEventType     = "eventType"
+	FieldProducerID    = "producerId"
+	FieldProducedAt    = "producedAt"
+	FieldEncoding      = "encoding"
+	FieldPayload       = "payload"
+)
+
+var (
+	// ErrInvalidEvent is returned by Encode for events which would be rejected by consumers
+	ErrInvalidEvent = errors.New("invalid event")
+	// ErrIncompatible is returned by Decode for messages that can't be read, either malformed,
+	// of an unknown version or failing validation. They're moved to the DLQ along with the reason
+	ErrIncompatible = errors.New("incompatible message")
+)
+
+// Envelope wraps an event published to the stream with what's needed to read it back
+type Envelope struct {
+	SchemaVersion int
+	// EventType is the ABI event name of the payload
+	EventType  string
+	ProducerID string
+	ProducedAt time.Time
+	Payload    Event
+}
+
+// Event is the payload of an envelope. Unlike stream entry values its fields keep their types,
+// every argument of the decoded log is in Args
+type Event struct {
+	TransactionHash string     `json:"transactionHash"`
+	LogIndex        uint       `json:"logIndex"`
+	BlockNumber     uint64     `json:"blockNumber"`
+	BlockTimestamp  *time.Time `json:"blockTimestamp,omitempty"`
+	// Timestamp is when the event was ingested
+	Timestamp time.Time `json:"timestamp"`
+
+	// Typed fields of SocketBridge events
+	Token         string `json:"token,omitempty"`
+	Amount        string `json:"amount,omitempty"`
+	FromChain     string `json:"fromChain,omitempty"`
+	ToChain       string `json:"toChain,omitempty"`
+	DestChainID   string `json:"destChainId,omitempty"`
+	BridgeName    string `json:"bridgeName,omitempty"`
+	BridgeNameRaw string `json:"bridgeNameRaw,omitempty"`
+
+	// Receipt fields, left out when the receipt couldn't be fetched
+	GasUsed           *uint64 `json:"gasUsed,omitempty"`
+	EffectiveGasPrice *string `json:"effectiveGasPrice,omitempty"`
+	TxStatus          *uint8  `json:"txStatus,omitempty"`
+	TxFrom            *string `json:"txFrom,omitempty"`
+	TxTo              *string `json:"txTo,omitempty"`
+
+	Args models.EventPayload `json:"args,omitempty"`
+}
+
+// New wraps the event in an envelope of the current version, events without a name are SocketBridge events
+func New(event models.BridgeEvent, producerID string, producedAt time.Time) Envelope {
+	eventType := event.EventName
+	if eventType == "" {
+		eventType = models.EventSocketBridge
+	}
+
+	return Envelope{
+		SchemaVersion: SchemaVersion,
+		EventType:     eventType,
+		ProducerID:    producerID,
+		ProducedAt:    producedAt,
+		Payload: Event{
+			TransactionHash:   event.TransactionHash,
+			LogIndex:          event.LogIndex,
+			BlockNumber:       event.BlockNumber,
+			BlockTimestamp:    event.BlockTimestamp,
+			Timestamp:         event.Timestamp,
+			Token:             event.Token,
+			Amount:            event.Amount,
+			FromChain:         event.FromChain,
+			ToChain:           event.ToChain,
+			DestChainID:       event.DestChainID,
+			BridgeName:        event.BridgeName,
+			BridgeNameRaw:     event.BridgeNameRaw,
+			GasUsed:           event.GasUsed,
+			EffectiveGasPrice: event.EffectiveGasPrice,
+			TxStatus:          event.TxStatus,
+			TxFrom:            event.TxFrom,
+			TxTo:              event.TxTo,
+			Args:              event.Payload,
+		},
+	}
+}
+
+// Event returns the wrapped event
+func (e Envelope) Event() models.BridgeEvent {
+	return models.BridgeEvent{
+		EventName:         e.EventType,
+		TransactionHash:   e.Payload.TransactionHash,
+		LogIndex:          e.Payload.LogIndex,
+		BlockNumber:       e.Payload.BlockNumber,
+		BlockTimestamp:    e.Payload.BlockTimestamp,
+		Timestamp:         e.Payload.Timestamp,
+		Token:             e.Payload.Token,
+		Amount:            e.Payload.Amount,
+		FromChain:         e.Payload.FromChain,
+		ToChain:           e.Payload.ToChain,
+		DestChainID:       e.Payload.DestChainID,
+		BridgeName:        e.Payload.BridgeName,
+		BridgeNameRaw:     e.Payload.BridgeNameRaw,
+		GasUsed:           e.Payload.GasUsed,
+		EffectiveGasPrice: e.Payload.EffectiveGasPrice,
+		TxStatus:          e.Payload.TxStatus,
+		TxFrom:            e.Payload.TxFrom,
+		TxTo:              e.Payload.TxTo,
+		Payload:           e.Payload.Args,
+	}
+}
+
+// Validate checks the envelope holds an event consumers can save
+func (e Envelope) Validate() error {
+	switch {
+	case e.SchemaVersion < 1 || e.SchemaVersion > SchemaVersion:
+		return fmt.Errorf("unsupported schema version %d", e.SchemaVersion)
+	case e.EventType == "":
+		return errors.New("event type is missing")
+	case e.SchemaVersion > 1 && e.ProducerID == "":
+		return errors.New("producer id is missing")
+	case e.SchemaVersion > 1 && e.ProducedAt.IsZero():
+		return errors.New("produced at is missing")
+	}
+
+	event := e.Payload
+	switch {
+	case !isHex(event.TransactionHash):
+		return fmt.Errorf("transaction hash %q isn't hex", event.TransactionHash)
+	case event.Timestamp.IsZero():
+		return errors.New("timestamp is missing")
+	case event.Amount != "" && !isInteger(event.Amount):
+		return fmt.Errorf("amount %q isn't an integer", event.Amount)
+	case event.EffectiveGasPrice != nil && !isInteger(*event.EffectiveGasPrice):
+		return fmt.Errorf("effective gas price %q isn't an integer", *event.EffectiveGasPrice)
+	case event.TxStatus != nil && *event.TxStatus > 1:
+		return fmt.Errorf("transaction status %d isn't 0 or 1", *event.TxStatus)
+	case e.EventType == models.EventSocketBridge && (event.Token == "" || event.Amount == ""):
+		return errors.New("SocketBridge event needs a token and an amount")
+	}
+
+	return nil
+}
+
+// Encode wraps the event in an envelope and returns the values of its stream entry, the payload is encoded with the codec
+func Encode(codec Codec, event models.BridgeEvent, producerID string, producedAt time.Time) (map[string]interface{}, error) {
+	env := New(event, producerID, producedAt)
+	if err := env.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	payload, err := codec.Marshal(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	values := map[string]interface{}{
+		FieldSchemaVersion: strconv.Itoa(env.SchemaVersion),
+		FieldEventType:     env.EventType,
+		FieldProducerID:    env.ProducerID,
+		FieldProducedAt:    env.ProducedAt.UTC().Format(time.RFC3339Nano),
+		FieldPayload:       string(payload),
+	}
+	if codec != JSON {
+		values[FieldEncoding] = codec.Name()
+	}
+
+	return values, nil
+}
+
+// Decode reads the envelope of a stream entry, entries without a schema version are version 1.
+// Errors wrap ErrIncompatible and say why the entry couldn't be read
+func Decode(values map[string]interface{}) (Envelope, error) {
+	env, err := decode(values)
+	if err == nil {
+		err = env.Validate()
+	}
+	if err != nil {
+		return Envelope{}, fmt.Errorf("%w: %v", ErrIncompatible, err)
+	}
+
+	return env, nil
+}
+
+func decode(values map[string]interface{}) (Envelope, error) {
+	if _, ok := values[FieldSchemaVersion]; !ok {
+		return decodeV1(values)
+	}
+
+	fields, err := stringValues(values)
+	if err != nil {
+		return Envelope{}, err
+	}
+	for name := range fields {
+		switch name {
+		case FieldSchemaVersion, FieldEventType, FieldProducerID, FieldProducedAt, FieldEncoding, FieldPayload:
+		default:
+			return Envelope{}, fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	version, err := strconv.Atoi(fields[FieldSchemaVersion])
+	if err != nil {
+		return Envelope{}, fmt.Errorf("schema version %q isn't a number", fields[FieldSchemaVersion])
+	}
+	if version < 2 || version > SchemaVersion {
+		return Envelope{}, fmt.Errorf("unsupported schema version %d", version)
+	}
+
+	env := Envelope{
+		SchemaVersion: version,
+		EventType:     fields[FieldEventType],
+		ProducerID:    fields[FieldProducerID],
+	}
+	if env.ProducedAt, err = time.Parse(time.RFC3339Nano, fields[FieldProducedAt]); err != nil {
+		return Envelope{}, fmt.Errorf("produced at %q isn't a time", fields[FieldProducedAt])
+	}
+
+	codec := JSON
+	if encoding, ok := fields[FieldEncoding]; ok {
+		if codec, err = CodecByName(encoding); err != nil {
+			return Envelope{}, err
+		}
+	}
+	if err := codec.Unmarshal([]byte(fields[FieldPayload]), &env.Payload); err != nil {
+		return Envelope{}, fmt.Errorf("malformed %s payload: %v", codec.Name(), err)
+	}
+
+	return env, nil
+}
+
+// decodeV1 reads the flat map of event fields published before envelopes existed,
+// events without a name were published before other events were decoded
+func decodeV1(values map[string]interface{}) (Envelope, error) {
+	fields, err := stringValues(values)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	env := Envelope{SchemaVersion: 1, EventType: models.EventSocketBridge}
+	event := &env.Payload
+	for name, value := range fields {
+		switch name {
+		case "transactionHash":
+			event.TransactionHash = value
+		case "token":
+			event.Token = value
+		case "amount":
+			event.Amount = value
+		case "fromChain":
+			event.FromChain = value
+		case "toChain":
+			event.ToChain = value
+		case "destChainId":
+			event.DestChainID = value
+		case "bridgeName":
+			event.BridgeName = value
+		case "bridgeNameRaw":
+			event.BridgeNameRaw = value
+		case "timestamp":
+			event.Timestamp, err = parseTime(name, value)
+		case "blockTimestamp":
+			var blockTimestamp time.Time
+			blockTimestamp, err = parseTime(name, value)
+			event.BlockTimestamp = &blockTimestamp
+		case "blockNumber":
+			event.BlockNumber, err = parseUint(name, value, 64)
+		case "logIndex":
+			var logIndex uint64
+			logIndex, err = parseUint(name, value, 32)
+			event.LogIndex = uint(logIndex)
+		case "gasUsed":
+			var gasUsed uint64
+			gasUsed, err = parseUint(name, value, 64)
+			event.GasUsed = &gasUsed
+		case "txStatus":
+			var txStatus uint64
+			txStatus, err = parseUint(name, value, 8)
+			status := uint8(txStatus)
+			event.TxStatus = &status
+		case "effectiveGasPrice":
+			event.EffectiveGasPrice = &value
+		case "txFrom":
+			event.TxFrom = &value
+		case "txTo":
+			event.TxTo = &value
+		case "eventName":
+			env.EventType = value
+		case "payload":
+			err = json.Unmarshal([]byte(value), &event.Args)
+			if err != nil {
+				err = fmt.Errorf("malformed payload: %v", err)
+			}
+		default:
+			err = fmt.Errorf("unknown field %q", name)
+		}
+		if err != nil {
+			return Envelope{}, err
+		}
+	}
+
+	return env, nil
+}
+
+// stringValues returns the values of a stream entry, which are strings when read back from Redis
+func stringValues(values map[string]interface{}) (map[string]string, error) {
+	fields := make(map[string]string, len(values))
+	for name, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("field %q is a %T, not a string", name, value)
+		}
+		fields[name] = text
+	}
+	return fields, nil
+}
+
+// parseTime reads times written by go-redis, in RFC 3339, or formatted with `%v`
+func parseTime(name, value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		// Monotonic clock readings are appended by `%v`
+		parsed, err := time.Parse(layout, strings.SplitN(value, " m=", 2)[0])
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s %q isn't a time", name, value)
+}
+
+func parseUint(name, value string, bitSize int) (uint64, error) {
+	parsed, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s %q isn't a %d bit unsigned integer", name, value, bitSize)
+	}
+	return parsed, nil
+}
+
+func isHex(value string) bool {
+	digits, ok := strings.CutPrefix(value, "0x")
+	return ok && digits != "" && strings.Trim(strings.ToLower(digits), "0123456789abcdef") == ""
+}
+
+// isInteger reports whether the value is a non negative decimal integer, as uint256 values are
+func isInteger(value string) bool {
+	return value != "" && strings.Trim(value, "0123456789") == ""
+}
@@ -0,0 +1,143 @@
+package envelope
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func socketBridge() models.BridgeEvent {
+	blockTimestamp := time.Date(2024, 12, 14, 14, 59, 47, 0, time.UTC)
+	gasUsed := uint64(184223)
+	gasPrice := "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+	txStatus := uint8(1)
+	txFrom := "0x3a23F943181408EAC424116Af7b7790c94Cb97a5"
+
+	return models.BridgeEvent{
+		EventName:         models.EventSocketBridge,
+		TransactionHash:   "0x9f8e7d6c",
+		LogIndex:          3,
+		BlockNumber:       21400118,
+		BlockTimestamp:    &blockTimestamp,
+		Timestamp:         time.Date(2024, 12, 14, 15, 0, 2, 123456789, time.UTC),
+		Token:             "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE",
+		Amount:            "1000000000000000000",
+		DestChainID:       "10",
+		BridgeName:        "hop",
+		BridgeNameRaw:     "0x9ddd",
+		GasUsed:           &gasUsed,
+		EffectiveGasPrice: &gasPrice,
+		TxStatus:          &txStatus,
+		TxFrom:            &txFrom,
+		Payload:           models.EventPayload{"amount": "1000000000000000000", "toChainId": "10"},
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	event := socketBridge()
+	producedAt := time.Date(2024, 12, 14, 15, 0, 3, 0, time.UTC)
+
+	values, err := Encode(JSON, event, "ingester-1", producedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", values[FieldSchemaVersion])
+	assert.Equal(t, models.EventSocketBridge, values[FieldEventType])
+
+	env, err := Decode(values)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ingester-1", env.ProducerID)
+	assert.True(t, producedAt.Equal(env.ProducedAt))
+	assert.Equal(t, event, env.Event())
+}
+
+func TestEncode_RejectsInvalidEvents(t *testing.T) {
+	missingAmount := socketBridge()
+	missingAmount.Amount = ""
+	badStatus := socketBridge()
+	status := uint8(2)
+	badStatus.TxStatus = &status
+	badHash := socketBridge()
+	badHash.TransactionHash = "9f8e7d6c"
+
+	for _, event := range []models.BridgeEvent{missingAmount, badStatus, badHash} {
+		_, err := Encode(JSON, event, "ingester-1", time.Now())
+		assert.ErrorIs(t, err, ErrInvalidEvent)
+	}
+
+	_, err := Encode(JSON, socketBridge(), "", time.Now())
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+}
+
+func TestDecode_Version1(t *testing.T) {
+	// Published before envelopes, every value is a string once read back from Redis
+	values := map[string]interface{}{
+		"transactionHash": "0x9f8e7d6c",
+		"token":           "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE",
+		"amount":          "1000",
+		"fromChain":       "",
+		"toChain":         "",
+		"destChainId":     "10",
+		"bridgeName":      "hop",
+		"bridgeNameRaw":   "0x9ddd",
+		"timestamp":       "2024-12-14T15:00:02.123456789Z",
+		"blockNumber":     "21400118",
+		"logIndex":        "3",
+		"blockTimestamp":  "2024-12-14 14:59:47 +0000 UTC",
+		"gasUsed":         "184223",
+		"txStatus":        "1",
+		"txFrom":          "0x3a23F943181408EAC424116Af7b7790c94Cb97a5",
+	}
+
+	env, err := Decode(values)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, env.SchemaVersion)
+
+	event := env.Event()
+	// Published before other events were decoded
+	assert.Equal(t, models.EventSocketBridge, event.EventName)
+	assert.Equal(t, uint64(21400118), event.BlockNumber)
+	assert.Equal(t, uint(3), event.LogIndex)
+	assert.Equal(t, time.Date(2024, 12, 14, 15, 0, 2, 123456789, time.UTC), event.Timestamp)
+	if assert.NotNil(t, event.BlockTimestamp) {
+		assert.True(t, time.Date(2024, 12, 14, 14, 59, 47, 0, time.UTC).Equal(*event.BlockTimestamp))
+	}
+	assert.Equal(t, uint64(184223), *event.GasUsed)
+	assert.Equal(t, uint8(1), *event.TxStatus)
+}
+
+func TestDecode_Incompatible(t *testing.T) {
+	valid, err := Encode(JSON, socketBridge(), "ingester-1", time.Now())
+	assert.NoError(t, err)
+
+	with := func(name string, value interface{}) map[string]interface{} {
+		values := make(map[string]interface{}, len(valid))
+		for field, value := range valid {
+			values[field] = value
+		}
+		values[name] = value
+		return values
+	}
+
+	for reason, values := range map[string]map[string]interface{}{
+		"unsupported schema version 3":  with(FieldSchemaVersion, "3"),
+		"isn't a number":                with(FieldSchemaVersion, "two"),
+		`unknown field "source"`:        with("source", "ingester-1"),
+		"malformed json payload":        with(FieldPayload, `{"transactionHash":"0x9f8e7d6c","logIndex":"3"}`),
+		`unknown field "chainId"`:       with(FieldPayload, `{"transactionHash":"0x9f8e7d6c","chainId":1}`),
+		"producer id is missing":        with(FieldProducerID, ""),
+		`produced at "yesterday"`:       with(FieldProducedAt, "yesterday"),
+		`blockNumber "-1" isn't a 64`:   {"transactionHash": "0x9f8e7d6c", "blockNumber": "-1"},
+		`unknown field "blockHash"`:     {"transactionHash": "0x9f8e7d6c", "blockHash": "0x01"},
+		"needs a token and an amount":   {"transactionHash": "0x9f8e7d6c", "timestamp": "2024-12-14T15:00:02Z"},
+		`field "amount" is a int`:       {"transactionHash": "0x9f8e7d6c", "amount": 1000},
+		`amount "1e18" isn't an intege`: {"transactionHash": "0x9f8e7d6c", "timestamp": "2024-12-14T15:00:02Z", "token": "0xEeee", "amount": "1e18"},
+	} {
+		_, err := Decode(values)
+
+		assert.ErrorIs(t, err, ErrIncompatible, reason)
+		assert.ErrorContains(t, err, reason)
+	}
+}
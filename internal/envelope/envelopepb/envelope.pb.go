@@ -0,0 +1,340 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: internal/envelope/envelopepb/envelope.proto
+
+package envelopepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// BridgeEvent is the payload of a stream envelope encoded with protobuf, see envelope.Event.
+// uint256 values are big-endian bytes, which carry them exactly in a fraction of their decimal text
+type BridgeEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionHash string                 `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	LogIndex        uint32                 `protobuf:"varint,2,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	BlockNumber     uint64                 `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	BlockTimestamp  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
+	// Timestamp is when the event was ingested
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Token         string                 `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+	Amount        []byte                 `protobuf:"bytes,7,opt,name=amount,proto3,oneof" json:"amount,omitempty"`
+	FromChain     string                 `protobuf:"bytes,8,opt,name=from_chain,json=fromChain,proto3" json:"from_chain,omitempty"`
+	ToChain       string                 `protobuf:"bytes,9,opt,name=to_chain,json=toChain,proto3" json:"to_chain,omitempty"`
+	DestChainId   string                 `protobuf:"bytes,10,opt,name=dest_chain_id,json=destChainId,proto3" json:"dest_chain_id,omitempty"`
+	BridgeName    string                 `protobuf:"bytes,11,opt,name=bridge_name,json=bridgeName,proto3" json:"bridge_name,omitempty"`
+	BridgeNameRaw string                 `protobuf:"bytes,12,opt,name=bridge_name_raw,json=bridgeNameRaw,proto3" json:"bridge_name_raw,omitempty"`
+	// Receipt fields, left out when the receipt couldn't be fetched
+	GasUsed           *uint64 `protobuf:"varint,13,opt,name=gas_used,json=gasUsed,proto3,oneof" json:"gas_used,omitempty"`
+	EffectiveGasPrice []byte  `protobuf:"bytes,14,opt,name=effective_gas_price,json=effectiveGasPrice,proto3,oneof" json:"effective_gas_price,omitempty"`
+	TxStatus          *uint32 `protobuf:"varint,15,opt,name=tx_status,json=txStatus,proto3,oneof" json:"tx_status,omitempty"`
+	TxFrom            *string `protobuf:"bytes,16,opt,name=tx_from,json=txFrom,proto3,oneof" json:"tx_from,omitempty"`
+	TxTo              *string `protobuf:"bytes,17,opt,name=tx_to,json=txTo,proto3,oneof" json:"tx_to,omitempty"`
+	// Args is every argument of the log as JSON, see models.EventPayload. Integers are decimal
+	// strings there, google.protobuf.Struct would turn them into doubles
+	Args []byte `protobuf:"bytes,18,opt,name=args,proto3" json:"args,omitempty"`
+}
+
+func (x *BridgeEvent) Reset() {
+	*x = BridgeEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_envelope_envelopepb_envelope_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BridgeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BridgeEvent) ProtoMessage() {}
+
+func (x *BridgeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_envelope_envelopepb_envelope_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BridgeEvent.ProtoReflect.Descriptor instead.
+func (*BridgeEvent) Descriptor() ([]byte, []int) {
+	return file_internal_envelope_envelopepb_envelope_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BridgeEvent) GetTransactionHash() string {
+	if x != nil {
+		return x.TransactionHash
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetLogIndex() uint32 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *BridgeEvent) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *BridgeEvent) GetBlockTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BlockTimestamp
+	}
+	return nil
+}
+
+func (x *BridgeEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *BridgeEvent) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetAmount() []byte {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *BridgeEvent) GetFromChain() string {
+	if x != nil {
+		return x.FromChain
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetToChain() string {
+	if x != nil {
+		return x.ToChain
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetDestChainId() string {
+	if x != nil {
+		return x.DestChainId
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetBridgeName() string {
+	if x != nil {
+		return x.BridgeName
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetBridgeNameRaw() string {
+	if x != nil {
+		return x.BridgeNameRaw
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetGasUsed() uint64 {
+	if x != nil && x.GasUsed != nil {
+		return *x.GasUsed
+	}
+	return 0
+}
+
+func (x *BridgeEvent) GetEffectiveGasPrice() []byte {
+	if x != nil {
+		return x.EffectiveGasPrice
+	}
+	return nil
+}
+
+func (x *BridgeEvent) GetTxStatus() uint32 {
+	if x != nil && x.TxStatus != nil {
+		return *x.TxStatus
+	}
+	return 0
+}
+
+func (x *BridgeEvent) GetTxFrom() string {
+	if x != nil && x.TxFrom != nil {
+		return *x.TxFrom
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetTxTo() string {
+	if x != nil && x.TxTo != nil {
+		return *x.TxTo
+	}
+	return ""
+}
+
+func (x *BridgeEvent) GetArgs() []byte {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+var File_internal_envelope_envelopepb_envelope_proto protoreflect.FileDescriptor
+
+var file_internal_envelope_envelopepb_envelope_proto_rawDesc = []byte{
+	0x0a, 0x2b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x65, 0x6e, 0x76, 0x65, 0x6c,
+	0x6f, 0x70, 0x65, 0x2f, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x70, 0x62, 0x2f, 0x65,
+	0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x14, 0x62,
+	0x72, 0x69, 0x64, 0x67, 0x69, 0x6e, 0x67, 0x2e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65,
+	0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe8, 0x05, 0x0a, 0x0b, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x21, 0x0a, 0x0c,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x43, 0x0a, 0x0f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01,
+	0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x69, 0x6e,
+	0x12, 0x19, 0x0a, 0x08, 0x74, 0x6f, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x74, 0x6f, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x22, 0x0a, 0x0d, 0x64,
+	0x65, 0x73, 0x74, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x64, 0x12,
+	0x1f, 0x0a, 0x0b, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x26, 0x0a, 0x0f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f,
+	0x72, 0x61, 0x77, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x62, 0x72, 0x69, 0x64, 0x67,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x61, 0x77, 0x12, 0x1e, 0x0a, 0x08, 0x67, 0x61, 0x73, 0x5f,
+	0x75, 0x73, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x07, 0x67, 0x61,
+	0x73, 0x55, 0x73, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x13, 0x65, 0x66, 0x66, 0x65,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x67, 0x61, 0x73, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x02, 0x52, 0x11, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x47, 0x61, 0x73, 0x50, 0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a,
+	0x09, 0x74, 0x78, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x03, 0x52, 0x08, 0x74, 0x78, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x1c, 0x0a, 0x07, 0x74, 0x78, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x04, 0x52, 0x06, 0x74, 0x78, 0x46, 0x72, 0x6f, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a,
+	0x05, 0x74, 0x78, 0x5f, 0x74, 0x6f, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x48, 0x05, 0x52, 0x04,
+	0x74, 0x78, 0x54, 0x6f, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x67, 0x61, 0x73, 0x5f, 0x75,
+	0x73, 0x65, 0x64, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x5f, 0x67, 0x61, 0x73, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x74, 0x78, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x74, 0x78,
+	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x78, 0x5f, 0x74, 0x6f, 0x42,
+	0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x74,
+	0x68, 0x2d, 0x62, 0x72, 0x69, 0x64, 0x67, 0x69, 0x6e, 0x67, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2f, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x2f, 0x65, 0x6e, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_internal_envelope_envelopepb_envelope_proto_rawDescOnce sync.Once
+	file_internal_envelope_envelopepb_envelope_proto_rawDescData = file_internal_envelope_envelopepb_envelope_proto_rawDesc
+)
+
+func file_internal_envelope_envelopepb_envelope_proto_rawDescGZIP() []byte {
+	file_internal_envelope_envelopepb_envelope_proto_rawDescOnce.Do(func() {
+		file_internal_envelope_envelopepb_envelope_proto_rawDescData = protoimpl.X.CompressGZIP(file_internal_envelope_envelopepb_envelope_proto_rawDescData)
+	})
+	return file_internal_envelope_envelopepb_envelope_proto_rawDescData
+}
+
+var file_internal_envelope_envelopepb_envelope_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_internal_envelope_envelopepb_envelope_proto_goTypes = []any{
+	(*BridgeEvent)(nil),           // 0: bridging.envelope.v1.BridgeEvent
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_internal_envelope_envelopepb_envelope_proto_depIdxs = []int32{
+	1, // 0: bridging.envelope.v1.BridgeEvent.block_timestamp:type_name -> google.protobuf.Timestamp
+	1, // 1: bridging.envelope.v1.BridgeEvent.timestamp:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_internal_envelope_envelopepb_envelope_proto_init() }
+func file_internal_envelope_envelopepb_envelope_proto_init() {
+	if File_internal_envelope_envelopepb_envelope_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_internal_envelope_envelopepb_envelope_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*BridgeEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_internal_envelope_envelopepb_envelope_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_internal_envelope_envelopepb_envelope_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_internal_envelope_envelopepb_envelope_proto_goTypes,
+		DependencyIndexes: file_internal_envelope_envelopepb_envelope_proto_depIdxs,
+		MessageInfos:      file_internal_envelope_envelopepb_envelope_proto_msgTypes,
+	}.Build()
+	File_internal_envelope_envelopepb_envelope_proto = out.File
+	file_internal_envelope_envelopepb_envelope_proto_rawDesc = nil
+	file_internal_envelope_envelopepb_envelope_proto_goTypes = nil
+	file_internal_envelope_envelopepb_envelope_proto_depIdxs = nil
+}
@@ -0,0 +1,320 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/pkg/decimal"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	// watermarkFile keeps the last exported event id within the export directory
+	watermarkFile = "_watermark.json"
+	// partitionLayout is the hive style date partition directory name
+	partitionLayout = "2006-01-02"
+)
+
+// ParquetRow is the warehouse friendly layout of a bridge event
+//
+// Raw amount is a DECIMAL(78, 0), which fits any uint256, hashes, addresses and
+// the raw bridge name are fixed length binary, and timestamp is a UTC microsecond TIMESTAMP
+type ParquetRow struct {
+	ID              int64     `parquet:"id"`
+	TransactionHash [32]byte  `parquet:"transaction_hash"`
+	BlockNumber     int64     `parquet:"block_number"`
+	LogIndex        int32     `parquet:"log_index"`
+	Timestamp       time.Time `parquet:"timestamp,timestamp(microsecond)"`
+	Token           [20]byte  `parquet:"token"`
+	TokenSymbol     *string   `parquet:"token_symbol,optional"`
+	TokenDecimals   *int32    `parquet:"token_decimals,optional"`
+	AmountRaw       [33]byte  `parquet:"amount_raw,decimal(0:78)"`
+	Amount          *string   `parquet:"amount,optional"`
+	Sender          [20]byte  `parquet:"sender"`
+	Receiver        [20]byte  `parquet:"receiver"`
+	DestChainID     *int64    `parquet:"dest_chain_id,optional"`
+	BridgeName      string    `parquet:"bridge_name"`
+	BridgeNameRaw   [32]byte  `parquet:"bridge_name_raw"`
+
+	// Transaction receipt, null if it couldn't be fetched
+	GasUsed           *int64    `parquet:"gas_used,optional"`
+	EffectiveGasPrice *string   `parquet:"effective_gas_price,optional"`
+	TxStatus          *int32    `parquet:"tx_status,optional"`
+	TxFrom            *[20]byte `parquet:"tx_from,optional"`
+	TxTo              *[20]byte `parquet:"tx_to,optional"`
+}
+
+// Watermark is the position an incremental parquet export continues from
+type Watermark struct {
+	LastID    uint      `json:"last_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Horizons are the greatest ids seen by recent runs which aren't exported yet
+	Horizons []Horizon `json:"horizons,omitempty"`
+}
+
+// Horizon is the greatest event id visible at a point in time
+//
+// Ids are taken when an event is inserted but only become visible once its transaction commits,
+// so a lower id may show up after a greater one. Every event up to a horizon was inserted before
+// it was seen, once the horizon is older than the export lag they are all expected to be committed
+type Horizon struct {
+	ID     uint      `json:"id"`
+	SeenAt time.Time `json:"seen_at"`
+}
+
+// safeID returns the greatest id every lower one is expected to be visible for at `now`
+func (w Watermark) safeID(now time.Time, lag time.Duration) uint {
+	safe := w.LastID
+	for _, horizon := range w.Horizons {
+		if !horizon.SeenAt.After(now.Add(-lag)) && horizon.ID > safe {
+			safe = horizon.ID
+		}
+	}
+	return safe
+}
+
+// NewParquetRow converts an event to its parquet layout
+func NewParquetRow(event models.BridgeEvent, cfg *config.Config) (ParquetRow, error) {
+	row := ParquetRow{
+		ID:              int64(event.ID),
+		TransactionHash: common.HexToHash(event.TransactionHash),
+		BlockNumber:     int64(event.BlockNumber),
+		LogIndex:        int32(event.LogIndex),
+		Timestamp:       event.Timestamp.UTC(),
+		Token:           common.HexToAddress(event.Token),
+		Sender:          common.HexToAddress(event.FromChain),
+		Receiver:        common.HexToAddress(event.ToChain),
+		BridgeName:      event.BridgeName,
+		BridgeNameRaw:   common.HexToHash(event.BridgeNameRaw),
+	}
+
+	amount, ok := new(big.Int).SetString(event.Amount, 10)
+	if !ok || amount.Sign() < 0 {
+		return row, fmt.Errorf("invalid amount %q of event %d", event.Amount, event.ID)
+	}
+	// Big endian two's complement, amount is never negative so it's just left padded
+	amount.FillBytes(row.AmountRaw[:])
+
+	if token, ok := cfg.GetTokenDetails(event.Token); ok {
+		decimals := int32(token.Decimals)
+		formatted := decimal.Format(amount, token.Decimals)
+		row.TokenSymbol = &token.Symbol
+		row.TokenDecimals = &decimals
+		row.Amount = &formatted
+	}
+
+	if chainID, err := strconv.ParseInt(event.DestChainID, 10, 64); err == nil {
+		row.DestChainID = &chainID
+	}
+
+	if event.GasUsed != nil {
+		gasUsed := int64(*event.GasUsed)
+		row.GasUsed = &gasUsed
+	}
+	if event.TxStatus != nil {
+		status := int32(*event.TxStatus)
+		row.TxStatus = &status
+	}
+	if event.TxFrom != nil {
+		from := [20]byte(common.HexToAddress(*event.TxFrom))
+		row.TxFrom = &from
+	}
+	if event.TxTo != nil {
+		to := [20]byte(common.HexToAddress(*event.TxTo))
+		row.TxTo = &to
+	}
+	row.EffectiveGasPrice = event.EffectiveGasPrice
+
+	return row, nil
+}
+
+// ParquetExporter writes events into a date partitioned directory layout
+//
+//	<dir>/date=2024-12-14/part-<first id>-<last id>.parquet
+//
+// Every run continues from the watermark stored in the directory, so only
+// events saved since the previous run are exported, into new part files.
+// Events are held back until they were visible for `lag`, see Horizon
+type ParquetExporter struct {
+	dir string
+	lag time.Duration
+	cfg *config.Config
+}
+
+// partitionWriter is an open part file of a single date partition
+type partitionWriter struct {
+	file    *os.File
+	writer  *parquet.GenericWriter[ParquetRow]
+	date    string
+	firstID int
+	lastID  int
+}
+
+func NewParquetExporter(dir string, lag time.Duration, cfg *config.Config) *ParquetExporter {
+	return &ParquetExporter{dir: dir, lag: lag, cfg: cfg}
+}
+
+// ReadWatermark returns the stored watermark, zero if nothing has been exported yet
+func (e *ParquetExporter) ReadWatermark() (Watermark, error) {
+	var watermark Watermark
+
+	data, err := os.ReadFile(filepath.Join(e.dir, watermarkFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return watermark, nil
+	}
+	if err != nil {
+		return watermark, err
+	}
+
+	err = json.Unmarshal(data, &watermark)
+	return watermark, err
+}
+
+// Export writes every event after the watermark and advances it, returns the number of exported events
+//
+// stream is expected to walk events matching the filter in ascending id order,
+// e.g. BridgeEventRepository.StreamEvents. The watermark is only advanced once
+// every part file is complete, so a failed run is simply retried. Events newer than
+// the horizon of `lag` ago are left for a later run, see Horizon
+func (e *ParquetExporter) Export(ctx context.Context, stream func(context.Context, models.EventFilter, func(models.BridgeEvent) error) error) (int, error) {
+	watermark, err := e.ReadWatermark()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read watermark: %w", err)
+	}
+
+	now := time.Now().UTC()
+	safeID := watermark.safeID(now, e.lag)
+	writers := make(map[string]*partitionWriter)
+	rows := 0
+	var seenID uint
+
+	err = stream(ctx, models.EventFilter{AfterID: watermark.LastID, EventName: models.EventSocketBridge}, func(event models.BridgeEvent) error {
+		if uint(event.ID) > seenID {
+			seenID = uint(event.ID)
+		}
+		// A lower id may still be committed, it's exported by a later run
+		if e.lag > 0 && uint(event.ID) > safeID {
+			return nil
+		}
+
+		row, err := NewParquetRow(event, e.cfg)
+		if err != nil {
+			return err
+		}
+
+		date := row.Timestamp.Format(partitionLayout)
+		writer, ok := writers[date]
+		if !ok {
+			if writer, err = e.openPartition(date, event.ID); err != nil {
+				return err
+			}
+			writers[date] = writer
+		}
+
+		if _, err := writer.writer.Write([]ParquetRow{row}); err != nil {
+			return err
+		}
+		writer.lastID = event.ID
+		rows++
+
+		if uint(event.ID) > watermark.LastID {
+			watermark.LastID = uint(event.ID)
+		}
+		return nil
+	})
+
+	// Part files are always closed, but only published if the whole export succeeded
+	for _, writer := range writers {
+		if closeErr := e.closePartition(writer, err == nil); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Horizons up to the watermark are done with, the greatest id seen now becomes a new one
+	var horizons []Horizon
+	for _, horizon := range watermark.Horizons {
+		if horizon.ID > watermark.LastID {
+			horizons = append(horizons, horizon)
+		}
+	}
+	if seenID > watermark.LastID && (len(horizons) == 0 || seenID > horizons[len(horizons)-1].ID) {
+		horizons = append(horizons, Horizon{ID: seenID, SeenAt: now})
+	}
+	changed := !slices.Equal(horizons, watermark.Horizons)
+	watermark.Horizons = horizons
+
+	if rows > 0 || changed {
+		watermark.UpdatedAt = now
+		if err := e.writeWatermark(watermark); err != nil {
+			return 0, fmt.Errorf("failed to write watermark: %w", err)
+		}
+	}
+
+	return rows, nil
+}
+
+func (e *ParquetExporter) openPartition(date string, firstID int) (*partitionWriter, error) {
+	partitionDir := filepath.Join(e.dir, "date="+date)
+	if err := os.MkdirAll(partitionDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	// Written under a temporary name, readers only ever see complete files
+	file, err := os.CreateTemp(partitionDir, ".part-*.parquet.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	return &partitionWriter{
+		file:    file,
+		writer:  parquet.NewGenericWriter[ParquetRow](file, parquet.Compression(&parquet.Snappy)),
+		date:    date,
+		firstID: firstID,
+		lastID:  firstID,
+	}, nil
+}
+
+// closePartition finishes the part file and renames it to its final name if publish is set,
+// otherwise the temporary file is removed
+func (e *ParquetExporter) closePartition(writer *partitionWriter, publish bool) error {
+	err := writer.writer.Close()
+	if closeErr := writer.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil || !publish {
+		os.Remove(writer.file.Name())
+		return err
+	}
+
+	name := fmt.Sprintf("part-%d-%d.parquet", writer.firstID, writer.lastID)
+	return os.Rename(writer.file.Name(), filepath.Join(e.dir, "date="+writer.date, name))
+}
+
+func (e *ParquetExporter) writeWatermark(watermark Watermark) error {
+	data, err := json.Marshal(watermark)
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(e.dir, watermarkFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(e.dir, watermarkFile))
+}
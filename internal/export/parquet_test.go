@@ -0,0 +1,147 @@
+package export
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// sliceStream streams events after the filter watermark, like BridgeEventRepository.StreamEvents
+func sliceStream(events []models.BridgeEvent) func(context.Context, models.EventFilter, func(models.BridgeEvent) error) error {
+	return func(ctx context.Context, filter models.EventFilter, fn func(models.BridgeEvent) error) error {
+		for _, event := range events {
+			if !filter.Matches(event) {
+				continue
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestNewParquetRow(t *testing.T) {
+	row, err := NewParquetRow(testEvent, config.LoadConfig())
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(2), row.ID)
+	assert.Equal(t, "1372483935", new(big.Int).SetBytes(row.AmountRaw[:]).String())
+	assert.Equal(t, "1372.483935", *row.Amount)
+	assert.Equal(t, "USDC", *row.TokenSymbol)
+	assert.Equal(t, int32(6), *row.TokenDecimals)
+	assert.Equal(t, int64(10), *row.DestChainID)
+
+	invalid := testEvent
+	invalid.Amount = "not a number"
+	_, err = NewParquetRow(invalid, config.LoadConfig())
+	assert.Error(t, err)
+}
+
+func TestParquetExporterIncremental(t *testing.T) {
+	dir := t.TempDir()
+	exporter := NewParquetExporter(dir, 0, config.LoadConfig())
+
+	nextDay := testEvent
+	nextDay.ID = 3
+	nextDay.Timestamp = testEvent.Timestamp.Add(24 * time.Hour)
+	events := []models.BridgeEvent{testEvent, nextDay}
+
+	rows, err := exporter.Export(context.Background(), sliceStream(events))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rows)
+
+	watermark, err := exporter.ReadWatermark()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), watermark.LastID)
+
+	read, err := parquet.ReadFile[ParquetRow](filepath.Join(dir, "date=2024-12-14", "part-2-2.parquet"))
+	assert.NoError(t, err)
+	assert.Len(t, read, 1)
+	assert.Equal(t, int64(2), read[0].ID)
+	assert.True(t, testEvent.Timestamp.Equal(read[0].Timestamp))
+	assert.Equal(t, "1372483935", new(big.Int).SetBytes(read[0].AmountRaw[:]).String())
+
+	_, err = os.Stat(filepath.Join(dir, "date=2024-12-15", "part-3-3.parquet"))
+	assert.NoError(t, err)
+
+	// Nothing new since the watermark
+	rows, err = exporter.Export(context.Background(), sliceStream(events))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rows)
+
+	later := testEvent
+	later.ID = 4
+	rows, err = exporter.Export(context.Background(), sliceStream(append(events, later)))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rows)
+
+	_, err = os.Stat(filepath.Join(dir, "date=2024-12-14", "part-4-4.parquet"))
+	assert.NoError(t, err)
+}
+
+func TestParquetExporter_ReceiptColumns(t *testing.T) {
+	dir := t.TempDir()
+	exporter := NewParquetExporter(dir, 0, config.LoadConfig())
+
+	gasUsed, status, from := uint64(21000), uint8(0), "0x0041B0239420DebF7885433d09AE4f274d3d8AC3"
+	enriched := testEvent
+	enriched.GasUsed, enriched.TxStatus, enriched.TxFrom = &gasUsed, &status, &from
+
+	_, err := exporter.Export(context.Background(), sliceStream([]models.BridgeEvent{enriched}))
+	assert.NoError(t, err)
+
+	read, err := parquet.ReadFile[ParquetRow](filepath.Join(dir, "date=2024-12-14", "part-2-2.parquet"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), *read[0].GasUsed)
+	assert.Equal(t, int32(0), *read[0].TxStatus)
+	assert.Equal(t, from, common.Address(*read[0].TxFrom).Hex())
+	// Contract creation, or the receipt wasn't fetched
+	assert.Nil(t, read[0].TxTo)
+	assert.Nil(t, read[0].EffectiveGasPrice)
+}
+
+func TestParquetExporter_Lag(t *testing.T) {
+	dir := t.TempDir()
+	exporter := NewParquetExporter(dir, time.Hour, config.LoadConfig())
+
+	// Event 3 is committed before event 2
+	committed := testEvent
+	committed.ID = 3
+	rows, err := exporter.Export(context.Background(), sliceStream([]models.BridgeEvent{committed}))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rows)
+
+	watermark, err := exporter.ReadWatermark()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), watermark.LastID)
+	if !assert.Len(t, watermark.Horizons, 1) {
+		return
+	}
+
+	// Once the horizon is older than the lag, both are exported
+	watermark.Horizons[0].SeenAt = watermark.Horizons[0].SeenAt.Add(-time.Hour)
+	assert.NoError(t, exporter.writeWatermark(watermark))
+
+	later := testEvent
+	later.ID = 4
+	rows, err = exporter.Export(context.Background(), sliceStream([]models.BridgeEvent{testEvent, committed, later}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rows)
+
+	watermark, err = exporter.ReadWatermark()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), watermark.LastID)
+	if assert.Len(t, watermark.Horizons, 1) {
+		assert.Equal(t, uint(4), watermark.Horizons[0].ID)
+	}
+}
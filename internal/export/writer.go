@@ -0,0 +1,277 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/pkg/decimal"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Writer writes bridge events one at a time, nothing is buffered beyond the underlying writer
+type Writer interface {
+	WriteEvent(event models.BridgeEvent) error
+	// Flush writes any buffered data to the underlying writer
+	Flush() error
+}
+
+// Record is a single exported event, amounts are exported both raw
+// and formatted using the token's decimals
+//
+// USDValue is only exported when quoted in USD, it's empty if the event wasn't valued
+type Record struct {
+	ID              int    `json:"id"`
+	TransactionHash string `json:"transaction_hash"`
+	BlockNumber     uint64 `json:"block_number"`
+	LogIndex        uint   `json:"log_index"`
+	Timestamp       string `json:"timestamp"`
+	Token           string `json:"token"`
+	TokenSymbol     string `json:"token_symbol"`
+	AmountRaw       string `json:"amount_raw"`
+	Amount          string `json:"amount"`
+	Sender          string `json:"sender"`
+	Receiver        string `json:"receiver"`
+	DestChainID     string `json:"dest_chain_id"`
+	BridgeName      string `json:"bridge_name"`
+	BridgeNameRaw   string `json:"bridge_name_raw"`
+
+	// Transaction receipt, empty if it couldn't be fetched
+	GasUsed           string `json:"gas_used"`
+	EffectiveGasPrice string `json:"effective_gas_price"`
+	TxStatus          string `json:"tx_status"`
+	TxFrom            string `json:"tx_from"`
+	TxTo              string `json:"tx_to"`
+
+	// Every argument of the event, columns above are empty for events other than SocketBridge
+	EventName string              `json:"event_name"`
+	Payload   models.EventPayload `json:"payload"`
+
+	USDValue string `json:"usd_value,omitempty"`
+}
+
+// csvHeader matches the order of Record.csvRow
+var csvHeader = []string{
+	"id",
+	"transaction_hash",
+	"block_number",
+	"log_index",
+	"timestamp",
+	"token",
+	"token_symbol",
+	"amount_raw",
+	"amount",
+	"sender",
+	"receiver",
+	"dest_chain_id",
+	"bridge_name",
+	"bridge_name_raw",
+	"gas_used",
+	"effective_gas_price",
+	"tx_status",
+	"tx_from",
+	"tx_to",
+	"event_name",
+	"payload",
+}
+
+// NewWriter returns a writer for the format, either `csv` or `ndjson`,
+// the USD value of events is included when quote is `USD`
+func NewWriter(format string, w io.Writer, cfg *config.Config, quote string) (Writer, error) {
+	quoted := quote == models.QuoteUSD
+
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), cfg: cfg, quoted: quoted}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w), cfg: cfg, quoted: quoted}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// ContentType returns the HTTP content type of the format
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// NewRecord converts an event to its exported form
+//
+// Amount of unknown tokens can't be formatted, so it's left empty rather than being wrong.
+// An amount which isn't an integer is an error, like in NewParquetRow
+func NewRecord(event models.BridgeEvent, cfg *config.Config) (Record, error) {
+	record := Record{
+		ID:              event.ID,
+		TransactionHash: event.TransactionHash,
+		BlockNumber:     event.BlockNumber,
+		LogIndex:        event.LogIndex,
+		Timestamp:       event.Timestamp.UTC().Format(time.RFC3339Nano),
+		Token:           event.Token,
+		AmountRaw:       event.Amount,
+		Sender:          event.FromChain,
+		Receiver:        event.ToChain,
+		DestChainID:     event.DestChainID,
+		BridgeName:      event.BridgeName,
+		BridgeNameRaw:   event.BridgeNameRaw,
+
+		EventName: event.EventName,
+		Payload:   event.Payload,
+	}
+
+	if event.USDValue != nil {
+		record.USDValue = decimal.Trim(*event.USDValue)
+	}
+	if event.GasUsed != nil {
+		record.GasUsed = strconv.FormatUint(*event.GasUsed, 10)
+	}
+	if event.EffectiveGasPrice != nil {
+		record.EffectiveGasPrice = *event.EffectiveGasPrice
+	}
+	if event.TxStatus != nil {
+		record.TxStatus = strconv.FormatUint(uint64(*event.TxStatus), 10)
+	}
+	if event.TxFrom != nil {
+		record.TxFrom = *event.TxFrom
+	}
+	if event.TxTo != nil {
+		record.TxTo = *event.TxTo
+	}
+
+	if token, ok := cfg.GetTokenDetails(event.Token); ok {
+		amount, err := decimal.FormatString(event.Amount, token.Decimals)
+		if err != nil {
+			return record, fmt.Errorf("invalid amount %q of event %d: %w", event.Amount, event.ID, err)
+		}
+		record.TokenSymbol = token.Symbol
+		record.Amount = amount
+	}
+
+	return record, nil
+}
+
+// csvRow renders the record in the order of csvHeader, followed by usd_value when quoted.
+// Payload is rendered as a JSON object
+func (r Record) csvRow(quoted bool) ([]string, error) {
+	var payload string
+	if r.Payload != nil {
+		encoded, err := json.Marshal(r.Payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = string(encoded)
+	}
+
+	row := []string{
+		strconv.Itoa(r.ID),
+		r.TransactionHash,
+		strconv.FormatUint(r.BlockNumber, 10),
+		strconv.FormatUint(uint64(r.LogIndex), 10),
+		r.Timestamp,
+		r.Token,
+		r.TokenSymbol,
+		r.AmountRaw,
+		r.Amount,
+		r.Sender,
+		r.Receiver,
+		r.DestChainID,
+		r.BridgeName,
+		r.BridgeNameRaw,
+		r.GasUsed,
+		r.EffectiveGasPrice,
+		r.TxStatus,
+		r.TxFrom,
+		r.TxTo,
+		r.EventName,
+		payload,
+	}
+	if quoted {
+		row = append(row, r.USDValue)
+	}
+
+	return row, nil
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	cfg           *config.Config
+	quoted        bool
+	headerWritten bool
+}
+
+func (c *csvWriter) WriteEvent(event models.BridgeEvent) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	record, err := NewRecord(event, c.cfg)
+	if err != nil {
+		return err
+	}
+	row, err := record.csvRow(c.quoted)
+	if err != nil {
+		return err
+	}
+	return c.w.Write(row)
+}
+
+// Flush writes the header as well, so an empty export is still a valid csv
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+
+	header := csvHeader
+	if c.quoted {
+		header = append(header[:len(header):len(header)], "usd_value")
+	}
+	if err := c.w.Write(header); err != nil {
+		return err
+	}
+
+	c.headerWritten = true
+	return nil
+}
+
+type ndjsonWriter struct {
+	enc    *json.Encoder
+	cfg    *config.Config
+	quoted bool
+}
+
+func (n *ndjsonWriter) WriteEvent(event models.BridgeEvent) error {
+	record, err := NewRecord(event, n.cfg)
+	if err != nil {
+		return err
+	}
+	if !n.quoted {
+		record.USDValue = ""
+	}
+
+	// Encode terminates every record with a newline
+	return n.enc.Encode(record)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testEvent = models.BridgeEvent{
+	ID:              2,
+	Token:           "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+	Amount:          "1372483935",
+	FromChain:       "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+	ToChain:         "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+	DestChainID:     "10",
+	Timestamp:       time.Date(2024, 12, 14, 14, 17, 3, 0, time.UTC),
+	TransactionHash: "0x995f960af8eefc632cdd9b89b546f4069a4098b2b40fd25840048f59d5ee5106",
+	BlockNumber:     21400000,
+	LogIndex:        12,
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, config.LoadConfig(), "")
+	assert.NoError(t, err)
+
+	assert.NoError(t, writer.WriteEvent(testEvent))
+	assert.NoError(t, writer.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(csvHeader, ","), lines[0])
+	assert.Contains(t, lines[1], ",USDC,1372483935,1372.483935,")
+}
+
+func TestCSVWriter_EmptyExportHasHeader(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, config.LoadConfig(), "")
+	assert.NoError(t, err)
+
+	assert.NoError(t, writer.Flush())
+	assert.Equal(t, strings.Join(csvHeader, ",")+"\n", buf.String())
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatNDJSON, &buf, config.LoadConfig(), "")
+	assert.NoError(t, err)
+
+	unknownToken := testEvent
+	unknownToken.Token = "0x1111111111111111111111111111111111111111"
+
+	assert.NoError(t, writer.WriteEvent(testEvent))
+	assert.NoError(t, writer.WriteEvent(unknownToken))
+	assert.NoError(t, writer.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var record Record
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "1372.483935", record.Amount)
+	assert.Equal(t, "2024-12-14T14:17:03Z", record.Timestamp)
+
+	// Unknown tokens are exported raw only
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "", record.Amount)
+	assert.Equal(t, "1372483935", record.AmountRaw)
+}
+
+func TestCSVWriter_QuotedInUSD(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, config.LoadConfig(), models.QuoteUSD)
+	assert.NoError(t, err)
+
+	usdValue := "1372.5100000000"
+	valued := testEvent
+	valued.USDValue = &usdValue
+
+	assert.NoError(t, writer.WriteEvent(valued))
+	assert.NoError(t, writer.WriteEvent(testEvent))
+	assert.NoError(t, writer.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, strings.Join(csvHeader, ",")+",usd_value", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], ",1372.51"))
+	// Events without a price are exported with an empty value
+	assert.True(t, strings.HasSuffix(lines[2], ","))
+}
+
+func TestNewWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewWriter("xlsx", &bytes.Buffer{}, config.LoadConfig(), "")
+	assert.Error(t, err)
+}
+
+func TestNDJSONWriter_InvalidAmount(t *testing.T) {
+	writer, err := NewWriter(FormatNDJSON, &bytes.Buffer{}, config.LoadConfig(), "")
+	assert.NoError(t, err)
+
+	invalid := testEvent
+	invalid.Amount = "1e18"
+
+	// Exporting it without a formatted amount would look like an unknown token
+	assert.ErrorContains(t, writer.WriteEvent(invalid), `invalid amount "1e18" of event 2`)
+}
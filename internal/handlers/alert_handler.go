@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlertHandler struct {
+	service services.AlertService
+}
+
+// alertRuleRequest is the body accepted when creating an alert rule
+type alertRuleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Token       string   `json:"token"`
+	MinAmount   string   `json:"min_amount"`
+	Addresses   []string `json:"addresses"`
+	DestChainID string   `json:"dest_chain_id"`
+	BridgeName  string   `json:"bridge_name"`
+	Notifier    string   `json:"notifier" binding:"required"`
+	Target      string   `json:"target"`
+}
+
+func NewAlertHandler(service services.AlertService) *AlertHandler {
+	return &AlertHandler{
+		service: service,
+	}
+}
+
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	var req alertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := models.AlertRule{
+		Name:        req.Name,
+		Token:       normalizeToken(req.Token),
+		Addresses:   strings.Join(req.Addresses, ","),
+		DestChainID: req.DestChainID,
+		BridgeName:  req.BridgeName,
+		Notifier:    req.Notifier,
+		Target:      req.Target,
+	}
+	if req.MinAmount != "" {
+		rule.MinAmount = &req.MinAmount
+	}
+
+	if err := h.service.CreateRule(&rule); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"rule": rule})
+}
+
+func (h *AlertHandler) ListRules(c *gin.Context) {
+	rules, err := h.service.ListRules()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRule(id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListAlerts returns alert history using the same keyset pagination as events
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	limit := 10
+	maxLimit := 100
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		limit = min(parsedLimit, maxLimit)
+	}
+
+	var lastID uint
+	if lastIDStr := c.Query("last_id"); lastIDStr != "" {
+		parsedID, err := strconv.ParseUint(lastIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid last_id parameter"})
+			return
+		}
+		lastID = uint(parsedID)
+	}
+
+	var ruleID int
+	if ruleIDStr := c.Query("rule_id"); ruleIDStr != "" {
+		parsedID, err := strconv.Atoi(ruleIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule_id parameter"})
+			return
+		}
+		ruleID = parsedID
+	}
+
+	alerts, err := h.service.ListAlerts(ruleID, lastID, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if len(alerts) > 0 {
+		lastID = uint(alerts[len(alerts)-1].ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts":  alerts,
+		"last_id": lastID,
+	})
+}
+
+func (h *AlertHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alert rule not found"})
+	case errors.Is(err, services.ErrInvalidAlertRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArchivedBeforeHeader is set on event responses whose range reaches into archived events
+const ArchivedBeforeHeader = "X-Archived-Before"
+
+type ArchiveHandler struct {
+	service services.ArchiveService
+}
+
+func NewArchiveHandler(service services.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{
+		service: service,
+	}
+}
+
+// ListRanges returns every archived range along with its object key
+func (h *ArchiveHandler) ListRanges(c *gin.Context) {
+	ranges, err := h.service.ListRanges()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	archivedBefore, err := h.service.ArchivedBefore()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"archived_before": formatArchivedBefore(archivedBefore),
+		"ranges":          ranges,
+	})
+}
+
+// archivedBefore returns the archive boundary if the filter's range reaches before it,
+// nil otherwise, so responses only mention the archive when it's relevant
+func archivedBefore(service services.ArchiveService, filter models.EventFilter) (*time.Time, error) {
+	before, err := service.ArchivedBefore()
+	if err != nil || before.IsZero() {
+		return nil, err
+	}
+
+	if !filter.From.IsZero() && !filter.From.Before(before) {
+		return nil, nil
+	}
+
+	return &before, nil
+}
+
+// formatArchivedBefore renders the archive boundary, nil if nothing has been archived
+func formatArchivedBefore(before time.Time) *string {
+	if before.IsZero() {
+		return nil
+	}
+
+	formatted := before.UTC().Format(time.RFC3339)
+	return &formatted
+}
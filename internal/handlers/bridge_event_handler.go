@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/eth-bridging/config"
 	"github.com/eth-bridging/internal/services"
@@ -12,12 +13,14 @@ import (
 )
 
 type BridgeEventHandler struct {
-	service services.BridgeEventService
+	service        services.BridgeEventService
+	archiveService services.ArchiveService
 }
 
-func NewBridgeEventHandler(service services.BridgeEventService) *BridgeEventHandler {
+func NewBridgeEventHandler(service services.BridgeEventService, archiveService services.ArchiveService) *BridgeEventHandler {
 	return &BridgeEventHandler{
-		service: service,
+		service:        service,
+		archiveService: archiveService,
 	}
 }
 
@@ -60,21 +63,64 @@ func (h *BridgeEventHandler) GetEvents(c *gin.Context) {
 		lastID = uint(parsedID)
 	}
 
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := parseQuoteParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Pages are ordered by timestamp, so the timestamp of the last event bounds the next page,
+	// letting postgres skip monthly partitions of newer events
+	lastTimestamp, err := parseTimeParam(c, "last_timestamp")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !lastTimestamp.IsZero() {
+		// `to` is exclusive, timestamps are stored with microsecond precision
+		upper := lastTimestamp.Add(time.Microsecond)
+		if filter.To.IsZero() || upper.Before(filter.To) {
+			filter.To = upper
+		}
+	}
+
 	// Fetch events
-	events, err := h.service.GetAllEvents(lastID, limit, currency)
+	events, err := h.service.GetAllEvents(filter, lastID, limit, currency, quote)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	var nextTimestamp string
 	if len(events) > 0 {
 		lastEvent := events[len(events)-1]
 		lastID = uint(lastEvent.ID)
+		nextTimestamp = lastEvent.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+
+	// Older events might have been moved to object storage, let the client know
+	before, err := archivedBefore(h.archiveService, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"events":         events,
+		"last_id":        lastID,
+		"next_timestamp": nextTimestamp,
+	}
+	if before != nil {
+		c.Header(ArchivedBeforeHeader, before.UTC().Format(time.RFC3339))
+		response["archived_before"] = before.UTC().Format(time.RFC3339)
 	}
 
 	// Return the response with pagination info
-	c.JSON(http.StatusOK, gin.H{
-		"events":  events,
-		"last_id": lastID,
-	})
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BridgeNameHandler struct {
+	service services.BridgeNameService
+}
+
+func NewBridgeNameHandler(service services.BridgeNameService) *BridgeNameHandler {
+	return &BridgeNameHandler{
+		service: service,
+	}
+}
+
+// ListUnknown returns the raw bridge names events were saved with because they couldn't be decoded
+func (h *BridgeNameHandler) ListUnknown(c *gin.Context) {
+	names, err := h.service.ListUnknown()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bridge_names": names})
+}
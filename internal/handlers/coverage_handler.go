@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/eth-bridging/internal/coverage"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CoverageHandler struct {
+	monitor *coverage.Monitor
+}
+
+func NewCoverageHandler(monitor *coverage.Monitor) *CoverageHandler {
+	return &CoverageHandler{
+		monitor: monitor,
+	}
+}
+
+// GetCoverage returns the block ranges ingested on every chain and the gaps between them
+func (h *CoverageHandler) GetCoverage(c *gin.Context) {
+	chains, err := h.monitor.Coverage()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	gaps := 0
+	for _, chain := range chains {
+		gaps += len(chain.Gaps)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chains": chains,
+		"gaps":   gaps,
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/export"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushEvery is the number of rows written between flushes to the client
+const exportFlushEvery = 500
+
+type ExportHandler struct {
+	service        services.BridgeEventService
+	archiveService services.ArchiveService
+	cfg            *config.Config
+}
+
+func NewExportHandler(service services.BridgeEventService, archiveService services.ArchiveService, cfg *config.Config) *ExportHandler {
+	return &ExportHandler{
+		service:        service,
+		archiveService: archiveService,
+		cfg:            cfg,
+	}
+}
+
+// ExportEvents streams every event matching the filter as `csv` or `ndjson`,
+// rows are written as they're read from the database cursor
+func (h *ExportHandler) ExportEvents(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatCSV))
+
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := parseQuoteParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	writer, err := export.NewWriter(format, c.Writer, h.cfg, quote)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format parameter, must be csv or ndjson"})
+		return
+	}
+
+	// Events before the archive boundary are only in object storage, see `/archive/ranges`
+	before, err := archivedBefore(h.archiveService, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if before != nil {
+		c.Header(ArchivedBeforeHeader, before.UTC().Format(time.RFC3339))
+	}
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="bridge_events.%s"`, format))
+	c.Status(http.StatusOK)
+
+	rows := 0
+	err = h.service.StreamEvents(c.Request.Context(), filter, func(event models.BridgeEvent) error {
+		if err := writer.WriteEvent(event); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Status is already sent, all we can do is stop, leaving a truncated export
+		log.Printf("Error exporting events after %d rows: %v", rows, err)
+		return
+	}
+
+	if err := writer.Flush(); err != nil {
+		log.Printf("Error flushing export: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// payloadParamPrefix prefixes query parameters filtering on a top level payload field, e.g. `payload.routeId=404`
+const payloadParamPrefix = "payload."
+
+// payloadFieldPattern matches ABI argument names, and the `argN` unnamed ones are stored as
+var payloadFieldPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// parseEventFilter reads the common event filter query parameters
+//
+//	token, dest_chain_id, bridge_name, event_name, payload.<field>, from, to
+//
+// `from` and `to` are expected in RFC3339 format, token is normalised
+// to its checksum address as that is how it is stored
+func parseEventFilter(c *gin.Context) (models.EventFilter, error) {
+	filter := models.EventFilter{
+		Token:       c.Query("token"),
+		DestChainID: c.Query("dest_chain_id"),
+		BridgeName:  c.Query("bridge_name"),
+		EventName:   c.Query("event_name"),
+	}
+
+	filter.Token = normalizeToken(filter.Token)
+
+	for param, values := range c.Request.URL.Query() {
+		field, ok := strings.CutPrefix(param, payloadParamPrefix)
+		if !ok {
+			continue
+		}
+		if !payloadFieldPattern.MatchString(field) {
+			return filter, fmt.Errorf("Invalid %s parameter", param)
+		}
+
+		if filter.Payload == nil {
+			filter.Payload = make(map[string]string)
+		}
+		filter.Payload[field] = values[0]
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeParam(c, "to"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseTimeParam parses an optional RFC3339 query parameter,
+// zero time is returned if parameter is not provided
+func parseTimeParam(c *gin.Context, name string) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid %s parameter", name)
+	}
+
+	return parsed, nil
+}
+
+// parseQuoteParam reads the optional `quote` query parameter, `USD` is the only supported value
+func parseQuoteParam(c *gin.Context) (string, error) {
+	quote := strings.ToUpper(c.Query("quote"))
+	if quote != "" && quote != models.QuoteUSD {
+		return "", errors.New("Invalid quote parameter, only USD is supported")
+	}
+
+	return quote, nil
+}
+
+// normalizeToken converts a token address to its checksum form, as that is how it is stored
+func normalizeToken(token string) string {
+	if token != "" && common.IsHexAddress(token) {
+		return common.HexToAddress(token).Hex()
+	}
+
+	return token
+}
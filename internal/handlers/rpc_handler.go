@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	ethereum "github.com/eth-bridging/pkg/go-eth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RPCHandler struct {
+	endpoints *ethereum.EndpointPool
+}
+
+func NewRPCHandler(endpoints *ethereum.EndpointPool) *RPCHandler {
+	return &RPCHandler{
+		endpoints: endpoints,
+	}
+}
+
+// ListEndpoints returns the health of every RPC endpoint, along with which one the subscription is on
+func (h *RPCHandler) ListEndpoints(c *gin.Context) {
+	statuses := h.endpoints.Statuses()
+
+	healthy := 0
+	for _, status := range statuses {
+		if status.Healthy {
+			healthy++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"endpoints": statuses,
+		"healthy":   healthy,
+	})
+}
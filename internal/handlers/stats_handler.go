@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultStatsRange is used when `from` is not provided
+const defaultStatsRange = 7 * 24 * time.Hour
+
+type StatsHandler struct {
+	service services.BridgeStatsService
+}
+
+func NewStatsHandler(service services.BridgeStatsService) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+	}
+}
+
+func (h *StatsHandler) GetVolume(c *gin.Context) {
+	interval := strings.ToLower(c.DefaultQuery("interval", "day"))
+	currency := strings.ToUpper(c.Query("currency"))
+
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := parseQuoteParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default to the last week, so a bare request doesn't aggregate the whole table
+	if filter.To.IsZero() {
+		filter.To = time.Now().UTC()
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-defaultStatsRange)
+	}
+
+	stats, err := h.service.GetVolume(interval, filter, currency, quote)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInterval) || errors.Is(err, services.ErrInvalidTimeRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interval": interval,
+		"from":     filter.From,
+		"to":       filter.To,
+		"stats":    stats,
+	})
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/broadcast"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamHeartbeatInterval keeps idle connections, and proxies in between, alive
+	streamHeartbeatInterval = 15 * time.Second
+	// streamWriteTimeout drops websocket clients that stopped reading
+	streamWriteTimeout = 10 * time.Second
+	// streamReplayPageSize is the page size used to replay missed events on resume
+	streamReplayPageSize = 100
+)
+
+// errSubscriberTooSlow is reported to clients dropped by the hub for not keeping up
+var errSubscriberTooSlow = errors.New("client is too slow, reconnect with the last received event id")
+
+type StreamHandler struct {
+	service  services.BridgeEventService
+	hub      *broadcast.Hub
+	upgrader websocket.Upgrader
+}
+
+// streamRequest holds the parameters shared by SSE and WebSocket streams
+type streamRequest struct {
+	filter   models.EventFilter
+	currency string
+	quote    string
+	lastID   uint
+}
+
+func NewStreamHandler(service services.BridgeEventService, hub *broadcast.Hub) *StreamHandler {
+	return &StreamHandler{
+		service: service,
+		hub:     hub,
+		upgrader: websocket.Upgrader{
+			// Stream is read only public data, so it's fine to be consumed from any origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// StreamEventsSSE pushes newly persisted events using Server-Sent Events
+//
+// Every event is sent with its ID, so browsers resume automatically
+// using the `Last-Event-ID` header after a reconnect
+func (h *StreamHandler) StreamEventsSSE(c *gin.Context) {
+	req, err := h.parseStreamRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Disable response buffering in nginx, otherwise events are delivered in chunks
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	send := func(event models.BridgeEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: bridge_event\ndata: %s\n\n", event.ID, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	ping := func() error {
+		if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	if err := h.streamEvents(c.Request.Context(), req, send, ping); err != nil {
+		// Headers are already sent, so let the client know why the stream ended
+		data, _ := json.Marshal(gin.H{"error": err.Error()})
+		fmt.Fprintf(c.Writer, "event: error\ndata: %s\n\n", data)
+		c.Writer.Flush()
+	}
+}
+
+// StreamEventsWS pushes newly persisted events over a WebSocket, one JSON event per message
+//
+// Clients resume by reconnecting with `last_event_id` query parameter
+func (h *StreamHandler) StreamEventsWS(c *gin.Context) {
+	req, err := h.parseStreamRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader has already replied with the error
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Pongs and close frames are only processed while reading, the stream
+	// ends once the client goes away or misses two heartbeats in a row
+	conn.SetReadDeadline(time.Now().Add(2 * streamHeartbeatInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * streamHeartbeatInterval))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(event models.BridgeEvent) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return conn.WriteJSON(event)
+	}
+	ping := func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout))
+	}
+
+	if err := h.streamEvents(ctx, req, send, ping); err != nil {
+		closeMessage := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error())
+		conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(streamWriteTimeout))
+	}
+}
+
+// parseStreamRequest reads the list endpoint filters along with the resume position,
+// which is taken from `Last-Event-ID` header, falling back to `last_event_id` query parameter
+func (h *StreamHandler) parseStreamRequest(c *gin.Context) (streamRequest, error) {
+	req := streamRequest{
+		currency: strings.ToUpper(c.Query("currency")),
+	}
+
+	var err error
+	if req.filter, err = parseEventFilter(c); err != nil {
+		return req, err
+	}
+	if req.quote, err = parseQuoteParam(c); err != nil {
+		return req, err
+	}
+
+	lastIDStr := c.GetHeader("Last-Event-ID")
+	if lastIDStr == "" {
+		lastIDStr = c.Query("last_event_id")
+	}
+	if lastIDStr != "" {
+		parsedID, err := strconv.ParseUint(lastIDStr, 10, 64)
+		if err != nil {
+			return req, errors.New("Invalid last event id")
+		}
+		req.lastID = uint(parsedID)
+	}
+
+	return req, nil
+}
+
+// streamEvents replays events saved after the resume position, then forwards live events
+// until the context is done, the client falls behind or sending fails
+func (h *StreamHandler) streamEvents(ctx context.Context, req streamRequest, send func(models.BridgeEvent) error, ping func() error) error {
+	// Subscribe before replaying, so nothing saved in the meantime is missed
+	sub := h.hub.Subscribe(req.filter)
+	defer h.hub.Unsubscribe(sub)
+
+	lastID := req.lastID
+	if lastID != 0 {
+		for {
+			events, err := h.service.GetEventsAfter(req.filter, lastID, streamReplayPageSize, req.currency, req.quote)
+			if err != nil {
+				return err
+			}
+			for _, event := range events {
+				if err := send(event); err != nil {
+					return err
+				}
+				lastID = uint(event.ID)
+			}
+			if len(events) < streamReplayPageSize {
+				break
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Closed():
+			return errSubscriberTooSlow
+		case <-heartbeat.C:
+			if err := ping(); err != nil {
+				return err
+			}
+		case event := <-sub.Events():
+			// Already sent while replaying
+			if uint(event.ID) <= lastID {
+				continue
+			}
+
+			// Live events carry the raw WEI amount
+			h.service.FormatAmounts(&event, req.currency, req.quote)
+
+			if err := send(event); err != nil {
+				return err
+			}
+			lastID = uint(event.ID)
+		}
+	}
+}
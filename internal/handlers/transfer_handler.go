@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMetricsWindow is the period completion metrics are computed over when none is given
+const defaultMetricsWindow = 24 * time.Hour
+
+// refundRequest is the body accepted when refunding a transfer
+type refundRequest struct {
+	// LogIndex picks the transfer of the transaction
+	LogIndex *uint  `json:"log_index" binding:"required"`
+	Reason   string `json:"reason" binding:"required"`
+}
+
+type TransferHandler struct {
+	service services.TransferService
+}
+
+func NewTransferHandler(service services.TransferService) *TransferHandler {
+	return &TransferHandler{
+		service: service,
+	}
+}
+
+// ListTransfers returns transfers of tracked bridges with their status, using the same keyset pagination as events
+func (h *TransferHandler) ListTransfers(c *gin.Context) {
+	lastID, limit, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	filter := models.TransferFilter{
+		BridgeName:  c.Query("bridge_name"),
+		DestChainID: c.Query("dest_chain_id"),
+		Status:      c.Query("status"),
+	}
+
+	transfers, err := h.service.ListTransfers(filter, lastID, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if len(transfers) > 0 {
+		lastID = uint(transfers[len(transfers)-1].Source.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfers": transfers,
+		"last_id":   lastID,
+	})
+}
+
+// GetTransfers returns the transfers of a source transaction, a transaction may bridge more than once
+func (h *TransferHandler) GetTransfers(c *gin.Context) {
+	transfers, err := h.service.GetTransfers(c.Param("tx_hash"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if len(transfers) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No transfers in the transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": transfers})
+}
+
+// ListStuck returns transfers which breached the SLA of their route and aren't completed yet, newest first
+func (h *TransferHandler) ListStuck(c *gin.Context) {
+	lastID, limit, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	breaches, err := h.service.ListStuck(c.Query("bridge_name"), c.Query("dest_chain_id"), lastID, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if len(breaches) > 0 {
+		lastID = uint(breaches[len(breaches)-1].ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stuck":   breaches,
+		"last_id": lastID,
+	})
+}
+
+// GetMetrics returns completion latency percentiles of every bridge over `window`, a duration e.g. `24h`
+func (h *TransferHandler) GetMetrics(c *gin.Context) {
+	window := defaultMetricsWindow
+	if windowStr := c.Query("window"); windowStr != "" {
+		parsed, err := time.ParseDuration(windowStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window parameter"})
+			return
+		}
+		window = parsed
+	}
+
+	metrics, err := h.service.CompletionMetrics(window)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"window":  window.String(),
+		"bridges": metrics,
+	})
+}
+
+// GetHistory returns the transfers of a source transaction with every state they went through
+func (h *TransferHandler) GetHistory(c *gin.Context) {
+	histories, err := h.service.GetHistory(c.Param("tx_hash"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if len(histories) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No transfers in the transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": histories})
+}
+
+// Refund records that the transfer was refunded to the sender, only confirmed or finalized transfers can be
+func (h *TransferHandler) Refund(c *gin.Context) {
+	var req refundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.service.Refund(c.Param("tx_hash"), *req.LogIndex, req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfer": history})
+}
+
+// parsePage reads `last_id` and `limit`, limit defaults to 10 and is at most 100.
+// A bad request is responded to when they're invalid
+func parsePage(c *gin.Context) (uint, int, bool) {
+	limit := 10
+	maxLimit := 100
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return 0, 0, false
+		}
+		limit = min(parsedLimit, maxLimit)
+	}
+
+	var lastID uint
+	if lastIDStr := c.Query("last_id"); lastIDStr != "" {
+		parsedID, err := strconv.ParseUint(lastIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid last_id parameter"})
+			return 0, 0, false
+		}
+		lastID = uint(parsedID)
+	}
+
+	return lastID, limit, true
+}
+
+func (h *TransferHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTransferStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTransferNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/eth-bridging/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	service services.WebhookService
+}
+
+// webhookRequest is the body accepted when creating or updating a webhook
+type webhookRequest struct {
+	URL         string `json:"url" binding:"required"`
+	Secret      string `json:"secret"`
+	Token       string `json:"token"`
+	DestChainID string `json:"dest_chain_id"`
+	BridgeName  string `json:"bridge_name"`
+	// Active is only used on update, defaults to true
+	Active *bool `json:"active"`
+}
+
+func NewWebhookHandler(service services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// CreateWebhook registers a new webhook, the secret is only ever returned in this response
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook := req.toModel()
+	if err := h.service.CreateWebhook(&webhook); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"webhook": webhook})
+}
+
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.service.ListWebhooks()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.service.GetWebhook(id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	webhook.Secret = ""
+	c.JSON(http.StatusOK, gin.H{"webhook": webhook})
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(id, req.toModel())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	webhook.Secret = ""
+	c.JSON(http.StatusOK, gin.H{"webhook": webhook})
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteWebhook(id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries returns the most recent delivery attempts of a webhook
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (h *WebhookHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+	case errors.Is(err, services.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (r webhookRequest) toModel() models.Webhook {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+
+	return models.Webhook{
+		URL:         r.URL,
+		Secret:      r.Secret,
+		Token:       normalizeToken(r.Token),
+		DestChainID: r.DestChainID,
+		BridgeName:  r.BridgeName,
+		Active:      active,
+	}
+}
+
+// parseIDParam parses the `:id` path parameter, replies with bad request if invalid
+func parseIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id parameter"})
+		return 0, false
+	}
+
+	return id, true
+}
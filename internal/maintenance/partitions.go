@@ -0,0 +1,79 @@
+package maintenance
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/repositories"
+)
+
+const (
+	// partitionCheckInterval is how often missing partitions are looked for
+	partitionCheckInterval = 24 * time.Hour
+	// PartitionMonthsAhead is the number of months partitions are created ahead of the current one
+	PartitionMonthsAhead = 3
+)
+
+// PartitionMaintainer keeps monthly `bridge_events` partitions created ahead of time,
+// so events never land in the default partition
+type PartitionMaintainer struct {
+	repo   repositories.PartitionRepository
+	now    func() time.Time
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPartitionMaintainer(repo repositories.PartitionRepository) *PartitionMaintainer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &PartitionMaintainer{
+		repo:   repo,
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start ensures partitions right away and then once a day
+func (m *PartitionMaintainer) Start() {
+	m.wg.Add(1)
+	go m.run()
+}
+
+// Stop waits for an in flight check to finish
+func (m *PartitionMaintainer) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// EnsurePartitions creates missing partitions from the current month up to PartitionMonthsAhead
+func (m *PartitionMaintainer) EnsurePartitions() error {
+	created, err := m.repo.EnsurePartitions(m.now().UTC(), PartitionMonthsAhead)
+	for _, name := range created {
+		log.Printf("Created partition %s", name)
+	}
+
+	return err
+}
+
+func (m *PartitionMaintainer) run() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(partitionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.EnsurePartitions(); err != nil {
+			log.Printf("Error ensuring partitions: %v", err)
+		}
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// AlertRule describes transfers we want to be told about
+//
+// Every non empty condition must hold for a rule to match, e.g. a rule with
+// Token and MinAmount matches transfers of that token of at least MinAmount WEI
+type AlertRule struct {
+	ID   int    `gorm:"primaryKey" json:"id"`
+	Name string `json:"name"`
+	// Token only matches transfers of this token
+	Token string `json:"token"`
+	// MinAmount only matches transfers of at least this many WEI
+	MinAmount *string `json:"min_amount"`
+	// Addresses is a comma separated watchlist, matched against sender and receiver
+	Addresses   string `json:"addresses"`
+	DestChainID string `json:"dest_chain_id"`
+	BridgeName  string `json:"bridge_name"`
+	// Notifier is the name of the notifier used when the rule matches, e.g. `log`, `webhook`, `email`
+	Notifier string `json:"notifier"`
+	// Target is notifier specific, e.g. URL for `webhook`, recipient for `email`
+	Target    string    `json:"target"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName implements schema.Tabler, see BridgeEvent.TableName
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// AddressList returns the watched addresses in lowercase
+func (r AlertRule) AddressList() []string {
+	var addresses []string
+	for _, address := range strings.Split(r.Addresses, ",") {
+		if address = strings.ToLower(strings.TrimSpace(address)); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// Alert is a single rule match, kept as history
+type Alert struct {
+	ID              int       `gorm:"primaryKey" json:"id"`
+	RuleID          int       `json:"rule_id"`
+	RuleName        string    `json:"rule_name"`
+	EventID         int       `json:"event_id"`
+	TransactionHash string    `json:"transaction_hash"`
+	Message         string    `json:"message"`
+	Notifier        string    `json:"notifier"`
+	Notified        bool      `json:"notified"`
+	Error           string    `json:"error"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TableName implements schema.Tabler
+func (Alert) TableName() string {
+	return "alerts"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ArchivedRange is a time range of events moved from `bridge_events` to object storage
+//
+// Events in [RangeStart, RangeEnd) are in the object at ObjectKey, gzipped NDJSON,
+// a range may span several objects if late events were archived in a later run
+type ArchivedRange struct {
+	ID         int       `gorm:"primaryKey" json:"id"`
+	RangeStart time.Time `json:"range_start"`
+	RangeEnd   time.Time `json:"range_end"`
+	ObjectKey  string    `json:"object_key"`
+	RowCount   int64     `json:"row_count"`
+	MinEventID int       `json:"min_event_id"`
+	MaxEventID int       `json:"max_event_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName implements schema.Tabler
+func (ArchivedRange) TableName() string {
+	return "archived_ranges"
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type BridgeEvent struct {
 	ID              int    `gorm:"primaryKey"`
@@ -9,6 +12,99 @@ type BridgeEvent struct {
 	TxnCurrency     string `json:"txn_currency"`
 	FromChain       string `gorm:"size:50"`
 	ToChain         string `gorm:"size:50"`
+	DestChainID     string `gorm:"size:78"`
+	BridgeName      string `gorm:"size:66"`
+	BridgeNameRaw   string `gorm:"size:66"`
 	Timestamp       time.Time
 	TransactionHash string
+	BlockNumber     uint64 `json:",string"`
+	LogIndex        uint   `json:",string"`
+	// BlockTimestamp is the time of the block the event was emitted in, Timestamp is when it was ingested,
+	// or the block time as well for events replayed from past blocks e.g. by backfills
+	BlockTimestamp *time.Time
+	// USDValue is the value of Amount at BlockTimestamp, nil if the token or its price is unknown
+	USDValue *string `gorm:"column:usd_value" json:"usd_value,omitempty"`
+
+	// Taken from the transaction receipt, nil if it couldn't be fetched. TxStatus is 1 for success,
+	// TxFrom is the account that sent the transaction and TxTo the contract it called
+	GasUsed           *uint64 `json:",string"`
+	EffectiveGasPrice *string
+	TxStatus          *uint8  `json:",string"`
+	TxFrom            *string `gorm:"size:42"`
+	TxTo              *string `gorm:"size:42"`
+
+	// EventName is the ABI event the log was decoded as and Payload every argument of it,
+	// indexed or not. Typed columns above are only filled in for SocketBridge events
+	EventName string       `gorm:"size:100"`
+	Payload   EventPayload `gorm:"type:jsonb" json:",omitempty"`
+
+	// Only filled in for API responses, where Amount is converted to the requested currency
+	AmountRaw       string  `gorm:"-" json:"amount_raw,omitempty"`
+	AmountFormatted *string `gorm:"-" json:"amount_formatted,omitempty"`
+}
+
+// TableName pins the table created by `db/migrations`, rather than relying on
+// GORM's naming strategy, which would silently change with a renamed struct
+func (BridgeEvent) TableName() string {
+	return "bridge_events"
+}
+
+// EventFilter narrows down bridge events by route and time range
+//
+// Zero values are ignored, so an empty filter matches every event
+type EventFilter struct {
+	// AfterID only matches events with a greater id, used for incremental reads
+	AfterID     uint
+	Token       string
+	DestChainID string
+	BridgeName  string
+	From        time.Time
+	To          time.Time
+
+	// EventName only matches events decoded as this ABI event, Payload matches
+	// top level payload fields by their text, see EventPayload.Field
+	EventName string
+	Payload   map[string]string
+}
+
+// Matches reports whether the event satisfies every non zero field of the filter,
+// it mirrors the conditions applied by the repository for in memory checks
+func (f EventFilter) Matches(event BridgeEvent) bool {
+	if f.AfterID != 0 && uint(event.ID) <= f.AfterID {
+		return false
+	}
+	if f.Token != "" && !strings.EqualFold(f.Token, event.Token) {
+		return false
+	}
+	if f.DestChainID != "" && f.DestChainID != event.DestChainID {
+		return false
+	}
+	if f.BridgeName != "" && f.BridgeName != event.BridgeName {
+		return false
+	}
+	if f.EventName == EventSocketBridge && !event.IsSocketBridge() {
+		return false
+	}
+	if f.EventName != "" && f.EventName != EventSocketBridge && f.EventName != event.EventName {
+		return false
+	}
+	for name, value := range f.Payload {
+		if field, ok := event.Payload.Field(name); !ok || field != value {
+			return false
+		}
+	}
+	if !f.From.IsZero() && event.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !event.Timestamp.Before(f.To) {
+		return false
+	}
+
+	return true
+}
+
+// IsSocketBridge reports whether the event has its typed columns filled in, events
+// without a name were saved or published before other events were decoded
+func (e BridgeEvent) IsSocketBridge() bool {
+	return e.EventName == "" || e.EventName == EventSocketBridge
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StoredBridgeName is a raw bridge name of saved events along with the name it's decoded to
+type StoredBridgeName struct {
+	Raw  string `gorm:"column:bridge_name_raw"`
+	Name string `gorm:"column:bridge_name"`
+}
+
+// UnknownBridgeName is a raw bridge name that couldn't be decoded, so it's used as is.
+//
+// FirstSeen and LastSeen are the UTC days of the first and last event with it
+type UnknownBridgeName struct {
+	BridgeName string    `json:"bridge_name"`
+	EventCount int64     `json:"event_count"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+}
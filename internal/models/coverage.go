@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// BlockRange is a range of blocks of a chain whose logs were all ingested, live or backfilled.
+// Overlapping and adjacent ranges are merged, CreatedAt is when the earliest of them was recorded
+type BlockRange struct {
+	ID        int       `gorm:"primaryKey" json:"-"`
+	ChainID   string    `gorm:"size:78" json:"chain_id"`
+	FromBlock uint64    `json:"from_block"`
+	ToBlock   uint64    `json:"to_block"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName implements schema.Tabler, see BridgeEvent.TableName
+func (BlockRange) TableName() string {
+	return "block_coverage"
+}
+
+// CoverageGap is a hole between two covered ranges of a chain, events of its blocks may be missing.
+// DetectedAt is when the range after it started being covered
+type CoverageGap struct {
+	ChainID    string    `json:"chain_id"`
+	FromBlock  uint64    `json:"from_block"`
+	ToBlock    uint64    `json:"to_block"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// ChainCoverage is the coverage of a chain, blocks before the first range were never ingested
+type ChainCoverage struct {
+	ChainID string        `json:"chain_id"`
+	Ranges  []BlockRange  `json:"ranges"`
+	Gaps    []CoverageGap `json:"gaps"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// EventSocketBridge is the name of the event with typed columns, events saved before
+// `event_name` existed and messages published without one are SocketBridge events
+const EventSocketBridge = "SocketBridge"
+
+// EventPayload holds every argument of an event by its ABI name, stored as `jsonb`.
+//
+// Integers wider than 32 bits are decimal strings so they survive JSON intact,
+// addresses are checksummed and fixed size byte arrays are hex encoded
+type EventPayload map[string]interface{}
+
+// Value implements driver.Valuer
+func (p EventPayload) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(map[string]interface{}(p))
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// Scan implements sql.Scanner
+func (p *EventPayload) Scan(value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		return p.decode(value)
+	case string:
+		return p.decode([]byte(value))
+	default:
+		return fmt.Errorf("cannot scan %T into EventPayload", value)
+	}
+}
+
+// UnmarshalJSON accepts the payload as an object or as a string holding one,
+// which is how it's carried in stream entries as their values are strings
+func (p *EventPayload) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		unquoted, err := strconv.Unquote(string(data))
+		if err != nil {
+			return err
+		}
+		data = []byte(unquoted)
+	}
+	if string(data) == "null" {
+		*p = nil
+		return nil
+	}
+
+	return p.decode(data)
+}
+
+// Field returns the top level field as text, the way postgres' `->>` operator does
+func (p EventPayload) Field(name string) (string, bool) {
+	value, ok := p[name]
+	if !ok || value == nil {
+		return "", false
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
+
+// decode keeps numbers as json.Number, so nothing is rounded through a float64
+func (p *EventPayload) decode(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var payload map[string]interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return err
+	}
+
+	*p = payload
+	return nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RPCEndpointStatus is the health of an Ethereum RPC endpoint as last checked
+//
+// Endpoint is only the scheme and host, providers put API keys in the rest of the URL
+type RPCEndpointStatus struct {
+	Endpoint   string     `json:"endpoint"`
+	Connected  bool       `json:"connected"`
+	Healthy    bool       `json:"healthy"`
+	Subscribed bool       `json:"subscribed"`
+	Head       uint64     `json:"head"`
+	HeadLag    uint64     `json:"head_lag"`
+	HeadAt     *time.Time `json:"head_at,omitempty"`
+	LatencyMs  int64      `json:"latency_ms"`
+	ErrorRate  float64    `json:"error_rate"`
+	LastError  string     `json:"last_error,omitempty"`
+	CheckedAt  *time.Time `json:"checked_at,omitempty"`
+}
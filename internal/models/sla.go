@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// RouteSLA overrides the SLA of a bridge for transfers to a single destination chain
+type RouteSLA struct {
+	BridgeName  string `json:"bridge_name"`
+	DestChainID string `json:"dest_chain_id"`
+	SLAMinutes  int    `json:"sla_minutes"`
+}
+
+// SLARule is the time transfers of a bridge to any of the chains are expected to complete in
+type SLARule struct {
+	BridgeName   string
+	DestChainIDs []string
+	SLA          time.Duration
+}
+
+// SLABreach is a transfer which wasn't completed within the SLA of its route,
+// it's resolved once the transfer completes
+type SLABreach struct {
+	ID              int        `gorm:"primaryKey" json:"id"`
+	SourceEventID   int        `json:"source_event_id"`
+	TransactionHash string     `json:"transaction_hash"`
+	BridgeName      string     `gorm:"size:66" json:"bridge_name"`
+	DestChainID     string     `gorm:"size:78" json:"dest_chain_id"`
+	Token           string     `gorm:"size:100" json:"token"`
+	SLASeconds      int64      `json:"sla_seconds"`
+	SourceTimestamp time.Time  `json:"source_timestamp"`
+	DetectedAt      time.Time  `json:"detected_at"`
+	ResolvedAt      *time.Time `json:"resolved_at"`
+	// LatencyMs is the completion latency of the transfer once it's resolved
+	LatencyMs *int64 `json:"latency_ms"`
+
+	// Only filled in for API responses, how long the transfer is past its SLA
+	OverdueSeconds float64 `gorm:"-" json:"overdue_seconds,omitempty"`
+}
+
+// TableName implements schema.Tabler, see BridgeEvent.TableName
+func (SLABreach) TableName() string {
+	return "sla_breaches"
+}
+
+// CompletionMetrics are percentiles of the completion latency of a bridge's transfers
+type CompletionMetrics struct {
+	BridgeName string  `json:"bridge_name"`
+	Completed  int64   `json:"completed"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// QuoteUSD is the only currency amounts can be quoted in besides the token itself
+const QuoteUSD = "USD"
+
+// TokenPrice is the USD price of one whole token, e.g. 1 ETH rather than 1 WEI,
+// from Timestamp onwards until the next price of the same symbol
+type TokenPrice struct {
+	Symbol    string    `gorm:"primaryKey" json:"symbol"`
+	Timestamp time.Time `gorm:"primaryKey" json:"timestamp"`
+	USDPrice  string    `gorm:"column:usd_price" json:"usd_price"`
+}
+
+// TableName implements schema.Tabler
+func (TokenPrice) TableName() string {
+	return "token_prices"
+}
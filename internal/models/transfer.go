@@ -0,0 +1,123 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Statuses of a transfer, pending until its fill is seen on the destination chain
+// and stuck once it isn't within the bridge's window. Transfers of bridges or to chains
+// fills aren't ingested for are untracked
+const (
+	TransferPending   = "pending"
+	TransferCompleted = "completed"
+	TransferStuck     = "stuck"
+	TransferUntracked = "untracked"
+)
+
+// BridgeFill is the destination leg of a transfer, the event a bridge emits once funds
+// are released on the destination chain. SourceEventID links it to the SocketBridge event
+// it completes, once matched
+type BridgeFill struct {
+	ID              int        `gorm:"primaryKey" json:"id"`
+	ChainID         string     `gorm:"size:78" json:"chain_id"`
+	BridgeName      string     `gorm:"size:66" json:"bridge_name"`
+	EventName       string     `gorm:"size:100" json:"event_name"`
+	TransactionHash string     `json:"transaction_hash"`
+	LogIndex        uint       `json:"log_index"`
+	BlockNumber     uint64     `json:"block_number"`
+	BlockTimestamp  *time.Time `json:"block_timestamp"`
+	Receiver        string     `gorm:"size:100" json:"receiver"`
+	// Amount released to the receiver in WEI, nil if the bridge's event has none
+	Amount *string `json:"amount"`
+	// DepositID is the bridge's own id of the transfer, empty if the bridge has none
+	DepositID string       `gorm:"size:255" json:"deposit_id,omitempty"`
+	Payload   EventPayload `gorm:"type:jsonb" json:"payload,omitempty"`
+
+	SourceEventID *int       `json:"source_event_id"`
+	LatencyMs     *int64     `json:"latency_ms"`
+	MatchedAt     *time.Time `json:"matched_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName implements schema.Tabler, see BridgeEvent.TableName
+func (BridgeFill) TableName() string {
+	return "bridge_fills"
+}
+
+// Time is when funds were released, the time of its block or when it was saved if that's unknown
+func (f BridgeFill) Time() time.Time {
+	if f.BlockTimestamp != nil {
+		return *f.BlockTimestamp
+	}
+	return f.CreatedAt
+}
+
+// Transfer is a SocketBridge event along with its fill on the destination chain, if there is one
+type Transfer struct {
+	Source BridgeEvent `json:"source"`
+	Fill   *BridgeFill `json:"fill,omitempty"`
+	Status string      `json:"status"`
+	// CompletionLatencySeconds is the time from the source block to the fill's block
+	CompletionLatencySeconds *float64 `json:"completion_latency_seconds,omitempty"`
+}
+
+// TransferFilter narrows down transfers, zero values are ignored
+type TransferFilter struct {
+	BridgeName  string
+	DestChainID string
+	// Status is one of TransferPending, TransferCompleted or TransferStuck
+	Status string
+	// Tracked are the bridges transfers are listed for along with the destination chains fills
+	// are ingested from, transfers of other bridges or to other chains never complete
+	Tracked []FillSpec
+	// Now is the time statuses are evaluated at
+	Now time.Time
+}
+
+// FillSpec describes how a bridge completes transfers on the destination chains,
+// fields are names of the event's payload fields, see EventPayload.Field
+type FillSpec struct {
+	BridgeName string `json:"bridge_name"`
+	// Event is the name of the event emitted on the destination chain, it has to be
+	// unique across bridges as that's how fills are told apart
+	Event    string `json:"event"`
+	Receiver string `json:"receiver"`
+	Amount   string `json:"amount"`
+	// DepositID and SourceDepositID match the fill to the SocketBridge event by the bridge's
+	// own id, held in these fields of the fill and of the SocketBridge payload respectively
+	DepositID       string `json:"deposit_id"`
+	SourceDepositID string `json:"source_deposit_id"`
+	// MaxFeeBps is the most the bridge keeps of the amount, in basis points
+	MaxFeeBps uint `json:"max_fee_bps"`
+	// WindowMinutes is how long after the source event the fill is looked for,
+	// transfers not completed by then are stuck
+	WindowMinutes int `json:"window_minutes"`
+	// SLAMinutes is how long transfers are expected to take, ones taking longer are recorded
+	// as SLA breaches. Defaults to WindowMinutes, routes may override it, see RouteSLA
+	SLAMinutes int `json:"sla_minutes"`
+	// ChainIDs are the destination chains fills are ingested from, filled in from the chains
+	ChainIDs []string `json:"-"`
+}
+
+// Window is WindowMinutes as a duration
+func (s FillSpec) Window() time.Duration {
+	return time.Duration(s.WindowMinutes) * time.Minute
+}
+
+// TransferStatus returns the status of the transfer of the source event at now
+func (s FillSpec) TransferStatus(source BridgeEvent, fill *BridgeFill, now time.Time) string {
+	switch {
+	case fill != nil:
+		return TransferCompleted
+	case now.Sub(source.Timestamp) > s.Window():
+		return TransferStuck
+	default:
+		return TransferPending
+	}
+}
+
+// Tracks reports whether fills of the bridge are ingested from the chain
+func (s FillSpec) Tracks(bridgeName, chainID string) bool {
+	return s.BridgeName == bridgeName && slices.Contains(s.ChainIDs, chainID)
+}
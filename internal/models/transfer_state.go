@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TransferState is a stage of the lifecycle of a transfer, see TransferRecord
+type TransferState string
+
+// States of a transfer. It's observed once its SocketBridge event is saved, confirmed once
+// enough blocks are built on top of its block and finalized along with its block. It's reorged
+// when its transaction drops out of the chain, and observed again once it's included again.
+// Completed and refunded are final
+const (
+	StateObserved  TransferState = "observed"
+	StateConfirmed TransferState = "confirmed"
+	StateReorged   TransferState = "reorged"
+	StateFinalized TransferState = "finalized"
+	StateCompleted TransferState = "completed"
+	StateRefunded  TransferState = "refunded"
+)
+
+// ErrInvalidTransition is returned when a transfer is moved to a state it can't reach from its current one
+var ErrInvalidTransition = errors.New("invalid transfer state transition")
+
+// transferTransitions are the states a transfer may move to from each state
+var transferTransitions = map[TransferState][]TransferState{
+	StateObserved:  {StateConfirmed, StateReorged},
+	StateConfirmed: {StateFinalized, StateCompleted, StateRefunded, StateReorged},
+	StateFinalized: {StateCompleted, StateRefunded},
+	StateReorged:   {StateObserved},
+}
+
+// CanTransition reports whether a transfer in the state may move to `to`
+func (s TransferState) CanTransition(to TransferState) bool {
+	return slices.Contains(transferTransitions[s], to)
+}
+
+// TransferRecord is the lifecycle of a transfer, kept apart from its SocketBridge event
+// as the event is saved once while the transfer moves through its states
+type TransferRecord struct {
+	ID              int    `gorm:"primaryKey" json:"id"`
+	SourceEventID   int    `json:"source_event_id"`
+	TransactionHash string `json:"transaction_hash"`
+	LogIndex        uint   `json:"log_index"`
+	BridgeName      string `gorm:"size:66" json:"bridge_name"`
+	DestChainID     string `gorm:"size:78" json:"dest_chain_id"`
+	// BlockNumber is the block the source transaction is included in, it changes when it's reorged
+	BlockNumber uint64        `json:"block_number"`
+	State       TransferState `gorm:"size:20" json:"state"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// TableName implements schema.Tabler, see BridgeEvent.TableName
+func (TransferRecord) TableName() string {
+	return "transfers"
+}
+
+// Transition validates moving the transfer to the state, returning the transition to record
+func (t TransferRecord) Transition(to TransferState, reason string, at time.Time) (TransferTransition, error) {
+	if !t.State.CanTransition(to) {
+		return TransferTransition{}, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, t.State, to)
+	}
+
+	return TransferTransition{
+		TransferID: t.ID,
+		FromState:  t.State,
+		ToState:    to,
+		Reason:     reason,
+		CreatedAt:  at,
+	}, nil
+}
+
+// TransferTransition is an entry of the audit history of a transfer,
+// FromState is empty for the transition a transfer is observed with
+type TransferTransition struct {
+	ID         int           `gorm:"primaryKey" json:"id"`
+	TransferID int           `json:"transfer_id"`
+	FromState  TransferState `gorm:"size:20" json:"from_state"`
+	ToState    TransferState `gorm:"size:20" json:"to_state"`
+	Reason     string        `json:"reason"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// TableName implements schema.Tabler, see BridgeEvent.TableName
+func (TransferTransition) TableName() string {
+	return "transfer_transitions"
+}
+
+// TransferHistory is a transfer along with every transition it went through, oldest first
+type TransferHistory struct {
+	TransferRecord
+	Transitions []TransferTransition `json:"transitions"`
+}
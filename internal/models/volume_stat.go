@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// VolumeStat is a single aggregated bucket of bridged volume
+// grouped by token, destination chain and bridge name
+type VolumeStat struct {
+	Bucket      time.Time `json:"bucket"`
+	Token       string    `json:"token"`
+	DestChainID string    `json:"dest_chain_id"`
+	BridgeName  string    `json:"bridge_name"`
+	EventCount  int64     `json:"event_count"`
+	TotalAmount string    `json:"total_amount"`
+	TxnCurrency string    `json:"txn_currency"`
+
+	// Only filled in when quoted in USD, UnpricedCount events without a price are left out of TotalUSD
+	TotalUSD      *string `json:"total_usd,omitempty"`
+	UnpricedCount *int64  `json:"unpriced_count,omitempty"`
+}
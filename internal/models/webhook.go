@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Webhook is a subscription of a downstream endpoint to bridge events
+//
+// Empty filter fields match every event
+type Webhook struct {
+	ID                  int        `gorm:"primaryKey" json:"id"`
+	URL                 string     `json:"url"`
+	Secret              string     `json:"secret,omitempty"`
+	Token               string     `gorm:"size:100" json:"token"`
+	DestChainID         string     `gorm:"size:78" json:"dest_chain_id"`
+	BridgeName          string     `gorm:"size:66" json:"bridge_name"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// TableName implements schema.Tabler, see BridgeEvent.TableName
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Filter returns the event filter the webhook is subscribed with,
+// webhooks only receive SocketBridge events
+func (w Webhook) Filter() EventFilter {
+	return EventFilter{
+		Token:       w.Token,
+		DestChainID: w.DestChainID,
+		BridgeName:  w.BridgeName,
+		EventName:   EventSocketBridge,
+	}
+}
+
+// WebhookDelivery records a single attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	ID         int       `gorm:"primaryKey" json:"id"`
+	WebhookID  int       `json:"webhook_id"`
+	EventID    int       `json:"event_id"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName implements schema.Tabler
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
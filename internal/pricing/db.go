@@ -0,0 +1,36 @@
+package pricing
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// DBPriceProvider reads prices from the `token_prices` table,
+// which is filled by the `prices import` command or any external job
+type DBPriceProvider struct {
+	repo   repositories.PriceRepository
+	maxAge time.Duration
+}
+
+func NewDBPriceProvider(repo repositories.PriceRepository, maxAge time.Duration) *DBPriceProvider {
+	return &DBPriceProvider{repo: repo, maxAge: maxAge}
+}
+
+func (p *DBPriceProvider) PriceAt(symbol string, at time.Time) (string, error) {
+	var from time.Time
+	if p.maxAge > 0 {
+		from = at.Add(-p.maxAge)
+	}
+
+	price, ok, err := p.repo.LatestPrice(strings.ToUpper(symbol), from, at)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrNoPrice
+	}
+
+	return price.USDPrice, nil
+}
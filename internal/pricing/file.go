@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/pkg/decimal"
+)
+
+// FilePriceProvider serves prices loaded from a CSV file once at startup,
+// see ReadPrices for the format
+type FilePriceProvider struct {
+	// prices of every symbol, oldest first
+	prices map[string][]models.TokenPrice
+	maxAge time.Duration
+}
+
+func NewFilePriceProvider(path string, maxAge time.Duration) (*FilePriceProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	prices, err := ReadPrices(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prices from %s: %w", path, err)
+	}
+
+	return NewStaticPriceProvider(prices, maxAge), nil
+}
+
+// NewStaticPriceProvider serves the given prices, they don't need to be sorted
+func NewStaticPriceProvider(prices []models.TokenPrice, maxAge time.Duration) *FilePriceProvider {
+	provider := &FilePriceProvider{
+		prices: make(map[string][]models.TokenPrice),
+		maxAge: maxAge,
+	}
+
+	for _, price := range prices {
+		provider.prices[price.Symbol] = append(provider.prices[price.Symbol], price)
+	}
+	for _, history := range provider.prices {
+		sort.Slice(history, func(i, j int) bool {
+			return history[i].Timestamp.Before(history[j].Timestamp)
+		})
+	}
+
+	return provider
+}
+
+func (p *FilePriceProvider) PriceAt(symbol string, at time.Time) (string, error) {
+	history := p.prices[strings.ToUpper(symbol)]
+
+	// Index of the first price after `at`, the one before it is the price at `at`
+	i := sort.Search(len(history), func(i int) bool {
+		return history[i].Timestamp.After(at)
+	})
+	if i == 0 || !withinMaxAge(history[i-1].Timestamp, at, p.maxAge) {
+		return "", ErrNoPrice
+	}
+
+	return history[i-1].USDPrice, nil
+}
+
+// ReadPrices parses a CSV of prices with a header row
+//
+//	symbol,timestamp,usd_price
+//	ETH,2024-12-14T00:00:00Z,3905.12
+//
+// Timestamps are RFC3339, symbols are converted to uppercase
+func ReadPrices(r io.Reader) ([]models.TokenPrice, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("missing header row")
+		}
+		return nil, err
+	}
+	if strings.Join(header, ",") != "symbol,timestamp,usd_price" {
+		return nil, fmt.Errorf("unexpected header %q, must be symbol,timestamp,usd_price", strings.Join(header, ","))
+	}
+
+	var prices []models.TokenPrice
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return prices, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		timestamp, err := time.Parse(time.RFC3339, row[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp %q", line, row[1])
+		}
+		if _, err := decimal.Parse(row[2], PriceDecimals); err != nil {
+			return nil, fmt.Errorf("line %d: invalid price %q", line, row[2])
+		}
+
+		prices = append(prices, models.TokenPrice{
+			Symbol:    strings.ToUpper(row[0]),
+			Timestamp: timestamp.UTC(),
+			USDPrice:  row[2],
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package pricing values bridged amounts in USD using historical token prices
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/eth-bridging/pkg/decimal"
+)
+
+const (
+	// Supported price sources, see config.PriceSource
+	SourceDB   = "db"
+	SourceFile = "file"
+	SourceNone = "none"
+
+	// PriceDecimals is the precision prices are read with, more precise prices are rejected
+	PriceDecimals = 18
+	// USDDecimals is the precision USD values are rounded to
+	USDDecimals = 6
+)
+
+// ErrNoPrice is returned when there is no price of a token recent enough to value an amount
+var ErrNoPrice = errors.New("no price available")
+
+// PriceProvider looks up historical USD prices of whole tokens
+type PriceProvider interface {
+	// PriceAt returns the USD price of the symbol at the time, as an exact decimal string.
+	// It's the latest known price at or before `at`, ErrNoPrice is returned if there is
+	// none or it's older than the provider's maximum age
+	PriceAt(symbol string, at time.Time) (string, error)
+}
+
+// NewPriceProvider returns the provider configured by `PRICE_SOURCE`,
+// it's nil when valuation is turned off
+func NewPriceProvider(cfg *config.Config, repo repositories.PriceRepository) (PriceProvider, error) {
+	maxAge := time.Duration(cfg.PriceMaxAgeHours) * time.Hour
+
+	switch cfg.PriceSource {
+	case SourceDB:
+		return NewDBPriceProvider(repo, maxAge), nil
+	case SourceFile:
+		return NewFilePriceProvider(cfg.PriceFile, maxAge)
+	case SourceNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown price source %q, must be %s, %s or %s", cfg.PriceSource, SourceDB, SourceFile, SourceNone)
+	}
+}
+
+// USDValue values a raw amount of a token with `decimals` decimals at a USD price of one whole token.
+// The result is exact up to USDDecimals, rounded half away from zero, with trailing zeros trimmed
+func USDValue(raw string, decimals uint, price string) (string, error) {
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return "", decimal.ErrInvalidAmount
+	}
+
+	scaledPrice, err := decimal.Parse(price, PriceDecimals)
+	if err != nil {
+		return "", fmt.Errorf("invalid price %q: %w", price, err)
+	}
+
+	// amount / 10^decimals * scaledPrice / 10^PriceDecimals, kept with USDDecimals decimals
+	numerator := new(big.Int).Mul(amount, scaledPrice)
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals+PriceDecimals-USDDecimals)), nil)
+
+	value, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Lsh(remainder.Abs(remainder), 1).Cmp(denominator) >= 0 {
+		value.Add(value, big.NewInt(int64(numerator.Sign())))
+	}
+
+	return decimal.Trim(decimal.Format(value, USDDecimals)), nil
+}
+
+// withinMaxAge reports whether a price from `priced` can still be used at `at`,
+// zero maxAge accepts prices of any age
+func withinMaxAge(priced, at time.Time, maxAge time.Duration) bool {
+	return maxAge == 0 || at.Sub(priced) <= maxAge
+}
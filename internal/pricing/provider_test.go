@@ -0,0 +1,90 @@
+package pricing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUSDValue(t *testing.T) {
+	cases := []struct {
+		raw      string
+		decimals uint
+		price    string
+		want     string
+	}{
+		// 1.5 ETH at 3905.12
+		{raw: "1500000000000000000", decimals: 18, price: "3905.12", want: "5857.68"},
+		// 1372.483935 USDC at 0.9998
+		{raw: "1372483935", decimals: 6, price: "0.9998", want: "1372.209438"},
+		// 1 WEI is worth less than the precision, rounds down to 0
+		{raw: "1", decimals: 18, price: "3905.12", want: "0"},
+		// 0.0000005 USD rounds half away from zero
+		{raw: "5", decimals: 7, price: "1", want: "0.000001"},
+		// Larger than a float64 holds exactly
+		{raw: "123456789012345678901234567890", decimals: 18, price: "2", want: "246913578024.691358"},
+	}
+
+	for _, c := range cases {
+		got, err := USDValue(c.raw, c.decimals, c.price)
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, got, "%s at %s", c.raw, c.price)
+	}
+
+	_, err := USDValue("1.5", 18, "1")
+	assert.Error(t, err)
+	_, err = USDValue("1", 18, "not a price")
+	assert.Error(t, err)
+}
+
+func TestStaticPriceProvider_PriceAt(t *testing.T) {
+	day := time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC)
+	provider := NewStaticPriceProvider([]models.TokenPrice{
+		{Symbol: "ETH", Timestamp: day.Add(time.Hour), USDPrice: "3910"},
+		{Symbol: "ETH", Timestamp: day, USDPrice: "3900"},
+	}, 2*time.Hour)
+
+	price, err := provider.PriceAt("eth", day.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "3910", price)
+
+	price, err = provider.PriceAt("ETH", day)
+	assert.NoError(t, err)
+	assert.Equal(t, "3900", price)
+
+	// Before the first price, after the last one got too old and an unknown symbol
+	for _, lookup := range []struct {
+		symbol string
+		at     time.Time
+	}{
+		{"ETH", day.Add(-time.Second)},
+		{"ETH", day.Add(3*time.Hour + time.Second)},
+		{"DAI", day},
+	} {
+		_, err := provider.PriceAt(lookup.symbol, lookup.at)
+		assert.ErrorIs(t, err, ErrNoPrice)
+	}
+}
+
+func TestReadPrices(t *testing.T) {
+	prices, err := ReadPrices(strings.NewReader("symbol,timestamp,usd_price\neth,2024-12-14T00:00:00Z,3905.12\nUSDC,2024-12-14T02:00:00+02:00,1\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TokenPrice{
+		{Symbol: "ETH", Timestamp: time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC), USDPrice: "3905.12"},
+		{Symbol: "USDC", Timestamp: time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC), USDPrice: "1"},
+	}, prices)
+
+	for _, invalid := range []string{
+		"",
+		"token,time,price\n",
+		"symbol,timestamp,usd_price\nETH,yesterday,3905.12\n",
+		"symbol,timestamp,usd_price\nETH,2024-12-14T00:00:00Z,$3905\n",
+	} {
+		_, err := ReadPrices(strings.NewReader(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
+	"github.com/eth-bridging/internal/envelope"
 	"github.com/eth-bridging/internal/models"
 	rediscli "github.com/eth-bridging/pkg/redisclient"
 	"github.com/go-redis/redis/v8"
@@ -23,16 +25,21 @@ type Producer interface {
 type RedisProducer struct {
 	client rediscli.RedisClient
 	stream string
-	done   chan bool
-	wg     *sync.WaitGroup
+	// producerID is written in the envelope of every event, whose payload is encoded with codec
+	producerID string
+	codec      envelope.Codec
+	done       chan bool
+	wg         *sync.WaitGroup
 }
 
-func NewRedisProducer(client rediscli.RedisClient, stream string, wg *sync.WaitGroup) *RedisProducer {
+func NewRedisProducer(client rediscli.RedisClient, stream, producerID string, codec envelope.Codec, wg *sync.WaitGroup) *RedisProducer {
 	return &RedisProducer{
-		client: client,
-		stream: stream,
-		done:   make(chan bool),
-		wg:     wg,
+		client:     client,
+		stream:     stream,
+		producerID: producerID,
+		codec:      codec,
+		done:       make(chan bool),
+		wg:         wg,
 	}
 }
 
@@ -55,18 +62,15 @@ func (p *RedisProducer) PublishEvent(event models.BridgeEvent) error {
 
 	ctx := context.Background()
 
-	// Convert the event to a flat key-value map as that is what will be sent using redis
-	eventMap := map[string]interface{}{
-		"transactionHash": event.TransactionHash,
-		"token":           event.Token,
-		"amount":          event.Amount,
-		"fromChain":       event.FromChain,
-		"toChain":         event.ToChain,
-		"timestamp":       event.Timestamp,
+	// Wrap the event in a versioned envelope, events consumers would reject aren't published
+	eventMap, err := envelope.Encode(p.codec, event, p.producerID, time.Now())
+	if err != nil {
+		log.Printf("Error encoding event %s:%d: %v", event.TransactionHash, event.LogIndex, err)
+		return err
 	}
 
 	// Add the event to the Redis stream
-	_, err := p.client.XAdd(ctx, &redis.XAddArgs{
+	_, err = p.client.XAdd(ctx, &redis.XAddArgs{
 		Stream: p.stream,
 		Values: eventMap,
 		ID:     "*",
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/eth-bridging/internal/envelope"
 	"github.com/eth-bridging/internal/models"
 	"github.com/eth-bridging/internal/producer"
 	redisCli "github.com/eth-bridging/pkg/redisclient"
@@ -20,7 +21,7 @@ func TestPublishEvent(t *testing.T) {
 
 	mockClient := new(redisCli.MockRedisClient)
 
-	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", nil)
+	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", "test-producer", envelope.JSON, nil)
 
 	event := models.BridgeEvent{
 		TransactionHash: "0x1234",
@@ -44,7 +45,7 @@ func TestPublishEvent_ErrorOnXAdd(t *testing.T) {
 
 	mockClient := new(redisCli.MockRedisClient)
 
-	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", nil)
+	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", "test-producer", envelope.JSON, nil)
 
 	event := models.BridgeEvent{
 		TransactionHash: "0x1234",
@@ -79,7 +80,7 @@ func TestPublishEvent_StopSignal(t *testing.T) {
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 
-	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", wg)
+	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", "test-producer", envelope.JSON, wg)
 
 	event := models.BridgeEvent{
 		TransactionHash: "0x1234",
@@ -116,7 +117,7 @@ func TestPublishEvent_Success(t *testing.T) {
 
 	mockClient := new(redisCli.MockRedisClient)
 
-	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", nil)
+	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", "test-producer", envelope.JSON, nil)
 
 	event := models.BridgeEvent{
 		TransactionHash: "0x1234",
@@ -140,7 +141,7 @@ func TestPublishEvent_WithEmptyTransactionHash(t *testing.T) {
 
 	mockClient := new(redisCli.MockRedisClient)
 
-	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", nil)
+	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", "test-producer", envelope.JSON, nil)
 
 	event := models.BridgeEvent{
 		TransactionHash: "",
@@ -151,20 +152,19 @@ func TestPublishEvent_WithEmptyTransactionHash(t *testing.T) {
 		Timestamp:       time.Now(),
 	}
 
-	mockClient.On("XAdd", mock.Anything, mock.Anything).Return(&redis.StringCmd{})
-
 	err := mockProducer.PublishEvent(event)
 
-	assert.NoError(t, err)
+	// Consumers would move it to the DLQ, it's not published
+	assert.ErrorIs(t, err, envelope.ErrInvalidEvent)
 
-	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "XAdd", mock.Anything, mock.Anything)
 }
 
 func TestPublishEvent_WithInvalidData(t *testing.T) {
 
 	mockClient := new(redisCli.MockRedisClient)
 
-	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", nil)
+	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", "test-producer", envelope.JSON, nil)
 
 	event := models.BridgeEvent{
 		TransactionHash: "0x1234",
@@ -175,11 +175,45 @@ func TestPublishEvent_WithInvalidData(t *testing.T) {
 		Timestamp:       time.Now(),
 	}
 
-	mockClient.On("XAdd", mock.Anything, mock.Anything).Return(&redis.StringCmd{})
-
 	err := mockProducer.PublishEvent(event)
 
+	assert.ErrorIs(t, err, envelope.ErrInvalidEvent)
+
+	mockClient.AssertNotCalled(t, "XAdd", mock.Anything, mock.Anything)
+}
+
+func TestPublishEvent_Payload(t *testing.T) {
+	mockClient := new(redisCli.MockRedisClient)
+	mockProducer := producer.NewRedisProducer(mockClient, "test-stream", "test-producer", envelope.JSON, nil)
+
+	event := models.BridgeEvent{
+		TransactionHash: "0x1234",
+		Timestamp:       time.Now(),
+		EventName:       "NewRouteAdded",
+		Payload:         models.EventPayload{"routeId": "404", "route": "0x3a23F943181408EAC424116Af7b7790c94Cb97a5"},
+	}
+
+	var values map[string]interface{}
+	mockClient.On("XAdd", mock.Anything, mock.MatchedBy(func(args *redis.XAddArgs) bool {
+		values = args.Values.(map[string]interface{})
+		return true
+	})).Return(&redis.StringCmd{})
+
+	assert.NoError(t, mockProducer.PublishEvent(event))
+	assert.Equal(t, "NewRouteAdded", values[envelope.FieldEventType])
+	assert.Equal(t, "test-producer", values[envelope.FieldProducerID])
+
+	// Stream values come back as strings, which is what the producer writes
+	for _, value := range values {
+		assert.IsType(t, "", value)
+	}
+
+	env, err := envelope.Decode(values)
 	assert.NoError(t, err)
+	assert.Equal(t, envelope.SchemaVersion, env.SchemaVersion)
 
-	mockClient.AssertExpectations(t)
+	consumed := env.Event()
+	assert.Equal(t, "NewRouteAdded", consumed.EventName)
+	assert.Equal(t, event.Payload, consumed.Payload)
+	assert.True(t, event.Timestamp.Equal(consumed.Timestamp))
 }
@@ -0,0 +1,209 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/producer"
+	"github.com/eth-bridging/internal/repositories"
+	ethereum "github.com/eth-bridging/pkg/go-eth"
+)
+
+// EventSource reads events of a block range from the chain along with the logs which couldn't
+// be decoded, see ethereum.EthereumClient.FetchEvents
+type EventSource interface {
+	FetchEvents(ctx context.Context, from, to uint64) ([]*models.BridgeEvent, []ethereum.DecodeFailure, error)
+}
+
+// Options tunes the Reconciler
+type Options struct {
+	// RangeSize is the number of blocks read from the chain at once, providers cap
+	// the blocks or logs of a single request
+	RangeSize uint64
+	// Repair republishes missing events through the producer, so the consumer saves them
+	Repair bool
+}
+
+// DefaultOptions are sensible defaults for production use
+var DefaultOptions = Options{
+	RangeSize: 2000,
+}
+
+// Mismatch is an event stored with fields differing from the chain
+type Mismatch struct {
+	Stored models.BridgeEvent
+	Chain  models.BridgeEvent
+	// Fields are the names of the differing fields
+	Fields []string
+}
+
+// Report is the outcome of reconciling a block range. Missing events are on the chain but not stored,
+// extra ones are stored but not on the chain, e.g. as they were reorged out. Undecodable logs
+// are on the chain but couldn't be checked, nor repaired
+type Report struct {
+	From        uint64
+	To          uint64
+	Matched     int
+	Missing     []models.BridgeEvent
+	Extra       []models.BridgeEvent
+	Mismatched  []Mismatch
+	Undecodable []ethereum.DecodeFailure
+	// Repaired is the number of missing events republished
+	Repaired int
+}
+
+// Clean reports whether stored events are exactly the events on the chain
+func (r Report) Clean() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Mismatched) == 0 && len(r.Undecodable) == 0
+}
+
+// eventKey identifies an event on the chain
+type eventKey struct {
+	txHash   string
+	logIndex uint
+}
+
+// Reconciler checks stored events against the chain, telling events apart by (transaction hash, log index)
+type Reconciler struct {
+	source   EventSource
+	repo     repositories.BridgeEventRepository
+	producer producer.Producer
+	opts     Options
+}
+
+// NewReconciler creates the reconciler, streamProducer is only used to repair and may be nil otherwise
+func NewReconciler(source EventSource, repo repositories.BridgeEventRepository, streamProducer producer.Producer, opts Options) *Reconciler {
+	return &Reconciler{
+		source:   source,
+		repo:     repo,
+		producer: streamProducer,
+		opts:     opts,
+	}
+}
+
+// Reconcile compares the events of the blocks [from, to] in ranges of RangeSize blocks,
+// republishing missing ones when repairing
+func (r *Reconciler) Reconcile(ctx context.Context, from, to uint64) (Report, error) {
+	report := Report{From: from, To: to}
+	if r.opts.Repair && r.producer == nil {
+		return report, errors.New("repairing needs a producer")
+	}
+
+	for start := from; start <= to && ctx.Err() == nil; start += r.opts.RangeSize {
+		end := min(to, start+r.opts.RangeSize-1)
+		if err := r.reconcileRange(ctx, start, end, &report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, ctx.Err()
+}
+
+// reconcileRange adds the differences of the blocks [from, to] to the report
+func (r *Reconciler) reconcileRange(ctx context.Context, from, to uint64, report *Report) error {
+	onChain, undecodable, err := r.source.FetchEvents(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to fetch events of blocks %d to %d: %w", from, to, err)
+	}
+	stored, err := r.repo.ListByBlockRange(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to read events of blocks %d to %d: %w", from, to, err)
+	}
+
+	storedByKey := make(map[eventKey]models.BridgeEvent, len(stored))
+	for _, event := range stored {
+		storedByKey[eventKey{event.TransactionHash, event.LogIndex}] = event
+	}
+
+	// Stored events of undecodable logs can't be checked, they aren't extra though
+	report.Undecodable = append(report.Undecodable, undecodable...)
+	for _, failure := range undecodable {
+		delete(storedByKey, eventKey{failure.TransactionHash, failure.LogIndex})
+	}
+
+	for _, event := range onChain {
+		key := eventKey{event.TransactionHash, event.LogIndex}
+		storedEvent, ok := storedByKey[key]
+		if !ok {
+			report.Missing = append(report.Missing, *event)
+			if r.opts.Repair {
+				r.repair(*event, report)
+			}
+			continue
+		}
+		delete(storedByKey, key)
+
+		if fields := diff(storedEvent, *event); len(fields) > 0 {
+			report.Mismatched = append(report.Mismatched, Mismatch{Stored: storedEvent, Chain: *event, Fields: fields})
+		} else {
+			report.Matched++
+		}
+	}
+
+	// Stored events are ordered, keep the extra ones in that order
+	for _, event := range stored {
+		if _, ok := storedByKey[eventKey{event.TransactionHash, event.LogIndex}]; ok {
+			report.Extra = append(report.Extra, event)
+		}
+	}
+
+	return nil
+}
+
+// repair republishes the missing event, the consumer saves it like any other event
+func (r *Reconciler) repair(event models.BridgeEvent, report *Report) {
+	if err := r.producer.PublishEvent(event); err != nil {
+		log.Printf("Error republishing event %s:%d: %v", event.TransactionHash, event.LogIndex, err)
+		return
+	}
+	report.Repaired++
+}
+
+// diff returns the names of the decoded fields differing between the stored event and the chain.
+// Bridge names are compared raw, as stored ones may have been decoded again since, see BridgeNameRaw,
+// and payloads are only compared when both have one, as events saved before payloads existed have none
+func diff(stored, chain models.BridgeEvent) []string {
+	var fields []string
+	for _, field := range []struct {
+		name          string
+		stored, chain string
+	}{
+		{"EventName", eventName(stored), eventName(chain)},
+		{"BlockNumber", fmt.Sprint(stored.BlockNumber), fmt.Sprint(chain.BlockNumber)},
+		{"Token", stored.Token, chain.Token},
+		{"Amount", stored.Amount, chain.Amount},
+		{"FromChain", stored.FromChain, chain.FromChain},
+		{"ToChain", stored.ToChain, chain.ToChain},
+		{"DestChainID", stored.DestChainID, chain.DestChainID},
+		{"BridgeNameRaw", stored.BridgeNameRaw, chain.BridgeNameRaw},
+	} {
+		if field.stored != field.chain {
+			fields = append(fields, field.name)
+		}
+	}
+
+	if stored.Payload != nil && chain.Payload != nil && !samePayload(stored.Payload, chain.Payload) {
+		fields = append(fields, "Payload")
+	}
+
+	return fields
+}
+
+// eventName is the name of the event, events saved before `event_name` existed are SocketBridge events
+func eventName(event models.BridgeEvent) string {
+	if event.EventName == "" {
+		return models.EventSocketBridge
+	}
+	return event.EventName
+}
+
+// samePayload compares the payloads by their JSON, as stored payloads are decoded with different types
+func samePayload(a, b models.EventPayload) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
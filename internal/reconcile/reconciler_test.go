@@ -0,0 +1,153 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	ethereum "github.com/eth-bridging/pkg/go-eth"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSource returns the events and undecodable logs of the requested blocks
+type fakeSource struct {
+	events      []models.BridgeEvent
+	undecodable []ethereum.DecodeFailure
+	ranges      [][2]uint64
+}
+
+func (s *fakeSource) FetchEvents(ctx context.Context, from, to uint64) ([]*models.BridgeEvent, []ethereum.DecodeFailure, error) {
+	s.ranges = append(s.ranges, [2]uint64{from, to})
+
+	var events []*models.BridgeEvent
+	for i := range s.events {
+		if s.events[i].BlockNumber >= from && s.events[i].BlockNumber <= to {
+			events = append(events, &s.events[i])
+		}
+	}
+	var undecodable []ethereum.DecodeFailure
+	for _, failure := range s.undecodable {
+		if failure.BlockNumber >= from && failure.BlockNumber <= to {
+			undecodable = append(undecodable, failure)
+		}
+	}
+	return events, undecodable, nil
+}
+
+// fakeEventRepository returns the stored events of the requested blocks
+type fakeEventRepository struct {
+	repositories.BridgeEventRepository
+
+	events []models.BridgeEvent
+}
+
+func (r *fakeEventRepository) ListByBlockRange(from, to uint64) ([]models.BridgeEvent, error) {
+	var events []models.BridgeEvent
+	for _, event := range r.events {
+		if event.BlockNumber >= from && event.BlockNumber <= to {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// fakeProducer keeps published events
+type fakeProducer struct {
+	published []models.BridgeEvent
+}
+
+func (p *fakeProducer) PublishEvent(event models.BridgeEvent) error {
+	p.published = append(p.published, event)
+	return nil
+}
+
+func (p *fakeProducer) Stop() {}
+
+func socketBridge(txHash string, logIndex uint, block uint64, amount string) models.BridgeEvent {
+	return models.BridgeEvent{
+		EventName:       models.EventSocketBridge,
+		TransactionHash: txHash,
+		LogIndex:        logIndex,
+		BlockNumber:     block,
+		Token:           "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE",
+		Amount:          amount,
+		DestChainID:     "10",
+		BridgeNameRaw:   "0x9ddd",
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	source := &fakeSource{events: []models.BridgeEvent{
+		socketBridge("0xa", 1, 100, "1000"),
+		socketBridge("0xa", 2, 100, "2000"),
+		socketBridge("0xb", 0, 150, "3000"),
+		socketBridge("0xc", 4, 230, "4000"),
+	}}
+
+	mismatched := socketBridge("0xb", 0, 150, "3001")
+	// Renamed since, raw names are compared
+	mismatched.BridgeName = "hop"
+	// Saved before event names existed
+	legacy := socketBridge("0xa", 1, 100, "1000")
+	legacy.EventName = ""
+	repo := &fakeEventRepository{events: []models.BridgeEvent{
+		legacy,
+		mismatched,
+		socketBridge("0xd", 7, 180, "5000"),
+		socketBridge("0xc", 4, 230, "4000"),
+	}}
+	streamProducer := &fakeProducer{}
+
+	reconciler := NewReconciler(source, repo, streamProducer, Options{RangeSize: 100, Repair: true})
+	report, err := reconciler.Reconcile(context.Background(), 100, 250)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]uint64{{100, 199}, {200, 250}}, source.ranges)
+	assert.False(t, report.Clean())
+	assert.Equal(t, 2, report.Matched)
+
+	if assert.Len(t, report.Missing, 1) {
+		assert.Equal(t, uint(2), report.Missing[0].LogIndex)
+	}
+	if assert.Len(t, report.Extra, 1) {
+		assert.Equal(t, "0xd", report.Extra[0].TransactionHash)
+	}
+	if assert.Len(t, report.Mismatched, 1) {
+		assert.Equal(t, []string{"Amount"}, report.Mismatched[0].Fields)
+	}
+
+	assert.Equal(t, 1, report.Repaired)
+	assert.Equal(t, report.Missing, streamProducer.published)
+}
+
+func TestReconciler_Undecodable(t *testing.T) {
+	source := &fakeSource{
+		events: []models.BridgeEvent{socketBridge("0xa", 1, 100, "1000")},
+		undecodable: []ethereum.DecodeFailure{
+			{TransactionHash: "0xe", LogIndex: 3, BlockNumber: 120, Err: errors.New("abi: cannot marshal in to go type")},
+		},
+	}
+	repo := &fakeEventRepository{events: []models.BridgeEvent{
+		socketBridge("0xa", 1, 100, "1000"),
+		// Saved by an earlier version which could decode it
+		socketBridge("0xe", 3, 120, "2000"),
+	}}
+
+	report, err := NewReconciler(source, repo, nil, Options{RangeSize: 100}).Reconcile(context.Background(), 100, 150)
+
+	assert.NoError(t, err)
+	assert.False(t, report.Clean())
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, source.undecodable, report.Undecodable)
+	assert.Empty(t, report.Extra)
+}
+
+func TestReconciler_RepairNeedsProducer(t *testing.T) {
+	reconciler := NewReconciler(&fakeSource{}, &fakeEventRepository{}, nil, Options{RangeSize: 100, Repair: true})
+
+	_, err := reconciler.Reconcile(context.Background(), 100, 200)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AlertRepository interface {
+	CreateRule(rule *models.AlertRule) error
+	ListRules() ([]models.AlertRule, error)
+	// ListActiveRules returns rules which are evaluated against consumed events
+	ListActiveRules() ([]models.AlertRule, error)
+	DeleteRule(id int) error
+	CreateAlert(alert *models.Alert) error
+	// UpdateAlertNotification records the outcome of notifying about the alert
+	UpdateAlertNotification(alert *models.Alert) error
+	// ListAlerts returns alerts newest first, optionally of a single rule, using keyset pagination on id
+	ListAlerts(ruleID int, lastID uint, limit int) ([]models.Alert, error)
+}
+
+type alertRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) AlertRepository {
+	return &alertRepositoryImpl{db: db}
+}
+
+func (r *alertRepositoryImpl) CreateRule(rule *models.AlertRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *alertRepositoryImpl) ListRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+
+	err := r.db.Order("id asc").Find(&rules).Error
+	return rules, err
+}
+
+func (r *alertRepositoryImpl) ListActiveRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+
+	err := r.db.Where("active = ?", true).Order("id asc").Find(&rules).Error
+	return rules, err
+}
+
+func (r *alertRepositoryImpl) DeleteRule(id int) error {
+	result := r.db.Delete(&models.AlertRule{}, id)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return result.Error
+}
+
+func (r *alertRepositoryImpl) CreateAlert(alert *models.Alert) error {
+	return r.db.Create(alert).Error
+}
+
+func (r *alertRepositoryImpl) UpdateAlertNotification(alert *models.Alert) error {
+	return r.db.Model(alert).Select("notified", "error").Updates(alert).Error
+}
+
+func (r *alertRepositoryImpl) ListAlerts(ruleID int, lastID uint, limit int) ([]models.Alert, error) {
+	var alerts []models.Alert
+
+	query := r.db.Order("id desc").Limit(limit)
+	if ruleID != 0 {
+		query = query.Where("rule_id = ?", ruleID)
+	}
+	if lastID != 0 {
+		query = query.Where("id < ?", lastID)
+	}
+
+	err := query.Find(&alerts).Error
+	return alerts, err
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ArchiveRepository interface {
+	// OldestEventTime returns the timestamp of the oldest event still in `bridge_events`,
+	// ok is false if there are no events
+	OldestEventTime() (oldest time.Time, ok bool, err error)
+	// CountEvents returns the number of events in [from, to)
+	CountEvents(from, to time.Time) (int64, error)
+	// CreateRange records events of a range as archived, it's done before they're deleted
+	// so a run interrupted while deleting is finished by the next one
+	CreateRange(archived *models.ArchivedRange) error
+	// DeleteEvents deletes events in [from, to) with the ids, `batchSize` per statement and each committed
+	// on its own, returns the number of deleted events. Ids already deleted are skipped, so it's safe to repeat
+	DeleteEvents(from, to time.Time, ids []int, batchSize int) (int64, error)
+	// ListRanges returns every archived range, oldest first
+	ListRanges() ([]models.ArchivedRange, error)
+	// ArchivedBefore returns the end of the most recent archived range,
+	// zero if nothing has been archived
+	ArchivedBefore() (time.Time, error)
+}
+
+type archiveRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewArchiveRepository(db *gorm.DB) ArchiveRepository {
+	return &archiveRepositoryImpl{db: db}
+}
+
+func (r *archiveRepositoryImpl) OldestEventTime() (time.Time, bool, error) {
+	var oldest sql.NullTime
+
+	err := r.db.Raw("SELECT MIN(timestamp) FROM bridge_events").Scan(&oldest).Error
+
+	return oldest.Time, oldest.Valid, err
+}
+
+func (r *archiveRepositoryImpl) CountEvents(from, to time.Time) (int64, error) {
+	var count int64
+
+	err := r.db.Model(&models.BridgeEvent{}).
+		Where("timestamp >= ? AND timestamp < ?", from, to).
+		Count(&count).Error
+
+	return count, err
+}
+
+func (r *archiveRepositoryImpl) CreateRange(archived *models.ArchivedRange) error {
+	return r.db.Create(archived).Error
+}
+
+func (r *archiveRepositoryImpl) DeleteEvents(from, to time.Time, ids []int, batchSize int) (int64, error) {
+	var deleted int64
+
+	for start := 0; start < len(ids); start += batchSize {
+		batch := ids[start:min(start+batchSize, len(ids))]
+
+		// The range is repeated so only its partitions are scanned
+		result := r.db.Exec("DELETE FROM bridge_events WHERE timestamp >= ? AND timestamp < ? AND id IN ?", from, to, batch)
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += result.RowsAffected
+	}
+
+	return deleted, nil
+}
+
+func (r *archiveRepositoryImpl) ListRanges() ([]models.ArchivedRange, error) {
+	var ranges []models.ArchivedRange
+
+	err := r.db.Order("range_start asc, id asc").Find(&ranges).Error
+
+	return ranges, err
+}
+
+func (r *archiveRepositoryImpl) ArchivedBefore() (time.Time, error) {
+	var before sql.NullTime
+
+	err := r.db.Raw("SELECT MAX(range_end) FROM archived_ranges").Scan(&before).Error
+
+	return before.Time, err
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveRepository_DeleteEvents(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewArchiveRepository(gormDB)
+
+	from := time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	// Every batch is committed on its own, event 9 was deleted by an earlier run
+	mock.ExpectExec(`DELETE FROM bridge_events WHERE timestamp >= \$1 AND timestamp < \$2 AND id IN \(\$3,\$4\)`).
+		WithArgs(from, to, 3, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM bridge_events WHERE timestamp >= \$1 AND timestamp < \$2 AND id IN \(\$3\)`).
+		WithArgs(from, to, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.DeleteEvents(from, to, []int{3, 5, 9}, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveRepository_DeleteEventsFailedBatch(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewArchiveRepository(gormDB)
+
+	from := time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	mock.ExpectExec(`DELETE FROM bridge_events`).
+		WithArgs(from, to, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM bridge_events`).
+		WithArgs(from, to, 5).
+		WillReturnError(errors.New("connection reset"))
+
+	deleted, err := repo.DeleteEvents(from, to, []int{3, 5, 9}, 1)
+
+	// Earlier batches stay deleted
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, int64(1), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveRepository_ArchivedBefore(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewArchiveRepository(gormDB)
+
+	end := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT MAX\(range_end\) FROM archived_ranges`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(end))
+
+	before, err := repo.ArchivedBefore()
+
+	assert.NoError(t, err)
+	assert.Equal(t, end, before)
+
+	// Nothing archived yet
+	mock.ExpectQuery(`SELECT MAX\(range_end\) FROM archived_ranges`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+
+	before, err = repo.ArchivedBefore()
+
+	assert.NoError(t, err)
+	assert.True(t, before.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// unknownBridgeNamePattern matches bridge names left as the hex encoded bytes32, see ethereum.BridgeNameDecoder
+const unknownBridgeNamePattern = "^0x[0-9a-f]{64}$"
+
+type BridgeNameRepository interface {
+	// ListUnknown returns the bridge names that couldn't be decoded, most events first.
+	// It's read from the daily rollups, so events of archived days aren't counted
+	ListUnknown() ([]models.UnknownBridgeName, error)
+	// StoredNames returns every distinct pair of raw and decoded bridge name of saved events
+	StoredNames() ([]models.StoredBridgeName, error)
+	// Rename changes the decoded name of events with the raw name from `from` to `to`,
+	// returns the number of updated events.
+	//
+	// Webhooks and alert rules filtering by an undecoded name are updated along with them
+	Rename(raw, from, to string) (int64, error)
+}
+
+type bridgeNameRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBridgeNameRepository(db *gorm.DB) BridgeNameRepository {
+	return &bridgeNameRepositoryImpl{db: db}
+}
+
+func (r *bridgeNameRepositoryImpl) ListUnknown() ([]models.UnknownBridgeName, error) {
+	var names []models.UnknownBridgeName
+
+	err := r.db.Table("bridge_volume_daily").
+		Select("bridge_name, SUM(event_count) AS event_count, MIN(bucket) AS first_seen, MAX(bucket) AS last_seen").
+		Where("bridge_name ~ ?", unknownBridgeNamePattern).
+		Group("bridge_name").
+		Order("event_count desc, bridge_name").
+		Scan(&names).Error
+
+	return names, err
+}
+
+func (r *bridgeNameRepositoryImpl) StoredNames() ([]models.StoredBridgeName, error) {
+	var names []models.StoredBridgeName
+
+	err := r.db.Model(&models.BridgeEvent{}).
+		Distinct("bridge_name_raw", "bridge_name").
+		Order("bridge_name_raw").
+		Scan(&names).Error
+
+	return names, err
+}
+
+func (r *bridgeNameRepositoryImpl) Rename(raw, from, to string) (int64, error) {
+	var renamed int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.BridgeEvent{}).
+			Where("bridge_name_raw = ? AND bridge_name = ?", raw, from).
+			Update("bridge_name", to)
+		if result.Error != nil {
+			return result.Error
+		}
+		renamed = result.RowsAffected
+
+		// Filters by a decoded name are left alone, other raw names may decode to it as well
+		if from != raw {
+			return nil
+		}
+		if err := tx.Model(&models.Webhook{}).Where("bridge_name = ?", from).Update("bridge_name", to).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.AlertRule{}).Where("bridge_name = ?", from).Update("bridge_name", to).Error
+	})
+
+	return renamed, err
+}
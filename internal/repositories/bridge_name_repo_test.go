@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const rawBridgeName = "0x686f700000000000000000000000000000000000000000000000000000000000"
+
+func TestBridgeNameRepository_ListUnknown(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewBridgeNameRepository(gormDB)
+
+	day := time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT bridge_name, SUM\(event_count\) AS event_count, (.+) FROM "bridge_volume_daily" WHERE bridge_name ~ \$1 GROUP BY "bridge_name"`).
+		WithArgs(unknownBridgeNamePattern).
+		WillReturnRows(sqlmock.NewRows([]string{"bridge_name", "event_count", "first_seen", "last_seen"}).
+			AddRow(rawBridgeName, 7, day, day.AddDate(0, 0, 2)))
+
+	names, err := repo.ListUnknown()
+
+	assert.NoError(t, err)
+	assert.Len(t, names, 1)
+	assert.Equal(t, int64(7), names[0].EventCount)
+	assert.Equal(t, day.AddDate(0, 0, 2), names[0].LastSeen)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBridgeNameRepository_Rename(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewBridgeNameRepository(gormDB)
+
+	// Undecoded name, filters of webhooks and alert rules are renamed as well
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "bridge_events" SET "bridge_name"=\$1 WHERE bridge_name_raw = \$2 AND bridge_name = \$3`).
+		WithArgs("hop", rawBridgeName, rawBridgeName).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectExec(`UPDATE "webhooks" SET "bridge_name"=\$1,"updated_at"=\$2 WHERE bridge_name = \$3`).
+		WithArgs("hop", sqlmock.AnyArg(), rawBridgeName).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "alert_rules" SET "bridge_name"=\$1 WHERE bridge_name = \$2`).
+		WithArgs("hop", rawBridgeName).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	renamed, err := repo.Rename(rawBridgeName, rawBridgeName, "hop")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), renamed)
+
+	// Decoded name, only events are renamed
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "bridge_events" SET "bridge_name"=\$1 WHERE bridge_name_raw = \$2 AND bridge_name = \$3`).
+		WithArgs("hop-protocol", rawBridgeName, "hop").
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectCommit()
+
+	_, err = repo.Rename(rawBridgeName, "hop", "hop-protocol")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
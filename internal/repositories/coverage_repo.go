@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CoverageRepository interface {
+	// AddRange records the blocks [from, to] of the chain as covered, merging it with
+	// overlapping and adjacent ranges so a chain ingested without holes is a single range
+	AddRange(chainID string, from, to uint64, at time.Time) error
+	// ListRanges returns the covered ranges of every chain, ordered by chain and first block
+	ListRanges() ([]models.BlockRange, error)
+	// LastCovered returns the highest block of the chain recorded as covered, false when none was
+	LastCovered(chainID string) (uint64, bool, error)
+}
+
+type coverageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCoverageRepository(db *gorm.DB) CoverageRepository {
+	return &coverageRepositoryImpl{db: db}
+}
+
+func (r *coverageRepositoryImpl) AddRange(chainID string, from, to uint64, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Live ingestion and backfills of a chain record ranges concurrently, merges of a chain are serialized
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "block_coverage:"+chainID).Error; err != nil {
+			return err
+		}
+
+		var touching []models.BlockRange
+		err := tx.Where("chain_id = ? AND from_block <= ? AND to_block >= ?", chainID, to+1, max(from, 1)-1).
+			Find(&touching).Error
+		if err != nil {
+			return err
+		}
+
+		merged := models.BlockRange{ChainID: chainID, FromBlock: from, ToBlock: to, CreatedAt: at, UpdatedAt: at}
+		ids := make([]int, 0, len(touching))
+		for _, existing := range touching {
+			merged.FromBlock = min(merged.FromBlock, existing.FromBlock)
+			merged.ToBlock = max(merged.ToBlock, existing.ToBlock)
+			if existing.CreatedAt.Before(merged.CreatedAt) {
+				merged.CreatedAt = existing.CreatedAt
+			}
+			ids = append(ids, existing.ID)
+		}
+
+		if len(ids) > 0 {
+			if err := tx.Delete(&models.BlockRange{}, ids).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&merged).Error
+	})
+}
+
+func (r *coverageRepositoryImpl) ListRanges() ([]models.BlockRange, error) {
+	var ranges []models.BlockRange
+
+	err := r.db.Order("chain_id asc, from_block asc").Find(&ranges).Error
+	return ranges, err
+}
+
+func (r *coverageRepositoryImpl) LastCovered(chainID string) (uint64, bool, error) {
+	var last sql.NullInt64
+
+	err := r.db.Raw("SELECT MAX(to_block) FROM block_coverage WHERE chain_id = ?", chainID).Scan(&last).Error
+
+	return uint64(last.Int64), last.Valid, err
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverageRepository_AddRange(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewCoverageRepository(gormDB)
+
+	now := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+		WithArgs("block_coverage:1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "block_coverage" WHERE chain_id = \$1 AND from_block <= \$2 AND to_block >= \$3`).
+		WithArgs("1", uint64(301), uint64(200)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "chain_id", "from_block", "to_block", "created_at", "updated_at"}).
+			AddRow(3, "1", 100, 200, earlier, earlier).
+			AddRow(4, "1", 250, 260, now, now))
+	mock.ExpectExec(`DELETE FROM "block_coverage" WHERE "block_coverage"."id" IN \(\$1,\$2\)`).
+		WithArgs(3, 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	// Merged with both, keeping when the first was recorded
+	mock.ExpectQuery(`INSERT INTO "block_coverage" \("chain_id","from_block","to_block","created_at","updated_at"\) VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING "id"`).
+		WithArgs("1", uint64(100), uint64(300), earlier, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	err := repo.AddRange("1", 201, 300, now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCoverageRepository_LastCovered(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewCoverageRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT MAX\(to_block\) FROM block_coverage WHERE chain_id = \$1`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(300))
+	mock.ExpectQuery(`SELECT MAX\(to_block\) FROM block_coverage WHERE chain_id = \$1`).
+		WithArgs("2").
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+
+	last, ok, err := repo.LastCovered("1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(300), last)
+
+	// Nothing recorded for the chain yet
+	_, ok, err = repo.LastCovered("2")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
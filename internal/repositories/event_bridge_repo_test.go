@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 	"time"
@@ -64,20 +65,76 @@ func TestBridgeEventRepository_Save(t *testing.T) {
 	defer TearDown(t)
 
 	mock.ExpectBegin()
+	// Key is claimed before the event is inserted
+	mock.ExpectExec(`INSERT INTO bridge_event_keys (.+) ON CONFLICT DO NOTHING`).
+		WithArgs(events[0].TransactionHash, uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	// Mock the Create operation with RETURNING "id"
-	mock.ExpectQuery(`INSERT INTO "bridge_events" (.+) VALUES (.+)`).
+	mock.ExpectQuery(`INSERT INTO "bridge_events" (.+) VALUES (.+) RETURNING "id"`).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	// Event is added to both hourly and daily rollups
+	mock.ExpectExec(`INSERT INTO bridge_volume_hourly (.+) ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO bridge_volume_daily (.+) ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 	// Define the event to be saved
+	event := *events[0]
+	event.BlockNumber = 21400000
+	event.LogIndex = 3
 
 	// Call the Save method
-	err := repo.Save(events[0])
+	err := repo.Save(&event)
 
 	// Assert that no error occurred and the SQL statements were as expected
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestBridgeEventRepository_Save_Redelivered(t *testing.T) {
+	mock, repo, _ := Setup(t)
+	defer TearDown(t)
+
+	mock.ExpectBegin()
+	// Key is already claimed, so neither the event nor the rollups must be touched
+	mock.ExpectExec(`INSERT INTO bridge_event_keys (.+) ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	event := *events[1]
+	event.BlockNumber = 21400000
+	event.LogIndex = 7
+
+	err := repo.Save(&event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, event.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBridgeEventRepository_Save_WithoutBlockNumber(t *testing.T) {
+	mock, repo, _ := Setup(t)
+	defer TearDown(t)
+
+	// Nothing to deduplicate on, no key is claimed
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "bridge_events" (.+) VALUES (.+) RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+	mock.ExpectExec(`INSERT INTO bridge_volume_hourly (.+) ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO bridge_volume_daily (.+) ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	event := *events[1]
+
+	err := repo.Save(&event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, event.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestBridgeEventRepository_GetAll(t *testing.T) {
 	// Create a mock database connection
 	mock, repo, _ := Setup(t)
@@ -92,10 +149,74 @@ func TestBridgeEventRepository_GetAll(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the GetAll method
-	fetchedEvents, err := repo.GetAll(0, 2, "WEI")
+	fetchedEvents, err := repo.GetAll(models.EventFilter{}, 0, 2)
 
 	// Assert that no error occurred, and the result matches the expected fetchedEvents
 	assert.NoError(t, err)
 	assert.Len(t, fetchedEvents, 2)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestBridgeEventRepository_GetAll_Filtered(t *testing.T) {
+	mock, repo, _ := Setup(t)
+	defer TearDown(t)
+
+	rows := sqlmock.NewRows([]string{"id", "transaction_hash", "token", "amount", "from_chain", "to_chain", "timestamp"}).
+		AddRow(events[1].ID, events[1].TransactionHash, events[1].Token, events[1].Amount, events[1].FromChain, events[1].ToChain, events[1].Timestamp)
+
+	mock.ExpectQuery(`SELECT (.+) FROM "bridge_events" WHERE id < (.+) AND token = (.+) AND dest_chain_id = (.+) ORDER BY timestamp desc`).
+		WillReturnRows(rows)
+
+	filter := models.EventFilter{Token: events[1].Token, DestChainID: "10"}
+	fetchedEvents, err := repo.GetAll(filter, 5, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, fetchedEvents, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBridgeEventRepository_GetAfter(t *testing.T) {
+	mock, repo, _ := Setup(t)
+	defer TearDown(t)
+
+	rows := sqlmock.NewRows([]string{"id", "transaction_hash", "token", "amount", "from_chain", "to_chain", "timestamp"}).
+		AddRow(4, events[0].TransactionHash, events[0].Token, events[0].Amount, events[0].FromChain, events[0].ToChain, events[0].Timestamp).
+		AddRow(5, events[1].TransactionHash, events[1].Token, events[1].Amount, events[1].FromChain, events[1].ToChain, events[1].Timestamp)
+
+	mock.ExpectQuery(`SELECT (.+) FROM "bridge_events" WHERE id > (.+) ORDER BY id asc LIMIT (.+)`).
+		WillReturnRows(rows)
+
+	fetchedEvents, err := repo.GetAfter(models.EventFilter{}, 3, 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, fetchedEvents, 2)
+	assert.Equal(t, 4, fetchedEvents[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBridgeEventRepository_StreamEvents(t *testing.T) {
+	mock, repo, _ := Setup(t)
+	defer TearDown(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DECLARE bridge_events_export NO SCROLL CURSOR FOR SELECT (.+) FROM "bridge_events" WHERE token = (.+) ORDER BY id asc`).
+		WithArgs(events[0].Token).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FETCH 1000 FROM bridge_events_export`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "token", "amount"}).
+			AddRow(1, events[0].Token, events[0].Amount).
+			AddRow(3, events[0].Token, events[0].Amount))
+	mock.ExpectExec(`CLOSE bridge_events_export`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	var streamed []int
+	err := repo.StreamEvents(context.Background(), models.EventFilter{Token: events[0].Token}, func(event models.BridgeEvent) error {
+		streamed = append(streamed, event.ID)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, streamed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
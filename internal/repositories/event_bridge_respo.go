@@ -1,18 +1,62 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/eth-bridging/config"
 	"github.com/eth-bridging/internal/models"
 
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
+const (
+	// exportCursor is the name of the cursor used to stream events, it's scoped to a transaction
+	exportCursor = "bridge_events_export"
+	// exportCursorBatchSize is the number of rows fetched from the cursor at once
+	exportCursorBatchSize = 1000
+)
+
+// eventColumns are the raw columns of every event
+var eventColumns = []string{
+	"id",
+	"token",
+	"amount",
+	"from_chain",
+	"to_chain",
+	"dest_chain_id",
+	"bridge_name",
+	"bridge_name_raw",
+	"transaction_hash",
+	"block_number",
+	"log_index",
+	"timestamp",
+	"block_timestamp",
+	"usd_value",
+	"gas_used",
+	"effective_gas_price",
+	"tx_status",
+	"tx_from",
+	"tx_to",
+	"event_name",
+	"payload",
+}
+
 type BridgeEventRepository interface {
 	Save(event *models.BridgeEvent) error
-	GetAll(lastID uint, limit int, currency string) ([]models.BridgeEvent, error)
+	// GetAll returns events newest first, amounts are left in WEI
+	GetAll(filter models.EventFilter, lastID uint, limit int) ([]models.BridgeEvent, error)
+	// GetAfter returns events with id greater than afterID in ascending id order,
+	// it's used to replay events a live subscriber missed. Amounts are left in WEI
+	GetAfter(filter models.EventFilter, afterID uint, limit int) ([]models.BridgeEvent, error)
+	// StreamEvents walks every event matching the filter in ascending id order using a
+	// server side cursor, fn is called for each event and aborts the walk by returning an error.
+	// Amounts are left in WEI
+	StreamEvents(ctx context.Context, filter models.EventFilter, fn func(models.BridgeEvent) error) error
+	// ListByBlockRange returns events of the blocks [from, to] ordered by block and log index.
+	// Amounts are left in WEI
+	ListByBlockRange(from, to uint64) ([]models.BridgeEvent, error)
 }
 
 type bridgeEventRepositoryImpl struct {
@@ -24,61 +68,115 @@ func NewBridgeEventRepository(db *gorm.DB, cfg *config.Config) BridgeEventReposi
 	return &bridgeEventRepositoryImpl{db: db, cfg: cfg}
 }
 
+// Save inserts the event and adds it to the volume rollups in a single transaction.
+//
+// Events are unique by (transaction_hash, log_index), so a redelivered event
+// is silently skipped and event.ID is left untouched.
+//
+//	Note:
+//	  `bridge_events` is partitioned by month, uniqueness can't be enforced on it across
+//	  partitions, so the key is claimed in `bridge_event_keys` first
 func (r *bridgeEventRepositoryImpl) Save(event *models.BridgeEvent) error {
-	return r.db.Omit("TxnCurrency").Create(event).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Events without a block number can't be told apart, they're always inserted
+		if event.BlockNumber > 0 {
+			result := tx.Exec("INSERT INTO bridge_event_keys (transaction_hash, log_index) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				event.TransactionHash, event.LogIndex)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// Already saved by an earlier delivery, it's been counted in the rollups as well
+			if result.RowsAffected == 0 {
+				return nil
+			}
+		}
+
+		// Other events only have a payload, amount is left NULL and they aren't part of the volume
+		if !event.IsSocketBridge() {
+			return tx.Omit("TxnCurrency", "Amount").Create(event).Error
+		}
+
+		if err := tx.Omit("TxnCurrency").Create(event).Error; err != nil {
+			return err
+		}
+
+		return applyRollups(tx, event)
+	})
 }
 
-func (r *bridgeEventRepositoryImpl) GetAll(lastID uint, limit int, currency string) ([]models.BridgeEvent, error) {
+func (r *bridgeEventRepositoryImpl) GetAll(filter models.EventFilter, lastID uint, limit int) ([]models.BridgeEvent, error) {
 	var events []models.BridgeEvent
 
-	amountSQL := r.generateAmountSQL(currency)
-	currencySQL := r.generateCurrencySQL(currency)
-
 	// Build the base query
-	query := r.db.Select("id",
-		"token",
-		amountSQL.SQL,
-		currencySQL.SQL,
-		"from_chain",
-		"to_chain",
-		"transaction_hash",
-		"timestamp",
-	).Order("timestamp desc").Limit(limit)
+	query := r.db.Select(eventColumns).Order("timestamp desc").Limit(limit)
 
 	// If a cursor is provided, use it for keyset pagination
 	if lastID != 0 {
 		query = query.Where("id < ?", lastID)
 	}
 
+	query = applyEventFilter(query, filter)
+
 	// Execute the query
 	err := query.Debug().Find(&events).Error
 	return events, err
 }
 
-// generateCurrencySQL generates the CASE statements for amount and currency fields
-func (r *bridgeEventRepositoryImpl) generateAmountSQL(currency string) clause.Expr {
-	currencyDetails := r.cfg.GetCurrencyDetails(currency)
+func (r *bridgeEventRepositoryImpl) GetAfter(filter models.EventFilter, afterID uint, limit int) ([]models.BridgeEvent, error) {
+	var events []models.BridgeEvent
+
+	query := r.db.Select(eventColumns).
+		Where("id > ?", afterID).
+		Order("id asc").
+		Limit(limit)
 
-	// This is not prone to sql injection
-	// as Factor is not sent by user, instead
-	// comes from our own config, so if user says
-	// something wrong, it will be set to default
-	// values
-	amountExpr := gorm.Expr(fmt.Sprintf("(amount::numeric / POWER(10, %d))::text as amount", currencyDetails.Factor))
+	query = applyEventFilter(query, filter)
 
-	return amountExpr
+	err := query.Find(&events).Error
+	return events, err
 }
 
-// generateCurrencySQL generates the CASE statements for amount and currency fields
-func (r *bridgeEventRepositoryImpl) generateCurrencySQL(currency string) clause.Expr {
-	currencyDetails := r.cfg.GetCurrencyDetails(currency)
+func (r *bridgeEventRepositoryImpl) ListByBlockRange(from, to uint64) ([]models.BridgeEvent, error) {
+	var events []models.BridgeEvent
 
-	// This is not prone to sql injection
-	// as Currency is not sent by user, instead
-	// comes from our own config, so if user says
-	// something wrong, it will be set to default
-	// values
-	currencyExpr := gorm.Expr(fmt.Sprintf("'%s'::text AS txn_currency", currencyDetails.Currency))
+	err := r.db.Select(eventColumns).
+		Where("block_number >= ? AND block_number <= ?", from, to).
+		Order("block_number asc, log_index asc").
+		Find(&events).Error
+	return events, err
+}
 
-	return currencyExpr
+func (r *bridgeEventRepositoryImpl) StreamEvents(ctx context.Context, filter models.EventFilter, fn func(models.BridgeEvent) error) error {
+	// Cursors only live within a transaction, which is read only as we never write here
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Render the filtered query without running it, so it can be declared as a cursor
+		stmt := applyEventFilter(
+			tx.Session(&gorm.Session{DryRun: true}).Table("bridge_events").Select(eventColumns).Order("id asc"),
+			filter,
+		).Find(&[]models.BridgeEvent{}).Statement
+
+		declareSQL := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", exportCursor, stmt.SQL.String())
+		if err := tx.Exec(declareSQL, stmt.Vars...).Error; err != nil {
+			return err
+		}
+
+		for {
+			var batch []models.BridgeEvent
+			fetchSQL := fmt.Sprintf("FETCH %d FROM %s", exportCursorBatchSize, exportCursor)
+			if err := tx.Raw(fetchSQL).Scan(&batch).Error; err != nil {
+				return err
+			}
+
+			for _, event := range batch {
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+
+			if len(batch) < exportCursorBatchSize {
+				return tx.Exec("CLOSE " + exportCursor).Error
+			}
+		}
+	}, &sql.TxOptions{ReadOnly: true})
 }
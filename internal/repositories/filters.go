@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"sort"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// applyEventFilter narrows the query down using the non zero values of the filter
+//
+// Time range is half open, `From` is inclusive and `To` is exclusive
+func applyEventFilter(query *gorm.DB, filter models.EventFilter) *gorm.DB {
+	if filter.AfterID != 0 {
+		query = query.Where("id > ?", filter.AfterID)
+	}
+	if filter.EventName != "" {
+		query = query.Where("event_name = ?", filter.EventName)
+	}
+	// Sorted so the same filter always renders the same SQL
+	names := make([]string, 0, len(filter.Payload))
+	for name := range filter.Payload {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		query = query.Where("payload ->> ? = ?", name, filter.Payload[name])
+	}
+
+	return applyTimeRange(applyRouteFilter(query, filter), "timestamp", filter)
+}
+
+// applyRouteFilter narrows the query down by token, destination chain and bridge name,
+// these columns are shared by raw events and the rollup tables
+func applyRouteFilter(query *gorm.DB, filter models.EventFilter) *gorm.DB {
+	if filter.Token != "" {
+		query = query.Where("token = ?", filter.Token)
+	}
+	if filter.DestChainID != "" {
+		query = query.Where("dest_chain_id = ?", filter.DestChainID)
+	}
+	if filter.BridgeName != "" {
+		query = query.Where("bridge_name = ?", filter.BridgeName)
+	}
+
+	return query
+}
+
+// applyTimeRange limits `column` to [filter.From, filter.To)
+func applyTimeRange(query *gorm.DB, column string, filter models.EventFilter) *gorm.DB {
+	if !filter.From.IsZero() {
+		query = query.Where(column+" >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where(column+" < ?", filter.To)
+	}
+
+	return query
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type LifecycleRepository interface {
+	// ObserveTransfers creates observed transfers of at most limit SocketBridge events made since the time
+	// which don't have one yet, along with their first transition. Returns the number created
+	ObserveTransfers(since, now time.Time, limit int) (int64, error)
+	// ListInState returns transfers in any of the states which were created since the time,
+	// in ascending id order starting after afterID
+	ListInState(states []models.TransferState, since time.Time, afterID, limit int) ([]models.TransferRecord, error)
+	// Transition records the transition and moves the transfer to its state, along with its block number.
+	// false if the transfer left the transition's from state in the meantime
+	Transition(transfer models.TransferRecord, transition models.TransferTransition) (bool, error)
+	// ListByTx returns the transfers of the source transaction
+	ListByTx(txHash string) ([]models.TransferRecord, error)
+	// ListTransitions returns the transitions of the transfers, oldest first
+	ListTransitions(transferIDs []int) ([]models.TransferTransition, error)
+}
+
+type lifecycleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewLifecycleRepository(db *gorm.DB) LifecycleRepository {
+	return &lifecycleRepositoryImpl{db: db}
+}
+
+func (r *lifecycleRepositoryImpl) ObserveTransfers(since, now time.Time, limit int) (int64, error) {
+	result := r.db.Exec(`WITH observed AS (
+			INSERT INTO transfers
+				(source_event_id, transaction_hash, log_index, bridge_name, dest_chain_id, block_number, state, created_at, updated_at)
+			SELECT id, transaction_hash, log_index, bridge_name, dest_chain_id, block_number, ?, ?, ?
+			FROM bridge_events
+			WHERE event_name = ? AND timestamp >= ?
+			AND NOT EXISTS (SELECT 1 FROM transfers WHERE transfers.source_event_id = bridge_events.id)
+			ORDER BY id
+			LIMIT ?
+			ON CONFLICT (source_event_id) DO NOTHING
+			RETURNING id
+		)
+		INSERT INTO transfer_transitions (transfer_id, from_state, to_state, reason, created_at)
+		SELECT id, '', ?, ?, ? FROM observed`,
+		models.StateObserved, now, now, models.EventSocketBridge, since, limit,
+		models.StateObserved, "SocketBridge event saved", now)
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *lifecycleRepositoryImpl) ListInState(states []models.TransferState, since time.Time, afterID, limit int) ([]models.TransferRecord, error) {
+	var transfers []models.TransferRecord
+
+	err := r.db.Where("state IN ? AND created_at >= ? AND id > ?", states, since, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&transfers).Error
+	return transfers, err
+}
+
+func (r *lifecycleRepositoryImpl) Transition(transfer models.TransferRecord, transition models.TransferTransition) (bool, error) {
+	moved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TransferRecord{}).
+			Where("id = ? AND state = ?", transition.TransferID, transition.FromState).
+			Updates(map[string]interface{}{
+				"state":        transition.ToState,
+				"block_number": transfer.BlockNumber,
+				"updated_at":   transition.CreatedAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		moved = true
+		return tx.Create(&transition).Error
+	})
+
+	return moved, err
+}
+
+func (r *lifecycleRepositoryImpl) ListByTx(txHash string) ([]models.TransferRecord, error) {
+	var transfers []models.TransferRecord
+
+	err := r.db.Where("transaction_hash = ?", txHash).Order("log_index asc").Find(&transfers).Error
+	return transfers, err
+}
+
+func (r *lifecycleRepositoryImpl) ListTransitions(transferIDs []int) ([]models.TransferTransition, error) {
+	var transitions []models.TransferTransition
+	if len(transferIDs) == 0 {
+		return transitions, nil
+	}
+
+	err := r.db.Where("transfer_id IN ?", transferIDs).Order("id asc").Find(&transitions).Error
+	return transitions, err
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleRepository_Transition(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewLifecycleRepository(gormDB)
+
+	now := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	transfer := models.TransferRecord{ID: 7, BlockNumber: 108, State: models.StateReorged}
+	transition, err := transfer.Transition(models.StateObserved, "transaction included again in block 108", now)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "transfers" SET "block_number"=\$1,"state"=\$2,"updated_at"=\$3 WHERE id = \$4 AND state = \$5`).
+		WithArgs(uint64(108), models.StateObserved, now, 7, models.StateReorged).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO "transfer_transitions" \("transfer_id","from_state","to_state","reason","created_at"\) VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING "id"`).
+		WithArgs(7, models.StateReorged, models.StateObserved, transition.Reason, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(31))
+	mock.ExpectCommit()
+
+	moved, err := repo.Transition(transfer, transition)
+
+	assert.NoError(t, err)
+	assert.True(t, moved)
+
+	// Moved in the meantime, nothing is recorded
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "transfers" SET (.+) WHERE id = \$4 AND state = \$5`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	moved, err = repo.Transition(transfer, transition)
+
+	assert.NoError(t, err)
+	assert.False(t, moved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// partitionPrefix is the name prefix of monthly `bridge_events` partitions, e.g. `bridge_events_p202412`
+const partitionPrefix = "bridge_events_p"
+
+type PartitionRepository interface {
+	// EnsurePartitions creates the monthly partitions of `bridge_events` from the month
+	// of `from` up to `months` months ahead of it, returns the names of the created ones
+	EnsurePartitions(from time.Time, months int) ([]string, error)
+	// ListPartitions returns names of every monthly partition, oldest first
+	ListPartitions() ([]string, error)
+}
+
+type partitionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPartitionRepository(db *gorm.DB) PartitionRepository {
+	return &partitionRepositoryImpl{db: db}
+}
+
+func (r *partitionRepositoryImpl) EnsurePartitions(from time.Time, months int) ([]string, error) {
+	var created []string
+
+	existing, err := r.ListPartitions()
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		exists[name] = true
+	}
+
+	month := partitionMonth(from)
+	for i := 0; i <= months; i++ {
+		start := month.AddDate(0, i, 0)
+		name := partitionName(start)
+		if exists[name] {
+			continue
+		}
+
+		// Identifiers can't be bound, name and bounds are derived from a time.Time so they're safe.
+		// Creating a partition fails if the default partition already holds rows of its month
+		createSQL := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF bridge_events FOR VALUES FROM ('%s') TO ('%s')",
+			name, start.Format(time.DateOnly), start.AddDate(0, 1, 0).Format(time.DateOnly))
+		if err := r.db.Exec(createSQL).Error; err != nil {
+			return created, fmt.Errorf("failed to create partition %s: %w", name, err)
+		}
+		created = append(created, name)
+	}
+
+	return created, nil
+}
+
+func (r *partitionRepositoryImpl) ListPartitions() ([]string, error) {
+	var names []string
+
+	err := r.db.Raw(`SELECT child.relname
+		FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname = 'bridge_events' AND child.relname LIKE ?
+		ORDER BY child.relname`, partitionPrefix+"%").Scan(&names).Error
+
+	return names, err
+}
+
+// partitionMonth returns the start of the month of t, which is the lower bound of its partition
+func partitionMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// partitionName returns the name of the partition holding the month of t
+func partitionName(t time.Time) string {
+	return partitionPrefix + t.Format("200601")
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionRepository_EnsurePartitions(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewPartitionRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT child.relname FROM pg_inherits (.+) WHERE parent.relname = 'bridge_events'`).
+		WithArgs("bridge_events_p%").
+		WillReturnRows(sqlmock.NewRows([]string{"relname"}).AddRow("bridge_events_p202412"))
+	// December exists, the following two months are created, spanning the year boundary
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS bridge_events_p202501 PARTITION OF bridge_events FOR VALUES FROM \('2025-01-01'\) TO \('2025-02-01'\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS bridge_events_p202502 PARTITION OF bridge_events FOR VALUES FROM \('2025-02-01'\) TO \('2025-03-01'\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	created, err := repo.EnsurePartitions(time.Date(2024, 12, 14, 14, 17, 3, 0, time.UTC), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bridge_events_p202501", "bridge_events_p202502"}, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPartitionName(t *testing.T) {
+	ts := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), partitionMonth(ts))
+	assert.Equal(t, "bridge_events_p202403", partitionName(ts))
+}
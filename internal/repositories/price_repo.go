@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// priceImportBatchSize is the number of prices upserted per statement
+const priceImportBatchSize = 1000
+
+type PriceRepository interface {
+	// LatestPrice returns the most recent price of the symbol in [from, at],
+	// zero `from` leaves the range open. ok is false if there is no such price
+	LatestPrice(symbol string, from, at time.Time) (price models.TokenPrice, ok bool, err error)
+	// UpsertPrices saves the prices, replacing existing ones of the same symbol and timestamp
+	UpsertPrices(prices []models.TokenPrice) error
+}
+
+type priceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPriceRepository(db *gorm.DB) PriceRepository {
+	return &priceRepositoryImpl{db: db}
+}
+
+func (r *priceRepositoryImpl) LatestPrice(symbol string, from, at time.Time) (models.TokenPrice, bool, error) {
+	var price models.TokenPrice
+
+	query := r.db.Where("symbol = ? AND timestamp <= ?", symbol, at)
+	if !from.IsZero() {
+		query = query.Where("timestamp >= ?", from)
+	}
+
+	err := query.Order("timestamp desc").Take(&price).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return price, false, nil
+	}
+
+	return price, err == nil, err
+}
+
+func (r *priceRepositoryImpl) UpsertPrices(prices []models.TokenPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "timestamp"}},
+		DoUpdates: clause.AssignmentColumns([]string{"usd_price"}),
+	}).CreateInBatches(prices, priceImportBatchSize).Error
+}
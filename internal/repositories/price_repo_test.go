@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceRepository_LatestPrice(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewPriceRepository(gormDB)
+
+	at := time.Date(2024, 12, 14, 14, 0, 0, 0, time.UTC)
+	from := at.Add(-24 * time.Hour)
+	priced := time.Date(2024, 12, 14, 13, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT \* FROM "token_prices" WHERE \(symbol = \$1 AND timestamp <= \$2\) AND timestamp >= \$3 ORDER BY timestamp desc LIMIT \$4`).
+		WithArgs("ETH", at, from, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "timestamp", "usd_price"}).AddRow("ETH", priced, "3905.12"))
+
+	price, ok, err := repo.LatestPrice("ETH", from, at)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "3905.12", price.USDPrice)
+	assert.Equal(t, priced, price.Timestamp)
+
+	// No price in range
+	mock.ExpectQuery(`SELECT \* FROM "token_prices" WHERE symbol = \$1 AND timestamp <= \$2 ORDER BY timestamp desc LIMIT \$3`).
+		WithArgs("DAI", at, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "timestamp", "usd_price"}))
+
+	_, ok, err = repo.LatestPrice("DAI", time.Time{}, at)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// rollupTables maps the `date_trunc` interval to the table holding
+// pre-aggregated volume for that interval
+var rollupTables = []struct {
+	interval string
+	table    string
+}{
+	{interval: "hour", table: "bridge_volume_hourly"},
+	{interval: "day", table: "bridge_volume_daily"},
+}
+
+// rollupRangeStart and rollupRangeEnd bound a rebuild when no range is given
+var (
+	rollupRangeStart = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	rollupRangeEnd   = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type RollupRepository interface {
+	// Rebuild recomputes the hourly and daily rollups for [from, to) from the raw events.
+	//
+	// Range is widened to whole days so no daily bucket is partially rebuilt,
+	// zero `from` or `to` means the range is open on that side
+	Rebuild(from, to time.Time) error
+}
+
+type rollupRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRollupRepository(db *gorm.DB) RollupRepository {
+	return &rollupRepositoryImpl{db: db}
+}
+
+func (r *rollupRepositoryImpl) Rebuild(from, to time.Time) error {
+	// Open ends are replaced with bounds no event can fall outside of
+	lower, upper := rollupRangeStart, rollupRangeEnd
+	if !from.IsZero() {
+		lower = rollupBucket(from, "day")
+	}
+	if !to.IsZero() {
+		upper = rollupBucket(to, "day")
+		if upper.Before(to) {
+			upper = upper.AddDate(0, 0, 1)
+		}
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Block consumers from updating rollups while they're being recomputed,
+		// otherwise an event saved in between might get counted twice
+		if err := tx.Exec("LOCK TABLE bridge_volume_hourly, bridge_volume_daily IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		for _, rollup := range rollupTables {
+			deleteSQL := fmt.Sprintf("DELETE FROM %s WHERE bucket >= ? AND bucket < ?", rollup.table)
+			insertSQL := fmt.Sprintf(`INSERT INTO %s (bucket, token, dest_chain_id, bridge_name, event_count, total_amount, total_usd, unpriced_count)
+				SELECT date_trunc('%s', timestamp), token, dest_chain_id, bridge_name, COUNT(*), SUM(amount),
+					COALESCE(SUM(usd_value), 0), COUNT(*) - COUNT(usd_value)
+				FROM bridge_events
+				WHERE timestamp >= ? AND timestamp < ? AND event_name = 'SocketBridge'
+				GROUP BY 1, 2, 3, 4`, rollup.table, rollup.interval)
+
+			if err := tx.Exec(deleteSQL, lower, upper).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(insertSQL, lower, upper).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// applyRollups adds a freshly inserted event to every rollup table,
+// it must run in the same transaction as the insert, so a redelivered
+// event is never counted twice
+//
+// Events without a USD value are counted in `unpriced_count` instead of `total_usd`
+func applyRollups(tx *gorm.DB, event *models.BridgeEvent) error {
+	unpriced := 0
+	if event.USDValue == nil {
+		unpriced = 1
+	}
+
+	for _, rollup := range rollupTables {
+		upsertSQL := fmt.Sprintf(`INSERT INTO %s AS r (bucket, token, dest_chain_id, bridge_name, event_count, total_amount, total_usd, unpriced_count)
+			VALUES (?, ?, ?, ?, 1, ?::numeric, COALESCE(?::numeric, 0), ?)
+			ON CONFLICT (bucket, token, dest_chain_id, bridge_name)
+			DO UPDATE SET event_count = r.event_count + 1,
+				total_amount = r.total_amount + EXCLUDED.total_amount,
+				total_usd = r.total_usd + EXCLUDED.total_usd,
+				unpriced_count = r.unpriced_count + EXCLUDED.unpriced_count`, rollup.table)
+
+		err := tx.Exec(upsertSQL,
+			rollupBucket(event.Timestamp, rollup.interval),
+			event.Token,
+			event.DestChainID,
+			event.BridgeName,
+			event.Amount,
+			event.USDValue,
+			unpriced,
+		).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollupBucket truncates t the same way `date_trunc` does on a `TIMESTAMP` column,
+// i.e. using the wall clock time, which is what ends up being stored
+func rollupBucket(t time.Time, interval string) time.Time {
+	if interval == "hour" {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
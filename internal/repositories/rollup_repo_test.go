@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollupRepository_Rebuild(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewRollupRepository(gormDB)
+
+	from := time.Date(2024, 12, 14, 13, 30, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 15, 6, 0, 0, 0, time.UTC)
+	// Range is widened to whole days
+	lower := time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC)
+	upper := time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`LOCK TABLE bridge_volume_hourly, bridge_volume_daily`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, table := range []string{"bridge_volume_hourly", "bridge_volume_daily"} {
+		mock.ExpectExec(`DELETE FROM `+table).
+			WithArgs(lower, upper).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec(`INSERT INTO `+table+` (.+) SELECT (.+) FROM bridge_events`).
+			WithArgs(lower, upper).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+	}
+	mock.ExpectCommit()
+
+	err := repo.Rebuild(from, to)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRollupBucket(t *testing.T) {
+	ts := time.Date(2024, 12, 14, 13, 47, 12, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 12, 14, 13, 0, 0, 0, time.UTC), rollupBucket(ts, "hour"))
+	assert.Equal(t, time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC), rollupBucket(ts, "day"))
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SLARepository interface {
+	// RecordBreaches records a breach for every transfer of the rule's route made within [since, now - SLA)
+	// which isn't completed yet or was completed later than the SLA, the latter are recorded resolved.
+	// Transfers already recorded are skipped. Returns the number recorded
+	RecordBreaches(rule models.SLARule, since, now time.Time) (int64, error)
+	// ResolveBreaches resolves open breaches of transfers which have completed, returns the number resolved
+	ResolveBreaches() (int64, error)
+	// ListOpenBreaches returns unresolved breaches newest first, optionally of a single bridge or
+	// destination chain, using keyset pagination on id
+	ListOpenBreaches(bridgeName, destChainID string, lastID uint, limit int) ([]models.SLABreach, error)
+	// CompletionMetrics returns the completion latency percentiles of every bridge over transfers matched since
+	CompletionMetrics(since time.Time) ([]models.CompletionMetrics, error)
+}
+
+type slaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSLARepository(db *gorm.DB) SLARepository {
+	return &slaRepositoryImpl{db: db}
+}
+
+func (r *slaRepositoryImpl) RecordBreaches(rule models.SLARule, since, now time.Time) (int64, error) {
+	// Transfers matched before a run saw them open still breached, e.g. while the service was down
+	result := r.db.Exec(`INSERT INTO sla_breaches
+			(source_event_id, transaction_hash, bridge_name, dest_chain_id, token, sla_seconds, source_timestamp, detected_at, resolved_at, latency_ms)
+		SELECT bridge_events.id, bridge_events.transaction_hash, bridge_events.bridge_name, bridge_events.dest_chain_id,
+			bridge_events.token, ?, bridge_events.timestamp, ?, bridge_fills.matched_at, bridge_fills.latency_ms
+		FROM bridge_events
+		LEFT JOIN bridge_fills ON bridge_fills.source_event_id = bridge_events.id
+		WHERE bridge_events.event_name = ? AND bridge_events.bridge_name = ? AND bridge_events.dest_chain_id IN ?
+		AND bridge_events.timestamp >= ? AND bridge_events.timestamp < ?
+		AND (bridge_fills.id IS NULL OR bridge_fills.latency_ms > ?)
+		ON CONFLICT (source_event_id) DO NOTHING`,
+		int64(rule.SLA.Seconds()), now, models.EventSocketBridge, rule.BridgeName, rule.DestChainIDs,
+		since, now.Add(-rule.SLA), rule.SLA.Milliseconds())
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *slaRepositoryImpl) ResolveBreaches() (int64, error) {
+	result := r.db.Exec(`UPDATE sla_breaches
+		SET resolved_at = bridge_fills.matched_at, latency_ms = bridge_fills.latency_ms
+		FROM bridge_fills
+		WHERE bridge_fills.source_event_id = sla_breaches.source_event_id
+		AND sla_breaches.resolved_at IS NULL`)
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *slaRepositoryImpl) ListOpenBreaches(bridgeName, destChainID string, lastID uint, limit int) ([]models.SLABreach, error) {
+	var breaches []models.SLABreach
+
+	query := r.db.Where("resolved_at IS NULL").Order("id desc").Limit(limit)
+	if bridgeName != "" {
+		query = query.Where("bridge_name = ?", bridgeName)
+	}
+	if destChainID != "" {
+		query = query.Where("dest_chain_id = ?", destChainID)
+	}
+	if lastID != 0 {
+		query = query.Where("id < ?", lastID)
+	}
+
+	err := query.Find(&breaches).Error
+	return breaches, err
+}
+
+func (r *slaRepositoryImpl) CompletionMetrics(since time.Time) ([]models.CompletionMetrics, error) {
+	var metrics []models.CompletionMetrics
+
+	err := r.db.Raw(`SELECT bridge_name,
+			COUNT(*) AS completed,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY latency_ms) / 1000 AS p50_seconds,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms) / 1000 AS p95_seconds
+		FROM bridge_fills
+		WHERE matched_at >= ? AND latency_ms IS NOT NULL
+		GROUP BY bridge_name
+		ORDER BY bridge_name`, since).Scan(&metrics).Error
+	return metrics, err
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLARepository_RecordBreaches(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewSLARepository(gormDB)
+
+	now := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	since := now.Add(-24 * time.Hour)
+	rule := models.SLARule{BridgeName: "hop", DestChainIDs: []string{"10", "42161"}, SLA: 20 * time.Minute}
+
+	// Open transfers, and ones completed later than the SLA along with their completion
+	mock.ExpectExec(`INSERT INTO sla_breaches \(.+, resolved_at, latency_ms\)\s+`+
+		`SELECT (.+), bridge_fills.matched_at, bridge_fills.latency_ms\s+FROM bridge_events\s+`+
+		`LEFT JOIN bridge_fills ON bridge_fills.source_event_id = bridge_events.id\s+`+
+		`WHERE bridge_events.event_name = \$3 AND bridge_events.bridge_name = \$4 AND bridge_events.dest_chain_id IN \(\$5,\$6\)\s+`+
+		`AND bridge_events.timestamp >= \$7 AND bridge_events.timestamp < \$8\s+`+
+		`AND \(bridge_fills.id IS NULL OR bridge_fills.latency_ms > \$9\)\s+`+
+		`ON CONFLICT \(source_event_id\) DO NOTHING`).
+		WithArgs(int64(1200), now, models.EventSocketBridge, "hop", "10", "42161", since, now.Add(-20*time.Minute), int64(1200000)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	breached, err := repo.RecordBreaches(rule, since, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), breached)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSLARepository_CompletionMetrics(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewSLARepository(gormDB)
+
+	since := time.Date(2024, 12, 13, 15, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT bridge_name,\s+COUNT\(\*\) AS completed,\s+` +
+		`percentile_cont\(0.5\) WITHIN GROUP \(ORDER BY latency_ms\) / 1000 AS p50_seconds,\s+` +
+		`percentile_cont\(0.95\) WITHIN GROUP \(ORDER BY latency_ms\) / 1000 AS p95_seconds\s+` +
+		`FROM bridge_fills\s+WHERE matched_at >= \$1`).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"bridge_name", "completed", "p50_seconds", "p95_seconds"}).
+			AddRow("hop", 120, 95.5, 410.2))
+
+	metrics, err := repo.CompletionMetrics(since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CompletionMetrics{{BridgeName: "hop", Completed: 120, P50Seconds: 95.5, P95Seconds: 410.2}}, metrics)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
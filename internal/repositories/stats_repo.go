@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// statsSources maps every supported interval to the rollup table it's read from
+//
+// Weeks are rolled up from the daily table on the fly
+var statsSources = map[string]string{
+	"hour": "bridge_volume_hourly",
+	"day":  "bridge_volume_daily",
+	"week": "bridge_volume_daily",
+}
+
+type BridgeStatsRepository interface {
+	// GetVolume aggregates volume per bucket, TotalUSD and UnpricedCount are only kept when quote is `USD`
+	GetVolume(interval string, filter models.EventFilter, currency, quote string) ([]models.VolumeStat, error)
+}
+
+type bridgeStatsRepositoryImpl struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewBridgeStatsRepository(db *gorm.DB, cfg *config.Config) BridgeStatsRepository {
+	return &bridgeStatsRepositoryImpl{db: db, cfg: cfg}
+}
+
+// GetVolume aggregates bridge events into `interval` sized buckets grouped by
+// token, destination chain and bridge name.
+//
+// Volume is read from the rollup tables maintained by the consumer, so buckets
+// are included when their start falls within the filter's time range.
+// Amounts are summed as `NUMERIC` and converted to the currency with big.Int
+// arithmetic, so no precision is lost on the way
+func (r *bridgeStatsRepositoryImpl) GetVolume(interval string, filter models.EventFilter, currency, quote string) ([]models.VolumeStat, error) {
+	var stats []models.VolumeStat
+
+	// interval is interpolated into the query, so it must be one of the known values
+	table, ok := statsSources[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	// This is not prone to sql injection
+	// as interval is validated above
+	selectSQL := fmt.Sprintf(`date_trunc('%s', bucket) AS bucket,
+		token,
+		dest_chain_id,
+		bridge_name,
+		SUM(event_count) AS event_count,
+		SUM(total_amount)::text AS total_amount,
+		SUM(total_usd)::text AS total_usd,
+		SUM(unpriced_count) AS unpriced_count`, interval)
+
+	query := r.db.Table(table).
+		Select(selectSQL).
+		Group("1, token, dest_chain_id, bridge_name").
+		Order("1, token, dest_chain_id, bridge_name")
+
+	query = applyTimeRange(applyRouteFilter(query, filter), "bucket", filter)
+
+	if err := query.Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		converted, currConf, err := r.cfg.ConvertAmount(stats[i].TotalAmount, currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid total amount %q: %w", stats[i].TotalAmount, err)
+		}
+		stats[i].TotalAmount = converted
+		stats[i].TxnCurrency = currConf.Currency
+
+		if quote != models.QuoteUSD {
+			stats[i].TotalUSD, stats[i].UnpricedCount = nil, nil
+		} else if stats[i].TotalUSD != nil {
+			totalUSD := decimal.Trim(*stats[i].TotalUSD)
+			stats[i].TotalUSD = &totalUSD
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBridgeStatsRepository_GetVolume(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewBridgeStatsRepository(gormDB, config.LoadConfig())
+
+	bucket := time.Date(2024, 12, 14, 0, 0, 0, 0, time.UTC)
+	// Sum is larger than a float64 holds exactly
+	rows := sqlmock.NewRows([]string{"bucket", "token", "dest_chain_id", "bridge_name", "event_count", "total_amount", "total_usd", "unpriced_count"}).
+		AddRow(bucket, events[0].Token, "10", "0xhop", 3, "123456789012345678901234567890500000000000000000", "2500.120000", 1)
+
+	mock.ExpectQuery(`SELECT date_trunc\('day', bucket\) AS bucket,(.+)SUM\(total_amount\)::text AS total_amount,(.+)SUM\(unpriced_count\) AS unpriced_count FROM "bridge_volume_daily" WHERE token = (.+) AND bucket >= (.+) GROUP BY 1, token, dest_chain_id, bridge_name`).
+		WillReturnRows(rows)
+
+	filter := models.EventFilter{
+		Token: events[0].Token,
+		From:  bucket.Add(-24 * time.Hour),
+	}
+	stats, err := repo.GetVolume("day", filter, "ETH", "USD")
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].EventCount)
+	assert.Equal(t, "123456789012345678901234567890.5", stats[0].TotalAmount)
+	assert.Equal(t, "ETH", stats[0].TxnCurrency)
+	assert.Equal(t, "2500.12", *stats[0].TotalUSD)
+	assert.Equal(t, int64(1), *stats[0].UnpricedCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBridgeStatsRepository_GetVolume_UnsupportedInterval(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewBridgeStatsRepository(gormDB, config.LoadConfig())
+
+	stats, err := repo.GetVolume("minute'; DROP TABLE bridge_events; --", models.EventFilter{}, "ETH", "")
+
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
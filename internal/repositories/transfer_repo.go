@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SourceCriteria narrows down the SocketBridge events a fill may complete, empty fields are ignored
+type SourceCriteria struct {
+	BridgeName  string
+	DestChainID string
+	Receiver    string
+	// Source amount is within [MinAmount, MaxAmount] WEI, as the bridge keeps a fee
+	MinAmount string
+	MaxAmount string
+	// DepositField is the SocketBridge payload field holding DepositID
+	DepositField string
+	DepositID    string
+	// Source event was ingested within [From, To]
+	From time.Time
+	To   time.Time
+}
+
+type TransferRepository interface {
+	// SaveFill inserts the fill, fills are unique by (chain_id, transaction_hash, log_index)
+	// so a redelivered one is silently skipped
+	SaveFill(fill *models.BridgeFill) error
+	// ListUnmatchedFills returns fills saved since the time which aren't linked to a source event yet,
+	// in ascending id order starting after afterID
+	ListUnmatchedFills(since time.Time, afterID int, limit int) ([]models.BridgeFill, error)
+	// FindSource returns the oldest SocketBridge event matching the criteria which isn't completed
+	// by another fill yet, nil if there's none
+	FindSource(criteria SourceCriteria) (*models.BridgeEvent, error)
+	// LinkFill links the fill to the source event it completes, false if either was linked in the meantime
+	LinkFill(fillID, sourceEventID int, latency time.Duration, matchedAt time.Time) (bool, error)
+	// ListTransfers returns SocketBridge events of tracked bridges newest first, using keyset pagination on id
+	ListTransfers(filter models.TransferFilter, lastID uint, limit int) ([]models.BridgeEvent, error)
+	// ListTransfersByTx returns SocketBridge events of the transaction
+	ListTransfersByTx(txHash string) ([]models.BridgeEvent, error)
+	// FillsOf returns the fills linked to any of the source events
+	FillsOf(sourceEventIDs []int) ([]models.BridgeFill, error)
+}
+
+type transferRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepositoryImpl{db: db}
+}
+
+func (r *transferRepositoryImpl) SaveFill(fill *models.BridgeFill) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "transaction_hash"}, {Name: "log_index"}},
+		DoNothing: true,
+	}).Omit("SourceEventID", "LatencyMs", "MatchedAt", "CreatedAt").Create(fill).Error
+}
+
+func (r *transferRepositoryImpl) ListUnmatchedFills(since time.Time, afterID int, limit int) ([]models.BridgeFill, error) {
+	var fills []models.BridgeFill
+
+	err := r.db.Where("source_event_id IS NULL AND created_at >= ? AND id > ?", since, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&fills).Error
+	return fills, err
+}
+
+func (r *transferRepositoryImpl) FindSource(criteria SourceCriteria) (*models.BridgeEvent, error) {
+	query := r.db.Select(eventColumns).
+		Where("event_name = ?", models.EventSocketBridge).
+		Where("bridge_name = ? AND dest_chain_id = ?", criteria.BridgeName, criteria.DestChainID).
+		Where("timestamp >= ? AND timestamp <= ?", criteria.From, criteria.To).
+		Where("NOT EXISTS (SELECT 1 FROM bridge_fills WHERE bridge_fills.source_event_id = bridge_events.id)")
+
+	if criteria.Receiver != "" {
+		query = query.Where("LOWER(to_chain) = LOWER(?)", criteria.Receiver)
+	}
+	if criteria.MinAmount != "" {
+		query = query.Where("amount >= ? AND amount <= ?", criteria.MinAmount, criteria.MaxAmount)
+	}
+	if criteria.DepositField != "" {
+		query = query.Where("payload ->> ? = ?", criteria.DepositField, criteria.DepositID)
+	}
+
+	var event models.BridgeEvent
+	err := query.Order("timestamp asc").Take(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func (r *transferRepositoryImpl) LinkFill(fillID, sourceEventID int, latency time.Duration, matchedAt time.Time) (bool, error) {
+	// `idx_fill_source_event` is unique, so a source event is only ever completed once
+	result := r.db.Exec(`UPDATE bridge_fills SET source_event_id = ?, latency_ms = ?, matched_at = ?
+		WHERE id = ? AND source_event_id IS NULL
+		AND NOT EXISTS (SELECT 1 FROM bridge_fills WHERE source_event_id = ?)`,
+		sourceEventID, latency.Milliseconds(), matchedAt, fillID, sourceEventID)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *transferRepositoryImpl) ListTransfers(filter models.TransferFilter, lastID uint, limit int) ([]models.BridgeEvent, error) {
+	var events []models.BridgeEvent
+
+	// Nothing is tracked, so there are no transfers to list
+	if len(filter.Tracked) == 0 {
+		return events, nil
+	}
+
+	query := r.db.Select(eventColumns).
+		Where("event_name = ?", models.EventSocketBridge).
+		Order("id desc").
+		Limit(limit)
+
+	if lastID != 0 {
+		query = query.Where("id < ?", lastID)
+	}
+	if filter.BridgeName != "" {
+		query = query.Where("bridge_name = ?", filter.BridgeName)
+	}
+	if filter.DestChainID != "" {
+		query = query.Where("dest_chain_id = ?", filter.DestChainID)
+	}
+
+	tracked, args := perBridge(filter.Tracked, func(spec models.FillSpec) (string, []interface{}) {
+		return "dest_chain_id IN ?", []interface{}{spec.ChainIDs}
+	})
+	query = query.Where(tracked, args...)
+
+	completed := "EXISTS (SELECT 1 FROM bridge_fills WHERE bridge_fills.source_event_id = bridge_events.id)"
+	switch filter.Status {
+	case models.TransferCompleted:
+		query = query.Where(completed)
+	case models.TransferPending, models.TransferStuck:
+		operator := ">="
+		if filter.Status == models.TransferStuck {
+			operator = "<"
+		}
+
+		late, args := perBridge(filter.Tracked, func(spec models.FillSpec) (string, []interface{}) {
+			return "timestamp " + operator + " ?", []interface{}{filter.Now.Add(-spec.Window())}
+		})
+		query = query.Where("NOT "+completed).Where(late, args...)
+	}
+
+	err := query.Find(&events).Error
+	return events, err
+}
+
+func (r *transferRepositoryImpl) ListTransfersByTx(txHash string) ([]models.BridgeEvent, error) {
+	var events []models.BridgeEvent
+
+	err := r.db.Select(eventColumns).
+		Where("event_name = ? AND transaction_hash = ?", models.EventSocketBridge, txHash).
+		Order("log_index asc").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *transferRepositoryImpl) FillsOf(sourceEventIDs []int) ([]models.BridgeFill, error) {
+	var fills []models.BridgeFill
+	if len(sourceEventIDs) == 0 {
+		return fills, nil
+	}
+
+	err := r.db.Where("source_event_id IN ?", sourceEventIDs).Find(&fills).Error
+	return fills, err
+}
+
+// perBridge renders `(bridge_name = ? AND <condition>) OR ...` over the specs,
+// GORM wraps it in parentheses as it contains OR
+func perBridge(specs []models.FillSpec, condition func(spec models.FillSpec) (string, []interface{})) (string, []interface{}) {
+	clauses := make([]string, 0, len(specs))
+	var args []interface{}
+	for _, spec := range specs {
+		sql, specArgs := condition(spec)
+		clauses = append(clauses, "(bridge_name = ? AND "+sql+")")
+		args = append(append(args, spec.BridgeName), specArgs...)
+	}
+
+	return strings.Join(clauses, " OR "), args
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferRepository_ListTransfers_Stuck(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewTransferRepository(gormDB)
+
+	now := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	filter := models.TransferFilter{
+		Status: models.TransferStuck,
+		Tracked: []models.FillSpec{
+			{BridgeName: "hop", WindowMinutes: 60, ChainIDs: []string{"10", "42161"}},
+			{BridgeName: "across", WindowMinutes: 15, ChainIDs: []string{"10"}},
+		},
+		Now: now,
+	}
+
+	mock.ExpectQuery(`SELECT (.+) FROM "bridge_events" WHERE event_name = \$1 AND id < \$2 `+
+		`AND \(\(bridge_name = \$3 AND dest_chain_id IN \(\$4,\$5\)\) OR \(bridge_name = \$6 AND dest_chain_id IN \(\$7\)\)\) `+
+		`AND NOT EXISTS \(SELECT 1 FROM bridge_fills WHERE bridge_fills.source_event_id = bridge_events.id\) `+
+		`AND \(\(bridge_name = \$8 AND timestamp < \$9\) OR \(bridge_name = \$10 AND timestamp < \$11\)\) `+
+		`ORDER BY id desc LIMIT \$12`).
+		WithArgs(models.EventSocketBridge, 100, "hop", "10", "42161", "across", "10",
+			"hop", now.Add(-time.Hour), "across", now.Add(-15*time.Minute), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "bridge_name"}).AddRow(42, "hop"))
+
+	events, err := repo.ListTransfers(filter, 100, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransferRepository_ListTransfers_Untracked(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewTransferRepository(gormDB)
+
+	// No bridge is tracked, the database isn't queried
+	events, err := repo.ListTransfers(models.TransferFilter{}, 0, 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransferRepository_LinkFill(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewTransferRepository(gormDB)
+
+	matchedAt := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE bridge_fills SET source_event_id = \$1, latency_ms = \$2, matched_at = \$3\s+WHERE id = \$4 AND source_event_id IS NULL`).
+		WithArgs(42, int64(90000), matchedAt, 7, 42).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	linked, err := repo.LinkFill(7, 42, 90*time.Second, matchedAt)
+
+	assert.NoError(t, err)
+	assert.True(t, linked)
+
+	// Source was completed by another fill in the meantime
+	mock.ExpectExec(`UPDATE bridge_fills`).
+		WithArgs(42, int64(90000), matchedAt, 8, 42).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	linked, err = repo.LinkFill(8, 42, 90*time.Second, matchedAt)
+
+	assert.NoError(t, err)
+	assert.False(t, linked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when the requested record doesn't exist
+var ErrNotFound = errors.New("record not found")
+
+type WebhookRepository interface {
+	Create(webhook *models.Webhook) error
+	GetByID(id int) (*models.Webhook, error)
+	List() ([]models.Webhook, error)
+	// ListActive returns webhooks which should receive deliveries
+	ListActive() ([]models.Webhook, error)
+	Update(webhook *models.Webhook) error
+	Delete(id int) error
+	// RecordDelivery stores a single delivery attempt
+	RecordDelivery(delivery *models.WebhookDelivery) error
+	// ListDeliveries returns most recent delivery attempts of a webhook first
+	ListDeliveries(webhookID int, limit int) ([]models.WebhookDelivery, error)
+	// MarkSuccess resets the consecutive failure counter of the webhook
+	MarkSuccess(id int) error
+	// MarkFailure increments the consecutive failure counter of the webhook and disables it
+	// once `disableAfter` consecutive deliveries have failed, reports whether it is disabled
+	MarkFailure(id int, disableAfter int) (bool, error)
+}
+
+type webhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepositoryImpl{db: db}
+}
+
+func (r *webhookRepositoryImpl) Create(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *webhookRepositoryImpl) GetByID(id int) (*models.Webhook, error) {
+	var webhook models.Webhook
+
+	err := r.db.First(&webhook, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+
+	return &webhook, err
+}
+
+func (r *webhookRepositoryImpl) List() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+
+	err := r.db.Order("id asc").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepositoryImpl) ListActive() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+
+	err := r.db.Where("active = ?", true).Order("id asc").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepositoryImpl) Update(webhook *models.Webhook) error {
+	// Save would insert a webhook deleted meanwhile, update the existing row only
+	webhook.UpdatedAt = time.Now()
+	result := r.db.Model(&models.Webhook{}).Where("id = ?", webhook.ID).Updates(map[string]interface{}{
+		"url":                  webhook.URL,
+		"secret":               webhook.Secret,
+		"token":                webhook.Token,
+		"dest_chain_id":        webhook.DestChainID,
+		"bridge_name":          webhook.BridgeName,
+		"active":               webhook.Active,
+		"consecutive_failures": webhook.ConsecutiveFailures,
+		"disabled_at":          webhook.DisabledAt,
+		"updated_at":           webhook.UpdatedAt,
+	})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return result.Error
+}
+
+func (r *webhookRepositoryImpl) Delete(id int) error {
+	result := r.db.Delete(&models.Webhook{}, id)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return result.Error
+}
+
+func (r *webhookRepositoryImpl) RecordDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *webhookRepositoryImpl) ListDeliveries(webhookID int, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := r.db.Where("webhook_id = ?", webhookID).Order("id desc").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepositoryImpl) MarkSuccess(id int) error {
+	return r.db.Model(&models.Webhook{}).
+		Where("id = ? AND consecutive_failures > 0", id).
+		Update("consecutive_failures", 0).Error
+}
+
+func (r *webhookRepositoryImpl) MarkFailure(id int, disableAfter int) (bool, error) {
+	var webhook models.Webhook
+
+	// Increment and disable in a single statement, so concurrent failures can't race
+	result := r.db.Raw(`UPDATE webhooks
+		SET consecutive_failures = consecutive_failures + 1,
+			active = CASE WHEN consecutive_failures + 1 >= ? THEN FALSE ELSE active END,
+			disabled_at = CASE WHEN consecutive_failures + 1 >= ? AND active THEN ? ELSE disabled_at END,
+			updated_at = ?
+		WHERE id = ?
+		RETURNING *`, disableAfter, disableAfter, time.Now(), time.Now(), id).Scan(&webhook)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	// The webhook was deleted while its delivery was retried
+	if result.RowsAffected == 0 {
+		return false, ErrNotFound
+	}
+
+	return !webhook.Active, nil
+}
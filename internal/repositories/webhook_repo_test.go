@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eth-bridging/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRepository_UpdateMissing(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewWebhookRepository(gormDB)
+
+	// Updated in place, a deleted webhook isn't inserted again
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "webhooks" SET .*"active"=\$1.* WHERE id = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.Update(&models.Webhook{ID: 7, URL: "https://example.com/hook", Active: false})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_MarkFailureMissing(t *testing.T) {
+	mock, _, gormDB := Setup(t)
+	defer TearDown(t)
+
+	repo := NewWebhookRepository(gormDB)
+
+	mock.ExpectQuery(`UPDATE webhooks`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "active"}))
+
+	disabled, err := repo.MarkFailure(7, 5)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.False(t, disabled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -9,11 +9,48 @@ import (
 func SetupRouter(container *di.Container) *gin.Engine {
 	router := gin.Default()
 
-	eventHandler := handlers.NewBridgeEventHandler(container.EventService)
+	eventHandler := handlers.NewBridgeEventHandler(container.EventService, container.ArchiveService)
+	statsHandler := handlers.NewStatsHandler(container.StatsService)
+	streamHandler := handlers.NewStreamHandler(container.EventService, container.Hub)
+	webhookHandler := handlers.NewWebhookHandler(container.WebhookService)
+	alertHandler := handlers.NewAlertHandler(container.AlertService)
+	exportHandler := handlers.NewExportHandler(container.EventService, container.ArchiveService, container.Config)
+	archiveHandler := handlers.NewArchiveHandler(container.ArchiveService)
+	bridgeNameHandler := handlers.NewBridgeNameHandler(container.BridgeNameService)
+	rpcHandler := handlers.NewRPCHandler(container.Endpoints)
+	coverageHandler := handlers.NewCoverageHandler(container.Coverage)
+	transferHandler := handlers.NewTransferHandler(container.TransferService)
 
 	apiV1 := router.Group("/api/v1")
 	{
 		apiV1.GET("/events", eventHandler.GetEvents)
+		apiV1.GET("/events/stream", streamHandler.StreamEventsSSE)
+		apiV1.GET("/events/ws", streamHandler.StreamEventsWS)
+		apiV1.GET("/events/export", exportHandler.ExportEvents)
+		apiV1.GET("/stats/volume", statsHandler.GetVolume)
+		apiV1.GET("/archive/ranges", archiveHandler.ListRanges)
+		apiV1.GET("/bridges/unknown", bridgeNameHandler.ListUnknown)
+		apiV1.GET("/transfers", transferHandler.ListTransfers)
+		apiV1.GET("/transfers/stuck", transferHandler.ListStuck)
+		apiV1.GET("/transfers/metrics", transferHandler.GetMetrics)
+		apiV1.GET("/transfers/:tx_hash", transferHandler.GetTransfers)
+		apiV1.GET("/transfers/:tx_hash/history", transferHandler.GetHistory)
+		apiV1.POST("/transfers/:tx_hash/refund", transferHandler.Refund)
+
+		apiV1.POST("/webhooks", webhookHandler.CreateWebhook)
+		apiV1.GET("/webhooks", webhookHandler.ListWebhooks)
+		apiV1.GET("/webhooks/:id", webhookHandler.GetWebhook)
+		apiV1.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+		apiV1.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+		apiV1.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+
+		apiV1.GET("/alerts", alertHandler.ListAlerts)
+		apiV1.POST("/alerts/rules", alertHandler.CreateRule)
+		apiV1.GET("/alerts/rules", alertHandler.ListRules)
+		apiV1.DELETE("/alerts/rules/:id", alertHandler.DeleteRule)
+
+		apiV1.GET("/admin/rpc", rpcHandler.ListEndpoints)
+		apiV1.GET("/admin/coverage", coverageHandler.GetCoverage)
 	}
 
 	return router
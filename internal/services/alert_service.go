@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"strings"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidAlertRule is wrapped by every alert rule validation error
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+type AlertService interface {
+	// CreateRule validates and stores a new rule, it's picked up by the engine within its cache TTL
+	CreateRule(rule *models.AlertRule) error
+	ListRules() ([]models.AlertRule, error)
+	DeleteRule(id int) error
+	// ListAlerts returns alert history newest first, optionally of a single rule
+	ListAlerts(ruleID int, lastID uint, limit int) ([]models.Alert, error)
+}
+
+type alertService struct {
+	repo      repositories.AlertRepository
+	notifiers map[string]bool
+}
+
+// NewAlertService creates the service, rules may only use one of the provided notifiers
+func NewAlertService(repo repositories.AlertRepository, notifiers []string) AlertService {
+	known := make(map[string]bool)
+	for _, notifier := range notifiers {
+		known[notifier] = true
+	}
+
+	return &alertService{
+		repo:      repo,
+		notifiers: known,
+	}
+}
+
+func (s *alertService) CreateRule(rule *models.AlertRule) error {
+	if err := s.validateRule(rule); err != nil {
+		return err
+	}
+
+	rule.ID = 0
+	rule.Active = true
+
+	return s.repo.CreateRule(rule)
+}
+
+func (s *alertService) ListRules() ([]models.AlertRule, error) {
+	return s.repo.ListRules()
+}
+
+func (s *alertService) DeleteRule(id int) error {
+	return s.repo.DeleteRule(id)
+}
+
+func (s *alertService) ListAlerts(ruleID int, lastID uint, limit int) ([]models.Alert, error) {
+	return s.repo.ListAlerts(ruleID, lastID, limit)
+}
+
+func (s *alertService) validateRule(rule *models.AlertRule) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidAlertRule, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(rule.Name) == "" {
+		return invalid("name is required")
+	}
+	if strings.ContainsAny(rule.Name, "\r\n") {
+		return invalid("name must be a single line")
+	}
+
+	if !s.notifiers[rule.Notifier] {
+		return invalid("unknown notifier %q", rule.Notifier)
+	}
+
+	switch rule.Notifier {
+	case "webhook":
+		if err := validateWebhookURL(rule.Target); err != nil {
+			return invalid("target %s", err)
+		}
+	case "email":
+		if _, err := mail.ParseAddress(rule.Target); err != nil {
+			return invalid("target must be an email address")
+		}
+	}
+
+	if rule.MinAmount != nil && *rule.MinAmount != "" {
+		amount, ok := new(big.Int).SetString(*rule.MinAmount, 10)
+		if !ok || amount.Sign() < 0 {
+			return invalid("min_amount must be a non negative integer amount in WEI")
+		}
+		// Amounts of different tokens aren't comparable
+		if rule.Token == "" {
+			return invalid("min_amount requires a token")
+		}
+	}
+
+	for _, address := range rule.AddressList() {
+		if !common.IsHexAddress(address) {
+			return invalid("%s is not a valid address", address)
+		}
+	}
+
+	hasCondition := rule.Token != "" ||
+		(rule.MinAmount != nil && *rule.MinAmount != "") ||
+		len(rule.AddressList()) > 0 ||
+		rule.DestChainID != "" ||
+		rule.BridgeName != ""
+	if !hasCondition {
+		return invalid("at least one of token, min_amount, addresses, dest_chain_id or bridge_name is required")
+	}
+
+	return nil
+}
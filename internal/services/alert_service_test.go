@@ -0,0 +1,89 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAlertRepository struct {
+	mock.Mock
+}
+
+func (m *MockAlertRepository) CreateRule(rule *models.AlertRule) error {
+	return m.Called(rule).Error(0)
+}
+
+func (m *MockAlertRepository) ListRules() ([]models.AlertRule, error) {
+	args := m.Called()
+	return args.Get(0).([]models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertRepository) ListActiveRules() ([]models.AlertRule, error) {
+	args := m.Called()
+	return args.Get(0).([]models.AlertRule), args.Error(1)
+}
+
+func (m *MockAlertRepository) DeleteRule(id int) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockAlertRepository) CreateAlert(alert *models.Alert) error {
+	return m.Called(alert).Error(0)
+}
+
+func (m *MockAlertRepository) UpdateAlertNotification(alert *models.Alert) error {
+	return m.Called(alert).Error(0)
+}
+
+func (m *MockAlertRepository) ListAlerts(ruleID int, lastID uint, limit int) ([]models.Alert, error) {
+	args := m.Called(ruleID, lastID, limit)
+	return args.Get(0).([]models.Alert), args.Error(1)
+}
+
+func TestCreateRule(t *testing.T) {
+	mockRepo := new(MockAlertRepository)
+	mockRepo.On("CreateRule", mock.Anything).Return(nil)
+	service := services.NewAlertService(mockRepo, []string{"log", "webhook", "email"})
+
+	threshold := "1000000000000000000000"
+	rule := &models.AlertRule{
+		Name:      "whale-eth",
+		Token:     "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE",
+		MinAmount: &threshold,
+		Notifier:  "email",
+		Target:    "ops@example.com",
+	}
+	err := service.CreateRule(rule)
+
+	assert.NoError(t, err)
+	assert.True(t, rule.Active)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateRule_Invalid(t *testing.T) {
+	mockRepo := new(MockAlertRepository)
+	service := services.NewAlertService(mockRepo, []string{"log", "webhook"})
+
+	negative := "-1"
+	threshold := "1000"
+	invalidRules := []models.AlertRule{
+		{Name: "no conditions", Notifier: "log"},
+		{Name: "unknown notifier", DestChainID: "10", Notifier: "sms"},
+		{Name: "bad webhook target", DestChainID: "10", Notifier: "webhook", Target: "not a url"},
+		{Name: "negative amount", MinAmount: &negative, Notifier: "log"},
+		{Name: "bad address", Addresses: "0xnope", Notifier: "log"},
+		{Name: "amount without token", MinAmount: &threshold, Notifier: "log"},
+		{Name: "multi\r\nBcc: victim@example.com", DestChainID: "10", Notifier: "log"},
+	}
+
+	for _, rule := range invalidRules {
+		err := service.CreateRule(&rule)
+		assert.ErrorIs(t, err, services.ErrInvalidAlertRule, rule.Name)
+	}
+
+	mockRepo.AssertNotCalled(t, "CreateRule", mock.Anything)
+}
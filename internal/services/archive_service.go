@@ -0,0 +1,34 @@
+package services
+
+import (
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+type ArchiveService interface {
+	// ListRanges returns every range of events moved to object storage, oldest first
+	ListRanges() ([]models.ArchivedRange, error)
+	// ArchivedBefore returns the time before which events are no longer in the database,
+	// zero if nothing has been archived
+	ArchivedBefore() (time.Time, error)
+}
+
+type archiveService struct {
+	repo repositories.ArchiveRepository
+}
+
+func NewArchiveService(repo repositories.ArchiveRepository) ArchiveService {
+	return &archiveService{
+		repo: repo,
+	}
+}
+
+func (s *archiveService) ListRanges() ([]models.ArchivedRange, error) {
+	return s.repo.ListRanges()
+}
+
+func (s *archiveService) ArchivedBefore() (time.Time, error) {
+	return s.repo.ArchivedBefore()
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+type BridgeNameService interface {
+	// ListUnknown returns bridge names that couldn't be decoded, so they can be added to `BRIDGE_NAMES`
+	ListUnknown() ([]models.UnknownBridgeName, error)
+}
+
+type bridgeNameService struct {
+	repo repositories.BridgeNameRepository
+}
+
+func NewBridgeNameService(repo repositories.BridgeNameRepository) BridgeNameService {
+	return &bridgeNameService{
+		repo: repo,
+	}
+}
+
+func (s *bridgeNameService) ListUnknown() ([]models.UnknownBridgeName, error) {
+	return s.repo.ListUnknown()
+}
@@ -2,19 +2,35 @@ package services
 
 import (
 	"context"
+	"errors"
 	"log"
 
+	"github.com/eth-bridging/config"
 	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/pricing"
 	"github.com/eth-bridging/internal/producer"
 	"github.com/eth-bridging/internal/repositories"
+	"github.com/eth-bridging/pkg/decimal"
 	ethereum "github.com/eth-bridging/pkg/go-eth"
 )
 
 type BridgeEventService interface {
-	// SaveEvent saves provided event to db
+	// SaveEvent values the event in USD, if it isn't already, and saves it to db
 	SaveEvent(event *models.BridgeEvent) error
-	// GetAllEvents fetches all events in paginated manner using lastID and limit
-	GetAllEvents(lastID uint, limit int, currency string) ([]models.BridgeEvent, error)
+	// GetAllEvents fetches all events matching the filter in paginated manner using lastID and limit,
+	// amounts are formatted as in FormatAmounts
+	GetAllEvents(filter models.EventFilter, lastID uint, limit int, currency, quote string) ([]models.BridgeEvent, error)
+	// GetEventsAfter fetches events matching the filter with id greater than afterID, oldest first,
+	// amounts are formatted as in FormatAmounts
+	GetEventsAfter(filter models.EventFilter, afterID uint, limit int, currency, quote string) ([]models.BridgeEvent, error)
+	// FormatAmounts converts the WEI amount of the event to the currency and fills in
+	// AmountRaw and, for known tokens, AmountFormatted using the token's decimals.
+	// USDValue is only kept when quote is `USD`
+	FormatAmounts(event *models.BridgeEvent, currency, quote string)
+	// StreamEvents calls fn for every event matching the filter, oldest first, amounts in WEI
+	//
+	//	Events are read through a database cursor, so memory use doesn't grow with the result
+	StreamEvents(ctx context.Context, filter models.EventFilter, fn func(models.BridgeEvent) error) error
 	// ProcessIncomingBridgeEvents listens for bridging events and saves them to the database
 	//
 	//	It is a blocking method, so ideally is should be called with `go` keyword
@@ -24,6 +40,8 @@ type BridgeEventService interface {
 type bridgeEventService struct {
 	repo      repositories.BridgeEventRepository
 	ethClient ethereum.EthereumClientInterface
+	prices    pricing.PriceProvider
+	cfg       *config.Config
 }
 
 // ProcessIncomingBridgeEvents listens for bridging events and saves them to the database
@@ -36,17 +54,116 @@ func (s *bridgeEventService) ProcessIncomingBridgeEvents(streamProducer producer
 	}
 }
 
-func NewBridgeEventService(repo repositories.BridgeEventRepository, ethClient ethereum.EthereumClientInterface) BridgeEventService {
+// NewBridgeEventService creates the event service, events are saved without a USD value when prices is nil
+func NewBridgeEventService(repo repositories.BridgeEventRepository, ethClient ethereum.EthereumClientInterface, prices pricing.PriceProvider, cfg *config.Config) BridgeEventService {
 	return &bridgeEventService{
 		repo:      repo,
 		ethClient: ethClient,
+		prices:    prices,
+		cfg:       cfg,
 	}
 }
 
 func (s *bridgeEventService) SaveEvent(event *models.BridgeEvent) error {
+	if event.USDValue == nil {
+		event.USDValue = s.usdValue(event)
+	}
 	return s.repo.Save(event)
 }
 
-func (s *bridgeEventService) GetAllEvents(lastID uint, limit int, currency string) ([]models.BridgeEvent, error) {
-	return s.repo.GetAll(lastID, limit, currency)
+// usdValue values the event at the time of its block, nil if the token or its price is unknown.
+// Events published without a block timestamp are valued at ingestion time instead,
+// which is only seconds off for live events
+func (s *bridgeEventService) usdValue(event *models.BridgeEvent) *string {
+	if s.prices == nil {
+		return nil
+	}
+
+	token, ok := s.cfg.GetTokenDetails(event.Token)
+	if !ok {
+		return nil
+	}
+
+	at := event.Timestamp
+	if event.BlockTimestamp != nil {
+		at = *event.BlockTimestamp
+	}
+
+	price, err := s.prices.PriceAt(token.Symbol, at)
+	if err != nil {
+		// A missing price is expected, only lookup failures are worth logging
+		if !errors.Is(err, pricing.ErrNoPrice) {
+			log.Printf("Error looking up %s price at %s: %v", token.Symbol, at, err)
+		}
+		return nil
+	}
+
+	value, err := pricing.USDValue(event.Amount, token.Decimals, price)
+	if err != nil {
+		log.Printf("Error valuing amount %q of %s: %v", event.Amount, token.Symbol, err)
+		return nil
+	}
+
+	return &value
+}
+
+func (s *bridgeEventService) GetAllEvents(filter models.EventFilter, lastID uint, limit int, currency, quote string) ([]models.BridgeEvent, error) {
+	events, err := s.repo.GetAll(filter, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		s.FormatAmounts(&events[i], currency, quote)
+	}
+	return events, nil
+}
+
+func (s *bridgeEventService) StreamEvents(ctx context.Context, filter models.EventFilter, fn func(models.BridgeEvent) error) error {
+	return s.repo.StreamEvents(ctx, filter, fn)
+}
+
+func (s *bridgeEventService) GetEventsAfter(filter models.EventFilter, afterID uint, limit int, currency, quote string) ([]models.BridgeEvent, error) {
+	events, err := s.repo.GetAfter(filter, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		s.FormatAmounts(&events[i], currency, quote)
+	}
+	return events, nil
+}
+
+func (s *bridgeEventService) FormatAmounts(event *models.BridgeEvent, currency, quote string) {
+	event.AmountRaw = event.Amount
+	if quote != models.QuoteUSD {
+		event.USDValue = nil
+	} else if event.USDValue != nil {
+		trimmed := decimal.Trim(*event.USDValue)
+		event.USDValue = &trimmed
+	}
+
+	// Events other than SocketBridge have no amount
+	if event.Amount == "" {
+		return
+	}
+
+	converted, currConf, err := s.cfg.ConvertAmount(event.Amount, currency)
+	if err != nil {
+		// Amount column is NUMERIC(78, 0), so this only happens for hand crafted events
+		log.Printf("Error converting amount %q of event %d: %v", event.Amount, event.ID, err)
+		return
+	}
+	event.Amount = converted
+	event.TxnCurrency = currConf.Currency
+
+	if token, ok := s.cfg.GetTokenDetails(event.Token); ok {
+		formatted, err := decimal.FormatString(event.AmountRaw, token.Decimals)
+		if err != nil {
+			log.Printf("Error formatting amount %q of event %d: %v", event.AmountRaw, event.ID, err)
+			return
+		}
+		event.AmountFormatted = &formatted
+	}
 }
@@ -3,8 +3,11 @@ package services_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/eth-bridging/config"
 	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/pricing"
 	"github.com/eth-bridging/internal/producer"
 	"github.com/eth-bridging/internal/services"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +23,23 @@ func (m *MockBridgeEventRepository) Save(event *models.BridgeEvent) error {
 	return args.Error(0)
 }
 
-func (m *MockBridgeEventRepository) GetAll(lastID uint, limit int, currency string) ([]models.BridgeEvent, error) {
-	args := m.Called(lastID, limit, currency)
+func (m *MockBridgeEventRepository) GetAll(filter models.EventFilter, lastID uint, limit int) ([]models.BridgeEvent, error) {
+	args := m.Called(filter, lastID, limit)
+	return args.Get(0).([]models.BridgeEvent), args.Error(1)
+}
+
+func (m *MockBridgeEventRepository) GetAfter(filter models.EventFilter, afterID uint, limit int) ([]models.BridgeEvent, error) {
+	args := m.Called(filter, afterID, limit)
+	return args.Get(0).([]models.BridgeEvent), args.Error(1)
+}
+
+func (m *MockBridgeEventRepository) StreamEvents(ctx context.Context, filter models.EventFilter, fn func(models.BridgeEvent) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockBridgeEventRepository) ListByBlockRange(from, to uint64) ([]models.BridgeEvent, error) {
+	args := m.Called(from, to)
 	return args.Get(0).([]models.BridgeEvent), args.Error(1)
 }
 
@@ -51,7 +69,7 @@ func (m *MockRedisProducer) Stop() {
 func TestSaveEvent(t *testing.T) {
 	mockRepo := new(MockBridgeEventRepository)
 	mockRepo.On("Save", mock.Anything).Return(nil)
-	service := services.NewBridgeEventService(mockRepo, nil)
+	service := services.NewBridgeEventService(mockRepo, nil, nil, config.LoadConfig())
 
 	err := service.SaveEvent(&models.BridgeEvent{})
 
@@ -59,25 +77,116 @@ func TestSaveEvent(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestSaveEvent_ValuesInUSD(t *testing.T) {
+	mockRepo := new(MockBridgeEventRepository)
+	mockRepo.On("Save", mock.Anything).Return(nil)
+	blockTime := time.Date(2024, 12, 14, 14, 0, 0, 0, time.UTC)
+	prices := pricing.NewStaticPriceProvider([]models.TokenPrice{
+		{Symbol: "USDC", Timestamp: blockTime.Add(-time.Hour), USDPrice: "0.9998"},
+		// Ingested after this price, but the block is older
+		{Symbol: "USDC", Timestamp: blockTime.Add(time.Minute), USDPrice: "1.0001"},
+	}, 24*time.Hour)
+	service := services.NewBridgeEventService(mockRepo, nil, prices, config.LoadConfig())
+
+	event := models.BridgeEvent{
+		Token:          "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Amount:         "1372483935",
+		Timestamp:      blockTime.Add(2 * time.Minute),
+		BlockTimestamp: &blockTime,
+	}
+	unknownToken := models.BridgeEvent{Token: "0x0000000000000000000000000000000000000001", Amount: "1", Timestamp: blockTime}
+
+	assert.NoError(t, service.SaveEvent(&event))
+	assert.NoError(t, service.SaveEvent(&unknownToken))
+
+	// 1372.483935 USDC at 0.9998
+	assert.Equal(t, "1372.209438", *event.USDValue)
+	assert.Nil(t, unknownToken.USDValue)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestGetAllEvents(t *testing.T) {
 	mockRepo := new(MockBridgeEventRepository)
-	mockRepo.On("GetAll", uint(0), 10, "ETH").Return([]models.BridgeEvent{}, nil)
-	service := services.NewBridgeEventService(mockRepo, nil) // Pass nil for EthereumClient as it's not needed here
+	mockRepo.On("GetAll", models.EventFilter{}, uint(0), 10).Return([]models.BridgeEvent{}, nil)
+	service := services.NewBridgeEventService(mockRepo, nil, nil, config.LoadConfig()) // Pass nil for EthereumClient as it's not needed here
 
-	events, err := service.GetAllEvents(0, 10, "ETH")
+	events, err := service.GetAllEvents(models.EventFilter{}, 0, 10, "ETH", "")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, events)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetAllEvents_FormatsAmounts(t *testing.T) {
+	mockRepo := new(MockBridgeEventRepository)
+	mockRepo.On("GetAll", models.EventFilter{}, uint(0), 10).Return([]models.BridgeEvent{
+		{ID: 2, Token: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Amount: "1372483935"},
+		{ID: 1, Token: "0x0000000000000000000000000000000000000001", Amount: "3482483968499194"},
+	}, nil)
+	service := services.NewBridgeEventService(mockRepo, nil, nil, config.LoadConfig())
+
+	events, err := service.GetAllEvents(models.EventFilter{}, 0, 10, "ETH", "")
+
+	assert.NoError(t, err)
+	// Amount is converted exactly to ETH, trailing zeros trimmed
+	assert.Equal(t, "0.000000001372483935", events[0].Amount)
+	assert.Equal(t, "ETH", events[0].TxnCurrency)
+	assert.Equal(t, "1372483935", events[0].AmountRaw)
+	// USDC has 6 decimals
+	assert.Equal(t, "1372.483935", *events[0].AmountFormatted)
+
+	// Unknown token, nothing to format with
+	assert.Equal(t, "3482483968499194", events[1].AmountRaw)
+	assert.Nil(t, events[1].AmountFormatted)
+}
+
+func TestGetEventsAfter_FormatsLikeLiveEvents(t *testing.T) {
+	raw := models.BridgeEvent{ID: 7, Token: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Amount: "1372483930"}
+	mockRepo := new(MockBridgeEventRepository)
+	mockRepo.On("GetAfter", models.EventFilter{}, uint(6), 10).Return([]models.BridgeEvent{raw}, nil)
+	service := services.NewBridgeEventService(mockRepo, nil, nil, config.LoadConfig())
+
+	replayed, err := service.GetEventsAfter(models.EventFilter{}, 6, 10, "ETH", "")
+	// Live events are formatted by the stream handler as they arrive
+	live := raw
+	service.FormatAmounts(&live, "ETH", "")
+
+	// Streams switch from replayed to live events, both must read the same
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BridgeEvent{live}, replayed)
+	assert.Equal(t, "0.00000000137248393", replayed[0].Amount)
+}
+
+func TestFormatAmounts_DefaultCurrency(t *testing.T) {
+	service := services.NewBridgeEventService(nil, nil, nil, config.LoadConfig())
+
+	event := models.BridgeEvent{Token: "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE", Amount: "3482483968499194"}
+	service.FormatAmounts(&event, "", "")
+
+	// WEI is the raw amount as is
+	assert.Equal(t, "3482483968499194", event.Amount)
+	assert.Equal(t, "WEI", event.TxnCurrency)
+	assert.Equal(t, "0.003482483968499194", *event.AmountFormatted)
+}
+
+func TestFormatAmounts_InvalidAmount(t *testing.T) {
+	service := services.NewBridgeEventService(nil, nil, nil, config.LoadConfig())
+
+	event := models.BridgeEvent{Token: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Amount: "1372.48"}
+	service.FormatAmounts(&event, "ETH", "")
+
+	// Left out rather than formatted as an empty amount
+	assert.Equal(t, "1372.48", event.AmountRaw)
+	assert.Nil(t, event.AmountFormatted)
+}
+
 func TestProcessIncomingBridgeEvents(t *testing.T) {
 	mockClient := new(MockEthereumClient)
 	mockProducer := new(MockRedisProducer)
 
 	mockClient.On("StartBridgingEventPublisher", mock.Anything, mock.Anything).Return(nil)
 
-	service := services.NewBridgeEventService(nil, mockClient)
+	service := services.NewBridgeEventService(nil, mockClient, nil, config.LoadConfig())
 
 	service.ProcessIncomingBridgeEvents(mockProducer)
 
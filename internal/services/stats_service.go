@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// ErrInvalidInterval is returned when an unsupported bucket interval is requested
+var ErrInvalidInterval = errors.New("interval must be one of hour, day or week")
+
+// ErrInvalidTimeRange is returned when the requested range is empty or reversed
+var ErrInvalidTimeRange = errors.New("from must be before to")
+
+type BridgeStatsService interface {
+	// GetVolume returns bridged volume grouped by token, destination chain and bridge name
+	// for every `interval` bucket within the filter's time range, quoted in USD as well if quote is `USD`
+	GetVolume(interval string, filter models.EventFilter, currency, quote string) ([]models.VolumeStat, error)
+}
+
+type bridgeStatsService struct {
+	repo repositories.BridgeStatsRepository
+}
+
+func NewBridgeStatsService(repo repositories.BridgeStatsRepository) BridgeStatsService {
+	return &bridgeStatsService{
+		repo: repo,
+	}
+}
+
+func (s *bridgeStatsService) GetVolume(interval string, filter models.EventFilter, currency, quote string) ([]models.VolumeStat, error) {
+	switch interval {
+	case "hour", "day", "week":
+	default:
+		return nil, ErrInvalidInterval
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	return s.repo.GetVolume(interval, filter, currency, quote)
+}
@@ -0,0 +1,47 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBridgeStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockBridgeStatsRepository) GetVolume(interval string, filter models.EventFilter, currency, quote string) ([]models.VolumeStat, error) {
+	args := m.Called(interval, filter, currency, quote)
+	return args.Get(0).([]models.VolumeStat), args.Error(1)
+}
+
+func TestGetVolume(t *testing.T) {
+	mockRepo := new(MockBridgeStatsRepository)
+	filter := models.EventFilter{From: time.Now().Add(-time.Hour), To: time.Now()}
+	mockRepo.On("GetVolume", "hour", filter, "ETH", "USD").Return([]models.VolumeStat{{EventCount: 1}}, nil)
+	service := services.NewBridgeStatsService(mockRepo)
+
+	stats, err := service.GetVolume("hour", filter, "ETH", "USD")
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetVolume_InvalidInput(t *testing.T) {
+	mockRepo := new(MockBridgeStatsRepository)
+	service := services.NewBridgeStatsService(mockRepo)
+
+	_, err := service.GetVolume("month", models.EventFilter{}, "ETH", "")
+	assert.ErrorIs(t, err, services.ErrInvalidInterval)
+
+	now := time.Now()
+	_, err = service.GetVolume("day", models.EventFilter{From: now, To: now.Add(-time.Hour)}, "ETH", "")
+	assert.ErrorIs(t, err, services.ErrInvalidTimeRange)
+
+	mockRepo.AssertNotCalled(t, "GetVolume", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// ErrInvalidTransferStatus is returned when transfers are filtered by an unknown status
+var ErrInvalidTransferStatus = errors.New("status must be pending, completed or stuck")
+
+// ErrTransferNotFound is returned when a transfer to move doesn't exist
+var ErrTransferNotFound = errors.New("transfer not found")
+
+type TransferService interface {
+	// ListTransfers returns transfers of tracked bridges newest first, using keyset pagination on the source event id
+	ListTransfers(filter models.TransferFilter, lastID uint, limit int) ([]models.Transfer, error)
+	// GetTransfers returns the transfers of a source transaction, tracked or not
+	GetTransfers(txHash string) ([]models.Transfer, error)
+	// ListStuck returns transfers which breached their SLA and aren't completed yet, newest first,
+	// optionally of a single bridge or destination chain
+	ListStuck(bridgeName, destChainID string, lastID uint, limit int) ([]models.SLABreach, error)
+	// CompletionMetrics returns completion latency percentiles of every bridge over the window
+	CompletionMetrics(window time.Duration) ([]models.CompletionMetrics, error)
+	// GetHistory returns the transfers of a source transaction along with every state they went through
+	GetHistory(txHash string) ([]models.TransferHistory, error)
+	// Refund moves the transfer of the source event at the log index to refunded, returns
+	// models.ErrInvalidTransition when it isn't confirmed or finalized
+	Refund(txHash string, logIndex uint, reason string) (*models.TransferHistory, error)
+}
+
+type transferService struct {
+	repo          repositories.TransferRepository
+	slaRepo       repositories.SLARepository
+	lifecycleRepo repositories.LifecycleRepository
+	tracked       []models.FillSpec
+	now           func() time.Time
+}
+
+// NewTransferService creates the service, transfers complete on the destination chains of the tracked bridges
+func NewTransferService(repo repositories.TransferRepository, slaRepo repositories.SLARepository, lifecycleRepo repositories.LifecycleRepository, tracked []models.FillSpec) TransferService {
+	return &transferService{
+		repo:          repo,
+		slaRepo:       slaRepo,
+		lifecycleRepo: lifecycleRepo,
+		tracked:       tracked,
+		now:           time.Now,
+	}
+}
+
+func (s *transferService) ListTransfers(filter models.TransferFilter, lastID uint, limit int) ([]models.Transfer, error) {
+	switch filter.Status {
+	case "", models.TransferPending, models.TransferCompleted, models.TransferStuck:
+	default:
+		return nil, ErrInvalidTransferStatus
+	}
+
+	filter.Tracked = s.tracked
+	filter.Now = s.now()
+
+	events, err := s.repo.ListTransfers(filter, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.transfers(events, filter.Now)
+}
+
+func (s *transferService) GetTransfers(txHash string) ([]models.Transfer, error) {
+	events, err := s.repo.ListTransfersByTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return s.transfers(events, s.now())
+}
+
+func (s *transferService) ListStuck(bridgeName, destChainID string, lastID uint, limit int) ([]models.SLABreach, error) {
+	breaches, err := s.slaRepo.ListOpenBreaches(bridgeName, destChainID, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	for i := range breaches {
+		deadline := breaches[i].SourceTimestamp.Add(time.Duration(breaches[i].SLASeconds) * time.Second)
+		breaches[i].OverdueSeconds = now.Sub(deadline).Seconds()
+	}
+	return breaches, nil
+}
+
+func (s *transferService) CompletionMetrics(window time.Duration) ([]models.CompletionMetrics, error) {
+	return s.slaRepo.CompletionMetrics(s.now().Add(-window))
+}
+
+func (s *transferService) GetHistory(txHash string) ([]models.TransferHistory, error) {
+	records, err := s.lifecycleRepo.ListByTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return s.histories(records)
+}
+
+func (s *transferService) Refund(txHash string, logIndex uint, reason string) (*models.TransferHistory, error) {
+	records, err := s.lifecycleRepo.ListByTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.LogIndex != logIndex {
+			continue
+		}
+
+		transition, err := record.Transition(models.StateRefunded, reason, s.now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		moved, err := s.lifecycleRepo.Transition(record, transition)
+		if err != nil {
+			return nil, err
+		}
+		// Moved by the lifecycle in the meantime
+		if !moved {
+			return nil, models.ErrInvalidTransition
+		}
+
+		record.State = models.StateRefunded
+		histories, err := s.histories([]models.TransferRecord{record})
+		if err != nil {
+			return nil, err
+		}
+		return &histories[0], nil
+	}
+
+	return nil, ErrTransferNotFound
+}
+
+// histories adds the transitions of every transfer to it
+func (s *transferService) histories(records []models.TransferRecord) ([]models.TransferHistory, error) {
+	ids := make([]int, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+
+	transitions, err := s.lifecycleRepo.ListTransitions(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]models.TransferHistory, 0, len(records))
+	for _, record := range records {
+		history := models.TransferHistory{TransferRecord: record, Transitions: []models.TransferTransition{}}
+		for _, transition := range transitions {
+			if transition.TransferID == record.ID {
+				history.Transitions = append(history.Transitions, transition)
+			}
+		}
+		histories = append(histories, history)
+	}
+
+	return histories, nil
+}
+
+// transfers pairs the source events with their fills and evaluates their status at now
+func (s *transferService) transfers(events []models.BridgeEvent, now time.Time) ([]models.Transfer, error) {
+	ids := make([]int, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	fills, err := s.repo.FillsOf(ids)
+	if err != nil {
+		return nil, err
+	}
+	fillsBySource := make(map[int]models.BridgeFill, len(fills))
+	for _, fill := range fills {
+		fillsBySource[*fill.SourceEventID] = fill
+	}
+
+	transfers := make([]models.Transfer, 0, len(events))
+	for _, event := range events {
+		transfer := models.Transfer{Source: event, Status: models.TransferUntracked}
+		if fill, ok := fillsBySource[event.ID]; ok {
+			transfer.Fill = &fill
+			transfer.Status = models.TransferCompleted
+			if fill.LatencyMs != nil {
+				latency := float64(*fill.LatencyMs) / 1000
+				transfer.CompletionLatencySeconds = &latency
+			}
+		}
+
+		for _, spec := range s.tracked {
+			if spec.Tracks(event.BridgeName, event.DestChainID) {
+				transfer.Status = spec.TransferStatus(event, transfer.Fill, now)
+			}
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
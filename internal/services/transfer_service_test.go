@@ -0,0 +1,148 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/eth-bridging/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransferRepository struct {
+	repositories.TransferRepository
+	mock.Mock
+}
+
+func (m *MockTransferRepository) ListTransfersByTx(txHash string) ([]models.BridgeEvent, error) {
+	args := m.Called(txHash)
+	return args.Get(0).([]models.BridgeEvent), args.Error(1)
+}
+
+func (m *MockTransferRepository) FillsOf(sourceEventIDs []int) ([]models.BridgeFill, error) {
+	args := m.Called(sourceEventIDs)
+	return args.Get(0).([]models.BridgeFill), args.Error(1)
+}
+
+func TestGetTransfers(t *testing.T) {
+	mockRepo := new(MockTransferRepository)
+	tracked := []models.FillSpec{{BridgeName: "hop", WindowMinutes: 60, ChainIDs: []string{"10"}}}
+
+	now := time.Now()
+	events := []models.BridgeEvent{
+		{ID: 1, BridgeName: "hop", DestChainID: "10", Timestamp: now.Add(-2 * time.Hour)},
+		{ID: 2, BridgeName: "hop", DestChainID: "10", Timestamp: now.Add(-2 * time.Hour)},
+		{ID: 3, BridgeName: "hop", DestChainID: "10", Timestamp: now.Add(-time.Minute)},
+		{ID: 4, BridgeName: "hop", DestChainID: "137", Timestamp: now.Add(-time.Minute)},
+	}
+	sourceID, latencyMs := 1, int64(90500)
+	fills := []models.BridgeFill{{ID: 9, SourceEventID: &sourceID, LatencyMs: &latencyMs}}
+
+	mockRepo.On("ListTransfersByTx", "0xabc").Return(events, nil)
+	mockRepo.On("FillsOf", []int{1, 2, 3, 4}).Return(fills, nil)
+	service := services.NewTransferService(mockRepo, nil, nil, tracked)
+
+	transfers, err := service.GetTransfers("0xabc")
+
+	assert.NoError(t, err)
+	if assert.Len(t, transfers, 4) {
+		assert.Equal(t, models.TransferCompleted, transfers[0].Status)
+		assert.Equal(t, 9, transfers[0].Fill.ID)
+		assert.Equal(t, 90.5, *transfers[0].CompletionLatencySeconds)
+		assert.Equal(t, models.TransferStuck, transfers[1].Status)
+		assert.Equal(t, models.TransferPending, transfers[2].Status)
+		// Fills aren't ingested from the chain
+		assert.Equal(t, models.TransferUntracked, transfers[3].Status)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListTransfers_InvalidStatus(t *testing.T) {
+	mockRepo := new(MockTransferRepository)
+	service := services.NewTransferService(mockRepo, nil, nil, nil)
+
+	_, err := service.ListTransfers(models.TransferFilter{Status: "lost"}, 0, 10)
+
+	assert.ErrorIs(t, err, services.ErrInvalidTransferStatus)
+}
+
+type MockSLARepository struct {
+	repositories.SLARepository
+	mock.Mock
+}
+
+func (m *MockSLARepository) ListOpenBreaches(bridgeName, destChainID string, lastID uint, limit int) ([]models.SLABreach, error) {
+	args := m.Called(bridgeName, destChainID, lastID, limit)
+	return args.Get(0).([]models.SLABreach), args.Error(1)
+}
+
+func TestListStuck(t *testing.T) {
+	mockSLARepo := new(MockSLARepository)
+	sourceTime := time.Now().Add(-time.Hour)
+	mockSLARepo.On("ListOpenBreaches", "hop", "", uint(0), 10).Return([]models.SLABreach{
+		{ID: 3, BridgeName: "hop", SLASeconds: 1200, SourceTimestamp: sourceTime},
+	}, nil)
+	service := services.NewTransferService(new(MockTransferRepository), mockSLARepo, nil, nil)
+
+	breaches, err := service.ListStuck("hop", "", 0, 10)
+
+	assert.NoError(t, err)
+	if assert.Len(t, breaches, 1) {
+		// 20 minute SLA of a transfer made an hour ago
+		assert.InDelta(t, 2400, breaches[0].OverdueSeconds, 5)
+	}
+	mockSLARepo.AssertExpectations(t)
+}
+
+type MockLifecycleRepository struct {
+	repositories.LifecycleRepository
+	mock.Mock
+}
+
+func (m *MockLifecycleRepository) ListByTx(txHash string) ([]models.TransferRecord, error) {
+	args := m.Called(txHash)
+	return args.Get(0).([]models.TransferRecord), args.Error(1)
+}
+
+func (m *MockLifecycleRepository) Transition(transfer models.TransferRecord, transition models.TransferTransition) (bool, error) {
+	args := m.Called(transfer, transition)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLifecycleRepository) ListTransitions(transferIDs []int) ([]models.TransferTransition, error) {
+	args := m.Called(transferIDs)
+	return args.Get(0).([]models.TransferTransition), args.Error(1)
+}
+
+func TestRefund(t *testing.T) {
+	mockLifecycleRepo := new(MockLifecycleRepository)
+	records := []models.TransferRecord{
+		{ID: 4, TransactionHash: "0xabc", LogIndex: 2, State: models.StateCompleted},
+		{ID: 5, TransactionHash: "0xabc", LogIndex: 5, State: models.StateFinalized},
+	}
+	mockLifecycleRepo.On("ListByTx", "0xabc").Return(records, nil)
+	mockLifecycleRepo.On("Transition", records[1], mock.MatchedBy(func(transition models.TransferTransition) bool {
+		return transition.FromState == models.StateFinalized && transition.ToState == models.StateRefunded
+	})).Return(true, nil)
+	mockLifecycleRepo.On("ListTransitions", []int{5}).Return([]models.TransferTransition{
+		{TransferID: 5, ToState: models.StateObserved},
+		{TransferID: 5, FromState: models.StateObserved, ToState: models.StateConfirmed},
+	}, nil)
+	service := services.NewTransferService(new(MockTransferRepository), nil, mockLifecycleRepo, nil)
+
+	history, err := service.Refund("0xabc", 5, "relayer refunded the deposit")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StateRefunded, history.State)
+	assert.Len(t, history.Transitions, 2)
+
+	// Completed transfers can't be refunded
+	_, err = service.Refund("0xabc", 2, "relayer refunded the deposit")
+	assert.ErrorIs(t, err, models.ErrInvalidTransition)
+
+	_, err = service.Refund("0xabc", 9, "relayer refunded the deposit")
+	assert.ErrorIs(t, err, services.ErrTransferNotFound)
+	mockLifecycleRepo.AssertExpectations(t)
+}
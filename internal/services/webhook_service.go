@@ -0,0 +1,133 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// ErrInvalidWebhookURL is returned when a webhook URL isn't an absolute http(s) URL
+var ErrInvalidWebhookURL = errors.New("url must be an absolute http or https url")
+
+// webhookDeliveriesLimit is the number of most recent deliveries returned per webhook
+const webhookDeliveriesLimit = 100
+
+type WebhookService interface {
+	// CreateWebhook validates and stores a new subscription, a secret is generated if not provided
+	CreateWebhook(webhook *models.Webhook) error
+	GetWebhook(id int) (*models.Webhook, error)
+	ListWebhooks() ([]models.Webhook, error)
+	// UpdateWebhook replaces URL, secret, filter and active flag of an existing webhook,
+	// re-activating a disabled webhook resets its failure counter
+	UpdateWebhook(id int, update models.Webhook) (*models.Webhook, error)
+	DeleteWebhook(id int) error
+	// ListDeliveries returns the most recent delivery attempts of a webhook
+	ListDeliveries(id int) ([]models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repo repositories.WebhookRepository
+}
+
+func NewWebhookService(repo repositories.WebhookRepository) WebhookService {
+	return &webhookService{
+		repo: repo,
+	}
+}
+
+func (s *webhookService) CreateWebhook(webhook *models.Webhook) error {
+	if err := validateWebhookURL(webhook.URL); err != nil {
+		return err
+	}
+
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+
+	webhook.ID = 0
+	webhook.Active = true
+	webhook.ConsecutiveFailures = 0
+	webhook.DisabledAt = nil
+
+	return s.repo.Create(webhook)
+}
+
+func (s *webhookService) GetWebhook(id int) (*models.Webhook, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *webhookService) ListWebhooks() ([]models.Webhook, error) {
+	return s.repo.List()
+}
+
+func (s *webhookService) UpdateWebhook(id int, update models.Webhook) (*models.Webhook, error) {
+	if err := validateWebhookURL(update.URL); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Active && !webhook.Active {
+		webhook.ConsecutiveFailures = 0
+		webhook.DisabledAt = nil
+	}
+
+	webhook.URL = update.URL
+	webhook.Token = update.Token
+	webhook.DestChainID = update.DestChainID
+	webhook.BridgeName = update.BridgeName
+	webhook.Active = update.Active
+	// Secret is only rotated when a new one is provided
+	if update.Secret != "" {
+		webhook.Secret = update.Secret
+	}
+
+	if err := s.repo.Update(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookService) DeleteWebhook(id int) error {
+	return s.repo.Delete(id)
+}
+
+func (s *webhookService) ListDeliveries(id int) ([]models.WebhookDelivery, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListDeliveries(id, webhookDeliveriesLimit)
+}
+
+// validateWebhookURL ensures deliveries can actually be POSTed to the URL
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	return nil
+}
+
+// generateWebhookSecret returns a random 32 byte hex encoded secret
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,285 @@
+package transfers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	ethereum "github.com/eth-bridging/pkg/go-eth"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SourceChain is the part of the source chain's ethereum.EndpointPool transfers are followed with
+type SourceChain interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// LifecycleOptions tunes the Lifecycle
+type LifecycleOptions struct {
+	// Interval is the wait between passes
+	Interval time.Duration
+	// Confirmations is the number of blocks built on top of its block a transfer is confirmed at
+	Confirmations uint64
+	// Lookback is how long after being observed transfers are followed, older ones are left in their state
+	Lookback time.Duration
+	// BatchSize is the number of transfers read at once
+	BatchSize int
+}
+
+// DefaultLifecycleOptions are sensible defaults for production use
+var DefaultLifecycleOptions = LifecycleOptions{
+	Interval:      30 * time.Second,
+	Confirmations: 12,
+	Lookback:      24 * time.Hour,
+	BatchSize:     500,
+}
+
+// followedStates are the states transfers move on from without a manual transition
+var followedStates = []models.TransferState{
+	models.StateObserved, models.StateConfirmed, models.StateFinalized, models.StateReorged,
+}
+
+// chainState is what's known of the source chain and the fills of a batch of transfers during a pass
+type chainState struct {
+	head      uint64
+	finalized uint64
+	// included are the blocks transactions are included in, transactions checked but
+	// missing from it aren't in the chain
+	included map[string]uint64
+	// fills by source event id
+	fills map[int]models.BridgeFill
+}
+
+// Lifecycle moves transfers through their states, every transition is recorded with its reason.
+//
+// Transfers are observed once their SocketBridge event is saved. They're confirmed once their block
+// has Confirmations blocks on top and finalized once the chain finalizes it, their transaction is looked
+// up on both occasions and they're reorged if it's gone. Confirmed transfers complete once their fill is
+// matched on the destination chain
+type Lifecycle struct {
+	repo      repositories.LifecycleRepository
+	transfers repositories.TransferRepository
+	chain     SourceChain
+	opts      LifecycleOptions
+	now       func() time.Time
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewLifecycle(repo repositories.LifecycleRepository, transfers repositories.TransferRepository, chain SourceChain, opts LifecycleOptions) *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Lifecycle{
+		repo:      repo,
+		transfers: transfers,
+		chain:     chain,
+		opts:      opts,
+		now:       time.Now,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Start advances transfers every Interval
+func (l *Lifecycle) Start() {
+	l.wg.Add(1)
+	go l.run()
+}
+
+// Stop waits for an in flight pass to finish
+func (l *Lifecycle) Stop() {
+	l.cancel()
+	l.wg.Wait()
+}
+
+// Advance observes newly saved transfers and moves every followed transfer as far as it goes,
+// returns the number of transitions recorded
+func (l *Lifecycle) Advance() (int, error) {
+	now := l.now().UTC()
+	since := now.Add(-l.opts.Lookback)
+
+	for l.ctx.Err() == nil {
+		observed, err := l.repo.ObserveTransfers(since, now, l.opts.BatchSize)
+		if err != nil {
+			return 0, err
+		}
+		if observed < int64(l.opts.BatchSize) {
+			break
+		}
+	}
+
+	head, err := l.chain.BlockNumber(l.ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the head: %w", err)
+	}
+	// Chains without finality leave transfers confirmed until they complete
+	var finalized uint64
+	header, err := l.chain.HeaderByNumber(l.ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		log.Printf("Error reading the finalized block, transfers aren't finalized: %v", err)
+	} else {
+		finalized = header.Number.Uint64()
+	}
+
+	moved := 0
+	afterID := 0
+	for l.ctx.Err() == nil {
+		batch, err := l.repo.ListInState(followedStates, since, afterID, l.opts.BatchSize)
+		if err != nil {
+			return moved, err
+		}
+
+		chain, err := l.chainState(batch, head, finalized)
+		if err != nil {
+			return moved, err
+		}
+		for _, transfer := range batch {
+			transitions, err := l.advance(transfer, chain, now)
+			moved += transitions
+			if err != nil {
+				return moved, err
+			}
+		}
+
+		if len(batch) < l.opts.BatchSize {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+
+	return moved, nil
+}
+
+// chainState looks up the transactions of the transfers which are checked and the fills of every transfer
+func (l *Lifecycle) chainState(batch []models.TransferRecord, head, finalized uint64) (chainState, error) {
+	chain := chainState{head: head, finalized: finalized}
+
+	var txHashes []string
+	sourceEventIDs := make([]int, 0, len(batch))
+	for _, transfer := range batch {
+		if l.checksInclusion(transfer, chain) {
+			txHashes = append(txHashes, transfer.TransactionHash)
+		}
+		sourceEventIDs = append(sourceEventIDs, transfer.SourceEventID)
+	}
+
+	included, err := ethereum.InclusionBlocks(l.ctx, l.chain, txHashes)
+	if err != nil {
+		return chain, err
+	}
+	chain.included = included
+
+	fills, err := l.transfers.FillsOf(sourceEventIDs)
+	if err != nil {
+		return chain, err
+	}
+	chain.fills = make(map[int]models.BridgeFill, len(fills))
+	for _, fill := range fills {
+		chain.fills[*fill.SourceEventID] = fill
+	}
+
+	return chain, nil
+}
+
+// checksInclusion reports whether the transfer's transaction has to be looked up to move it on
+func (l *Lifecycle) checksInclusion(transfer models.TransferRecord, chain chainState) bool {
+	switch transfer.State {
+	case models.StateObserved:
+		return transfer.BlockNumber+l.opts.Confirmations <= chain.head
+	case models.StateConfirmed:
+		return transfer.BlockNumber <= chain.finalized
+	case models.StateReorged:
+		return true
+	default:
+		return false
+	}
+}
+
+// next returns the state the transfer moves to along with the reason and its block number from then on,
+// an empty state when it stays where it is
+func (l *Lifecycle) next(transfer models.TransferRecord, chain chainState) (models.TransferState, string, uint64) {
+	block, included := chain.included[transfer.TransactionHash]
+
+	switch transfer.State {
+	case models.StateObserved, models.StateConfirmed:
+		if !l.checksInclusion(transfer, chain) {
+			break
+		}
+		if !included {
+			return models.StateReorged, fmt.Sprintf("transaction is no longer in block %d", transfer.BlockNumber), transfer.BlockNumber
+		}
+		if block != transfer.BlockNumber {
+			return models.StateReorged, fmt.Sprintf("transaction moved from block %d to %d", transfer.BlockNumber, block), transfer.BlockNumber
+		}
+		if transfer.State == models.StateObserved {
+			return models.StateConfirmed, fmt.Sprintf("block %d has %d confirmations", block, chain.head-block), block
+		}
+		return models.StateFinalized, fmt.Sprintf("block %d is finalized", block), block
+	case models.StateReorged:
+		if included {
+			return models.StateObserved, fmt.Sprintf("transaction included again in block %d", block), block
+		}
+		return "", "", transfer.BlockNumber
+	}
+
+	if fill, ok := chain.fills[transfer.SourceEventID]; ok && transfer.State.CanTransition(models.StateCompleted) {
+		return models.StateCompleted, fmt.Sprintf("filled by transaction %s on chain %s", fill.TransactionHash, fill.ChainID), transfer.BlockNumber
+	}
+	return "", "", transfer.BlockNumber
+}
+
+// advance moves the transfer until it stays in its state, returns the number of transitions recorded
+func (l *Lifecycle) advance(transfer models.TransferRecord, chain chainState, now time.Time) (int, error) {
+	moved := 0
+	for {
+		to, reason, block := l.next(transfer, chain)
+		if to == "" {
+			return moved, nil
+		}
+
+		transition, err := transfer.Transition(to, reason, now)
+		if err != nil {
+			return moved, err
+		}
+		transfer.BlockNumber = block
+
+		ok, err := l.repo.Transition(transfer, transition)
+		if err != nil || !ok {
+			return moved, err
+		}
+		transfer.State = to
+		moved++
+	}
+}
+
+func (l *Lifecycle) run() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		moved, err := l.Advance()
+		if err != nil && l.ctx.Err() == nil {
+			log.Printf("Error advancing transfers: %v", err)
+		}
+		if moved > 0 {
+			log.Printf("Recorded %d transfer state transitions", moved)
+		}
+
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package transfers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeChain answers receipt requests with the blocks transactions are included in
+type fakeChain struct {
+	head      uint64
+	finalized uint64
+	included  map[string]uint64
+}
+
+func (c *fakeChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.head, nil
+}
+
+func (c *fakeChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: new(big.Int).SetUint64(c.finalized)}, nil
+}
+
+func (c *fakeChain) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	for i := range batch {
+		raw := "null"
+		if block, ok := c.included[batch[i].Args[0].(common.Hash).Hex()]; ok {
+			raw = fmt.Sprintf(`{"blockNumber": "0x%x"}`, block)
+		}
+		batch[i].Error = json.Unmarshal([]byte(raw), batch[i].Result)
+	}
+	return nil
+}
+
+// fakeLifecycleRepository keeps transfers and their transitions in memory
+type fakeLifecycleRepository struct {
+	repositories.LifecycleRepository
+
+	transfers   []models.TransferRecord
+	transitions []models.TransferTransition
+}
+
+func (r *fakeLifecycleRepository) ObserveTransfers(since, now time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeLifecycleRepository) ListInState(states []models.TransferState, since time.Time, afterID, limit int) ([]models.TransferRecord, error) {
+	var transfers []models.TransferRecord
+	for _, transfer := range r.transfers {
+		for _, state := range states {
+			if transfer.State == state && transfer.ID > afterID && len(transfers) < limit {
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+	return transfers, nil
+}
+
+func (r *fakeLifecycleRepository) Transition(transfer models.TransferRecord, transition models.TransferTransition) (bool, error) {
+	for i := range r.transfers {
+		if r.transfers[i].ID == transfer.ID && r.transfers[i].State == transition.FromState {
+			r.transfers[i].State = transition.ToState
+			r.transfers[i].BlockNumber = transfer.BlockNumber
+			r.transitions = append(r.transitions, transition)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeTransferRepository) FillsOf(sourceEventIDs []int) ([]models.BridgeFill, error) {
+	var fills []models.BridgeFill
+	for _, fill := range r.fills {
+		if fill.SourceEventID != nil {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+func txHash(n int) string {
+	return common.BigToHash(big.NewInt(int64(n))).Hex()
+}
+
+func TestLifecycle_Advance(t *testing.T) {
+	sourceEventID := 1
+	transfers := newFakeTransferRepository()
+	transfers.fills = []models.BridgeFill{{ID: 9, ChainID: "10", TransactionHash: "0xfill", SourceEventID: &sourceEventID}}
+	chain := &fakeChain{head: 120, finalized: 110, included: map[string]uint64{
+		txHash(1): 100,
+		txHash(2): 115,
+		txHash(4): 108,
+	}}
+	repo := &fakeLifecycleRepository{transfers: []models.TransferRecord{
+		// Filled and deep enough to be finalized
+		{ID: 1, SourceEventID: 1, TransactionHash: txHash(1), BlockNumber: 100, State: models.StateObserved},
+		// Not enough confirmations yet
+		{ID: 2, SourceEventID: 2, TransactionHash: txHash(2), BlockNumber: 115, State: models.StateObserved},
+		// Dropped out of the chain
+		{ID: 3, SourceEventID: 3, TransactionHash: txHash(3), BlockNumber: 105, State: models.StateObserved},
+		// Included again in another block
+		{ID: 4, SourceEventID: 4, TransactionHash: txHash(4), BlockNumber: 90, State: models.StateReorged},
+	}}
+
+	lifecycle := NewLifecycle(repo, transfers, chain, LifecycleOptions{Confirmations: 12, Lookback: time.Hour, BatchSize: 10})
+	moved, err := lifecycle.Advance()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, moved)
+
+	states := make(map[int]models.TransferState)
+	for _, transfer := range repo.transfers {
+		states[transfer.ID] = transfer.State
+	}
+	assert.Equal(t, map[int]models.TransferState{
+		1: models.StateCompleted,
+		2: models.StateObserved,
+		3: models.StateReorged,
+		4: models.StateFinalized,
+	}, states)
+	assert.Equal(t, uint64(108), repo.transfers[3].BlockNumber)
+
+	var history []string
+	for _, transition := range repo.transitions {
+		if transition.TransferID == 1 {
+			history = append(history, transition.Reason)
+		}
+	}
+	assert.Equal(t, []string{
+		"block 100 has 20 confirmations",
+		"block 100 is finalized",
+		"filled by transaction 0xfill on chain 10",
+	}, history)
+}
+
+func TestTransferRecord_Transition(t *testing.T) {
+	now := time.Now()
+	transfer := models.TransferRecord{ID: 1, State: models.StateFinalized}
+
+	transition, err := transfer.Transition(models.StateRefunded, "refunded by the bridge", now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferTransition{
+		TransferID: 1, FromState: models.StateFinalized, ToState: models.StateRefunded, Reason: "refunded by the bridge", CreatedAt: now,
+	}, transition)
+
+	// Final and unconfirmed transfers can't be refunded
+	for _, state := range []models.TransferState{models.StateCompleted, models.StateRefunded, models.StateObserved, models.StateReorged} {
+		transfer.State = state
+		_, err := transfer.Transition(models.StateRefunded, "", now)
+		assert.ErrorIs(t, err, models.ErrInvalidTransition, state)
+	}
+}
@@ -0,0 +1,187 @@
+package transfers
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// MatcherOptions tunes the Matcher
+type MatcherOptions struct {
+	// Interval is the wait between matching passes
+	Interval time.Duration
+	// RetryFor is how long after being saved a fill is matched again, its source event
+	// may be ingested after it when the source chain is behind
+	RetryFor time.Duration
+	// BatchSize is the number of fills read at once
+	BatchSize int
+}
+
+// DefaultMatcherOptions are sensible defaults for production use
+var DefaultMatcherOptions = MatcherOptions{
+	Interval:  30 * time.Second,
+	RetryFor:  time.Hour,
+	BatchSize: 500,
+}
+
+// Matcher links fills to the SocketBridge events they complete.
+//
+// A fill completes the oldest SocketBridge event of its bridge to its chain and receiver within
+// the bridge's window, whose amount is at most MaxFeeBps above the amount filled. Bridges with
+// deposit ids are matched by id instead of amount
+type Matcher struct {
+	repo   repositories.TransferRepository
+	spec   *Spec
+	opts   MatcherOptions
+	now    func() time.Time
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewMatcher(repo repositories.TransferRepository, spec *Spec, opts MatcherOptions) *Matcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Matcher{
+		repo:   repo,
+		spec:   spec,
+		opts:   opts,
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start matches fills every Interval
+func (m *Matcher) Start() {
+	m.wg.Add(1)
+	go m.run()
+}
+
+// Stop waits for an in flight pass to finish
+func (m *Matcher) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// Match tries to match every unmatched fill saved within RetryFor, returns the number matched
+func (m *Matcher) Match() (int, error) {
+	now := m.now().UTC()
+	since := now.Add(-m.opts.RetryFor)
+	matched := 0
+	afterID := 0
+
+	for m.ctx.Err() == nil {
+		fills, err := m.repo.ListUnmatchedFills(since, afterID, m.opts.BatchSize)
+		if err != nil {
+			return matched, err
+		}
+
+		for _, fill := range fills {
+			ok, err := m.matchFill(fill, now)
+			if err != nil {
+				return matched, err
+			}
+			if ok {
+				matched++
+			}
+		}
+
+		if len(fills) < m.opts.BatchSize {
+			break
+		}
+		afterID = fills[len(fills)-1].ID
+	}
+
+	return matched, nil
+}
+
+// matchFill links the fill to its source event, false if there's none (yet)
+func (m *Matcher) matchFill(fill models.BridgeFill, now time.Time) (bool, error) {
+	bridge, ok := m.spec.Bridge(fill.EventName)
+	if !ok {
+		return false, nil
+	}
+
+	criteria, ok := sourceCriteria(bridge, fill)
+	if !ok {
+		return false, nil
+	}
+
+	source, err := m.repo.FindSource(criteria)
+	if err != nil || source == nil {
+		return false, err
+	}
+
+	sourceTime := source.Timestamp
+	if source.BlockTimestamp != nil {
+		sourceTime = *source.BlockTimestamp
+	}
+
+	return m.repo.LinkFill(fill.ID, source.ID, fill.Time().Sub(sourceTime), now)
+}
+
+// sourceCriteria describes the source events the fill may complete,
+// false if the fill is missing the fields it'd be matched by
+func sourceCriteria(bridge models.FillSpec, fill models.BridgeFill) (repositories.SourceCriteria, bool) {
+	criteria := repositories.SourceCriteria{
+		BridgeName:  bridge.BridgeName,
+		DestChainID: fill.ChainID,
+		Receiver:    fill.Receiver,
+		From:        fill.Time().Add(-bridge.Window()),
+		To:          fill.Time().Add(bridge.Window()),
+	}
+
+	if bridge.DepositID != "" {
+		if fill.DepositID == "" {
+			return criteria, false
+		}
+		criteria.DepositField = bridge.SourceDepositID
+		criteria.DepositID = fill.DepositID
+		return criteria, true
+	}
+
+	if fill.Amount != nil {
+		filled, ok := new(big.Int).SetString(*fill.Amount, 10)
+		if !ok {
+			return criteria, false
+		}
+
+		// filled = source * (10000 - fee) / 10000, so source is at most filled * 10000 / (10000 - fee)
+		maxAmount := new(big.Int).Mul(filled, big.NewInt(10000))
+		maxAmount.Div(maxAmount, big.NewInt(int64(10000-bridge.MaxFeeBps)))
+
+		criteria.MinAmount = filled.String()
+		criteria.MaxAmount = maxAmount.String()
+	}
+
+	return criteria, fill.Receiver != ""
+}
+
+func (m *Matcher) run() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		matched, err := m.Match()
+		if err != nil && m.ctx.Err() == nil {
+			log.Printf("Error matching fills: %v", err)
+		}
+		if matched > 0 {
+			log.Printf("Matched %d fills to their transfers", matched)
+		}
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
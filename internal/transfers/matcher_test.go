@@ -0,0 +1,178 @@
+package transfers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTransferRepository keeps fills in memory, FindSource returns the sources in order
+type fakeTransferRepository struct {
+	repositories.TransferRepository
+
+	fills    []models.BridgeFill
+	sources  []models.BridgeEvent
+	criteria []repositories.SourceCriteria
+	links    map[int]int
+	latency  map[int]time.Duration
+}
+
+func (r *fakeTransferRepository) SaveFill(fill *models.BridgeFill) error {
+	fill.ID = len(r.fills) + 1
+	r.fills = append(r.fills, *fill)
+	return nil
+}
+
+func (r *fakeTransferRepository) ListUnmatchedFills(since time.Time, afterID int, limit int) ([]models.BridgeFill, error) {
+	var fills []models.BridgeFill
+	for _, fill := range r.fills {
+		if _, linked := r.links[fill.ID]; !linked && fill.ID > afterID && len(fills) < limit {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+func (r *fakeTransferRepository) FindSource(criteria repositories.SourceCriteria) (*models.BridgeEvent, error) {
+	r.criteria = append(r.criteria, criteria)
+	if len(r.sources) == 0 {
+		return nil, nil
+	}
+
+	source := r.sources[0]
+	r.sources = r.sources[1:]
+	return &source, nil
+}
+
+func (r *fakeTransferRepository) LinkFill(fillID, sourceEventID int, latency time.Duration, matchedAt time.Time) (bool, error) {
+	r.links[fillID] = sourceEventID
+	r.latency[fillID] = latency
+	return true, nil
+}
+
+func newFakeTransferRepository() *fakeTransferRepository {
+	return &fakeTransferRepository{
+		links:   make(map[int]int),
+		latency: make(map[int]time.Duration),
+	}
+}
+
+var hop = models.FillSpec{
+	BridgeName:    "hop",
+	Event:         "TransferFromL1Completed",
+	Receiver:      "recipient",
+	Amount:        "amount",
+	MaxFeeBps:     50,
+	WindowMinutes: 60,
+	ChainIDs:      []string{"10"},
+}
+
+func TestRecorder_PublishEvent(t *testing.T) {
+	repo := newFakeTransferRepository()
+	recorder := NewRecorder("10", &Spec{Bridges: []models.FillSpec{hop}}, repo)
+
+	blockTime := time.Date(2024, 12, 14, 14, 20, 0, 0, time.UTC)
+	err := recorder.PublishEvent(models.BridgeEvent{
+		EventName:       "TransferFromL1Completed",
+		TransactionHash: "0xfill",
+		LogIndex:        3,
+		BlockNumber:     128000000,
+		BlockTimestamp:  &blockTime,
+		Payload: models.EventPayload{
+			"recipient": "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+			"amount":    "995000000",
+		},
+	})
+	assert.NoError(t, err)
+
+	// Other events of the contracts aren't fills
+	err = recorder.PublishEvent(models.BridgeEvent{EventName: "TransferSent"})
+	assert.NoError(t, err)
+
+	if assert.Len(t, repo.fills, 1) {
+		fill := repo.fills[0]
+		assert.Equal(t, "10", fill.ChainID)
+		assert.Equal(t, "hop", fill.BridgeName)
+		assert.Equal(t, "0x0041B0239420DebF7885433d09AE4f274d3d8AC3", fill.Receiver)
+		assert.Equal(t, "995000000", *fill.Amount)
+		assert.Equal(t, uint(3), fill.LogIndex)
+		assert.Equal(t, blockTime, fill.Time())
+	}
+}
+
+func TestSourceCriteria(t *testing.T) {
+	blockTime := time.Date(2024, 12, 14, 14, 20, 0, 0, time.UTC)
+	amount := "995000000"
+	fill := models.BridgeFill{
+		ChainID:        "10",
+		Receiver:       "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+		Amount:         &amount,
+		BlockTimestamp: &blockTime,
+	}
+
+	criteria, ok := sourceCriteria(hop, fill)
+
+	assert.True(t, ok)
+	assert.Equal(t, "hop", criteria.BridgeName)
+	assert.Equal(t, "10", criteria.DestChainID)
+	// 0.5% of 1000000000 is kept as a fee
+	assert.Equal(t, "995000000", criteria.MinAmount)
+	assert.Equal(t, "1000000000", criteria.MaxAmount)
+	assert.Equal(t, blockTime.Add(-time.Hour), criteria.From)
+	assert.Equal(t, blockTime.Add(time.Hour), criteria.To)
+
+	// Bridges with deposit ids are matched by id
+	across := hop
+	across.DepositID = "depositId"
+	across.SourceDepositID = "metadata"
+
+	_, ok = sourceCriteria(across, fill)
+	assert.False(t, ok)
+
+	fill.DepositID = "1842"
+	criteria, ok = sourceCriteria(across, fill)
+	assert.True(t, ok)
+	assert.Equal(t, "metadata", criteria.DepositField)
+	assert.Equal(t, "1842", criteria.DepositID)
+	assert.Empty(t, criteria.MinAmount)
+
+	// Nothing to match a fill without a receiver by
+	_, ok = sourceCriteria(hop, models.BridgeFill{ChainID: "10", Amount: &amount})
+	assert.False(t, ok)
+}
+
+func TestMatcher_Match(t *testing.T) {
+	repo := newFakeTransferRepository()
+	spec := &Spec{Bridges: []models.FillSpec{hop}}
+
+	fillTime := time.Date(2024, 12, 14, 14, 20, 0, 0, time.UTC)
+	sourceTime := fillTime.Add(-90 * time.Second)
+	amount := "995000000"
+	for i := 0; i < 3; i++ {
+		repo.SaveFill(&models.BridgeFill{
+			ChainID:        "10",
+			EventName:      hop.Event,
+			Receiver:       "0x0041B0239420DebF7885433d09AE4f274d3d8AC3",
+			Amount:         &amount,
+			BlockTimestamp: &fillTime,
+		})
+	}
+	// Only two of the fills have their source ingested
+	repo.sources = []models.BridgeEvent{
+		{ID: 7, BlockTimestamp: &sourceTime},
+		{ID: 9, Timestamp: sourceTime},
+	}
+
+	matcher := NewMatcher(repo, spec, MatcherOptions{Interval: time.Minute, RetryFor: time.Hour, BatchSize: 2})
+	matched, err := matcher.Match()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, matched)
+	assert.Len(t, repo.criteria, 3)
+	assert.Equal(t, map[int]int{1: 7, 2: 9}, repo.links)
+	assert.Equal(t, 90*time.Second, repo.latency[1])
+	assert.Equal(t, 90*time.Second, repo.latency[2])
+}
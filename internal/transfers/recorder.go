@@ -0,0 +1,62 @@
+package transfers
+
+import (
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// Recorder saves fills of tracked bridges ingested from a destination chain,
+// it's handed to the chain's ingester in place of the stream producer
+type Recorder struct {
+	chainID string
+	spec    *Spec
+	repo    repositories.TransferRepository
+}
+
+func NewRecorder(chainID string, spec *Spec, repo repositories.TransferRepository) *Recorder {
+	return &Recorder{
+		chainID: chainID,
+		spec:    spec,
+		repo:    repo,
+	}
+}
+
+// PublishEvent saves the event as a fill if it's the fill event of a tracked bridge, other events are dropped
+func (r *Recorder) PublishEvent(event models.BridgeEvent) error {
+	bridge, ok := r.spec.Bridge(event.EventName)
+	if !ok {
+		return nil
+	}
+
+	fill := NewFill(r.chainID, bridge, event)
+	return r.repo.SaveFill(&fill)
+}
+
+// Stop implements producer.Producer, fills are saved synchronously so there's nothing to stop
+func (r *Recorder) Stop() {}
+
+// NewFill reads the fill of the bridge from the event's payload
+func NewFill(chainID string, bridge models.FillSpec, event models.BridgeEvent) models.BridgeFill {
+	fill := models.BridgeFill{
+		ChainID:         chainID,
+		BridgeName:      bridge.BridgeName,
+		EventName:       event.EventName,
+		TransactionHash: event.TransactionHash,
+		LogIndex:        event.LogIndex,
+		BlockNumber:     event.BlockNumber,
+		BlockTimestamp:  event.BlockTimestamp,
+		Payload:         event.Payload,
+	}
+
+	fill.Receiver, _ = event.Payload.Field(bridge.Receiver)
+	if bridge.Amount != "" {
+		if amount, ok := event.Payload.Field(bridge.Amount); ok {
+			fill.Amount = &amount
+		}
+	}
+	if bridge.DepositID != "" {
+		fill.DepositID, _ = event.Payload.Field(bridge.DepositID)
+	}
+
+	return fill
+}
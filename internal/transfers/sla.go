@@ -0,0 +1,130 @@
+package transfers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/repositories"
+)
+
+// metricsLogInterval is how often completion latency percentiles are logged
+const metricsLogInterval = time.Hour
+
+// SLAOptions tunes the SLAMonitor
+type SLAOptions struct {
+	// Interval is the wait between checks
+	Interval time.Duration
+	// Lookback is how far back transfers are checked, older ones are never flagged
+	Lookback time.Duration
+	// MetricsWindow is the period completion latency percentiles are computed over
+	MetricsWindow time.Duration
+}
+
+// DefaultSLAOptions are sensible defaults for production use
+var DefaultSLAOptions = SLAOptions{
+	Interval:      time.Minute,
+	Lookback:      24 * time.Hour,
+	MetricsWindow: 24 * time.Hour,
+}
+
+// SLAMonitor flags transfers which aren't completed within the SLA of their route as SLA breaches,
+// and resolves breaches once their transfers complete. Completion latency percentiles of every
+// bridge are logged every hour
+type SLAMonitor struct {
+	repo   repositories.SLARepository
+	spec   *Spec
+	opts   SLAOptions
+	now    func() time.Time
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSLAMonitor(repo repositories.SLARepository, spec *Spec, opts SLAOptions) *SLAMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &SLAMonitor{
+		repo:   repo,
+		spec:   spec,
+		opts:   opts,
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start checks SLAs every Interval, nothing is started when no bridge is tracked
+func (m *SLAMonitor) Start() {
+	if len(m.spec.Bridges) == 0 {
+		return
+	}
+
+	m.wg.Add(1)
+	go m.run()
+}
+
+// Stop waits for an in flight check to finish
+func (m *SLAMonitor) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// Check records breaches of every route and resolves breaches of completed transfers
+func (m *SLAMonitor) Check() (breached, resolved int64, err error) {
+	now := m.now()
+
+	for _, rule := range m.spec.SLARules() {
+		recorded, err := m.repo.RecordBreaches(rule, now.Add(-m.opts.Lookback), now)
+		if err != nil {
+			return breached, resolved, err
+		}
+		breached += recorded
+	}
+
+	resolved, err = m.repo.ResolveBreaches()
+	return breached, resolved, err
+}
+
+// logMetrics logs completion latency percentiles of every bridge over MetricsWindow
+func (m *SLAMonitor) logMetrics() {
+	metrics, err := m.repo.CompletionMetrics(m.now().Add(-m.opts.MetricsWindow))
+	if err != nil {
+		log.Printf("Error computing completion metrics: %v", err)
+		return
+	}
+
+	for _, metric := range metrics {
+		log.Printf("Completion of %s transfers over %s: p50 %.1fs, p95 %.1fs, %d completed",
+			metric.BridgeName, m.opts.MetricsWindow, metric.P50Seconds, metric.P95Seconds, metric.Completed)
+	}
+}
+
+func (m *SLAMonitor) run() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	var metricsLoggedAt time.Time
+	for {
+		breached, resolved, err := m.Check()
+		if err != nil && m.ctx.Err() == nil {
+			log.Printf("Error checking transfer SLAs: %v", err)
+		}
+		if breached > 0 || resolved > 0 {
+			log.Printf("%d transfers breached their SLA, %d breaches resolved", breached, resolved)
+		}
+		if time.Since(metricsLoggedAt) >= metricsLogInterval {
+			m.logMetrics()
+			metricsLoggedAt = time.Now()
+		}
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
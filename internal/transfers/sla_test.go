@@ -0,0 +1,83 @@
+package transfers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSLARepository records the rules breaches were recorded for
+type fakeSLARepository struct {
+	repositories.SLARepository
+
+	rules    []models.SLARule
+	since    time.Time
+	resolved int64
+}
+
+func (r *fakeSLARepository) RecordBreaches(rule models.SLARule, since, now time.Time) (int64, error) {
+	r.rules = append(r.rules, rule)
+	r.since = since
+	return 1, nil
+}
+
+func (r *fakeSLARepository) ResolveBreaches() (int64, error) {
+	return r.resolved, nil
+}
+
+func TestSpec_SLARules(t *testing.T) {
+	path := writeSpec(t, `{
+		"chains": [
+			{"chain_id": "10", "rpc_urls": ["wss://optimism"], "contracts": ["0x1"], "abi_files": ["./hop.json"]},
+			{"chain_id": "137", "rpc_urls": ["wss://polygon"], "contracts": ["0x2"], "abi_files": ["./hop.json"]},
+			{"chain_id": "42161", "rpc_urls": ["wss://arbitrum"], "contracts": ["0x3"], "abi_files": ["./hop.json"]}
+		],
+		"bridges": [
+			{"bridge_name": "hop", "event": "TransferFromL1Completed", "receiver": "recipient", "sla_minutes": 20},
+			{"bridge_name": "across", "event": "FilledRelay", "receiver": "recipient", "window_minutes": 15}
+		],
+		"slas": [
+			{"bridge_name": "hop", "dest_chain_id": "137", "sla_minutes": 45}
+		]
+	}`)
+	spec, err := LoadSpec(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []models.SLARule{
+		{BridgeName: "hop", DestChainIDs: []string{"137"}, SLA: 45 * time.Minute},
+		{BridgeName: "hop", DestChainIDs: []string{"10", "42161"}, SLA: 20 * time.Minute},
+		// SLA defaults to the window
+		{BridgeName: "across", DestChainIDs: []string{"10", "137", "42161"}, SLA: 15 * time.Minute},
+	}, spec.SLARules())
+
+	// Routes have to be tracked
+	_, err = LoadSpec(writeSpec(t, `{
+		"chains": [{"chain_id": "10", "rpc_urls": ["wss://optimism"], "contracts": ["0x1"], "abi_files": ["./hop.json"]}],
+		"bridges": [{"bridge_name": "hop", "event": "TransferFromL1Completed", "receiver": "recipient"}],
+		"slas": [{"bridge_name": "hop", "dest_chain_id": "137", "sla_minutes": 45}]
+	}`))
+	assert.Error(t, err)
+}
+
+func TestSLAMonitor_Check(t *testing.T) {
+	repo := &fakeSLARepository{resolved: 2}
+	spec := &Spec{Bridges: []models.FillSpec{
+		{BridgeName: "hop", SLAMinutes: 20, ChainIDs: []string{"10"}},
+		{BridgeName: "across", SLAMinutes: 15, ChainIDs: []string{"10"}},
+	}}
+
+	now := time.Date(2024, 12, 14, 15, 0, 0, 0, time.UTC)
+	monitor := NewSLAMonitor(repo, spec, DefaultSLAOptions)
+	monitor.now = func() time.Time { return now }
+
+	breached, resolved, err := monitor.Check()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), breached)
+	assert.Equal(t, int64(2), resolved)
+	assert.Len(t, repo.rules, 2)
+	assert.Equal(t, now.Add(-24*time.Hour), repo.since)
+}
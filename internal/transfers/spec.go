@@ -0,0 +1,159 @@
+package transfers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+)
+
+// defaultWindowMinutes is used for bridges without a window of their own
+const defaultWindowMinutes = 60
+
+// ChainSpec is a destination chain fills are ingested from
+type ChainSpec struct {
+	ChainID   string   `json:"chain_id"`
+	RPCURLs   []string `json:"rpc_urls"`
+	Contracts []string `json:"contracts"`
+	ABIFiles  []string `json:"abi_files"`
+	// Events are the watched event names, every bridge's fill event when empty
+	Events []string `json:"events"`
+	// StartBlock is the first block polled, 0 starts at the confirmed head
+	StartBlock uint64 `json:"start_block"`
+}
+
+// Spec is the set of tracked bridges and the destination chains their fills are read from,
+// loaded from the JSON file at `TRANSFERS_FILE`
+type Spec struct {
+	Chains  []ChainSpec       `json:"chains"`
+	Bridges []models.FillSpec `json:"bridges"`
+	SLAs    []models.RouteSLA `json:"slas"`
+}
+
+// LoadSpec reads and validates the spec, an empty path tracks nothing
+func LoadSpec(path string) (*Spec, error) {
+	spec := &Spec{}
+	if path == "" {
+		return spec, nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transfers file: %w", err)
+	}
+	if err := json.Unmarshal(contents, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse transfers file: %w", err)
+	}
+
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Bridge returns the bridge whose fill event is named eventName
+func (s *Spec) Bridge(eventName string) (models.FillSpec, bool) {
+	for _, bridge := range s.Bridges {
+		if bridge.Event == eventName {
+			return bridge, true
+		}
+	}
+	return models.FillSpec{}, false
+}
+
+// validate checks the spec and fills in defaults, every bridge is tracked on every chain
+func (s *Spec) validate() error {
+	chainIDs := make([]string, 0, len(s.Chains))
+	for i, chain := range s.Chains {
+		if chain.ChainID == "" {
+			return fmt.Errorf("chain %d has no chain_id", i)
+		}
+		if slices.Contains(chainIDs, chain.ChainID) {
+			return fmt.Errorf("chain %s is listed twice", chain.ChainID)
+		}
+		if len(chain.RPCURLs) == 0 || len(chain.Contracts) == 0 || len(chain.ABIFiles) == 0 {
+			return fmt.Errorf("chain %s needs rpc_urls, contracts and abi_files", chain.ChainID)
+		}
+		chainIDs = append(chainIDs, chain.ChainID)
+	}
+
+	events := make([]string, 0, len(s.Bridges))
+	for i := range s.Bridges {
+		bridge := &s.Bridges[i]
+		if bridge.BridgeName == "" || bridge.Event == "" || bridge.Receiver == "" {
+			return fmt.Errorf("bridge %d needs bridge_name, event and receiver", i)
+		}
+		if slices.Contains(events, bridge.Event) {
+			return fmt.Errorf("event %s is the fill event of more than one bridge", bridge.Event)
+		}
+		if (bridge.DepositID == "") != (bridge.SourceDepositID == "") {
+			return fmt.Errorf("bridge %s needs both deposit_id and source_deposit_id, or neither", bridge.BridgeName)
+		}
+		if bridge.MaxFeeBps >= 10000 {
+			return fmt.Errorf("bridge %s max_fee_bps has to be below 10000", bridge.BridgeName)
+		}
+		if bridge.WindowMinutes <= 0 {
+			bridge.WindowMinutes = defaultWindowMinutes
+		}
+		if bridge.SLAMinutes <= 0 {
+			bridge.SLAMinutes = bridge.WindowMinutes
+		}
+
+		bridge.ChainIDs = chainIDs
+		events = append(events, bridge.Event)
+	}
+
+	for i := range s.Chains {
+		if len(s.Chains[i].Events) == 0 {
+			s.Chains[i].Events = events
+		}
+	}
+
+	for _, route := range s.SLAs {
+		if !slices.ContainsFunc(s.Bridges, func(bridge models.FillSpec) bool { return bridge.BridgeName == route.BridgeName }) {
+			return fmt.Errorf("SLA of bridge %s, which isn't tracked", route.BridgeName)
+		}
+		if !slices.Contains(chainIDs, route.DestChainID) || route.SLAMinutes <= 0 {
+			return fmt.Errorf("SLA of bridge %s needs a tracked dest_chain_id and sla_minutes", route.BridgeName)
+		}
+	}
+
+	return nil
+}
+
+// SLARules returns the SLA of every tracked route, a rule per route with an SLA of its own
+// and one per bridge for its other routes
+func (s *Spec) SLARules() []models.SLARule {
+	var rules []models.SLARule
+	for _, bridge := range s.Bridges {
+		var defaultChains []string
+		for _, chainID := range bridge.ChainIDs {
+			i := slices.IndexFunc(s.SLAs, func(route models.RouteSLA) bool {
+				return route.BridgeName == bridge.BridgeName && route.DestChainID == chainID
+			})
+			if i < 0 {
+				defaultChains = append(defaultChains, chainID)
+				continue
+			}
+
+			rules = append(rules, models.SLARule{
+				BridgeName:   bridge.BridgeName,
+				DestChainIDs: []string{chainID},
+				SLA:          time.Duration(s.SLAs[i].SLAMinutes) * time.Minute,
+			})
+		}
+
+		if len(defaultChains) > 0 {
+			rules = append(rules, models.SLARule{
+				BridgeName:   bridge.BridgeName,
+				DestChainIDs: defaultChains,
+				SLA:          time.Duration(bridge.SLAMinutes) * time.Minute,
+			})
+		}
+	}
+
+	return rules
+}
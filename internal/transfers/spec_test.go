@@ -0,0 +1,79 @@
+package transfers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSpec(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "transfers.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSpec(t *testing.T) {
+	path := writeSpec(t, `{
+		"chains": [
+			{"chain_id": "10", "rpc_urls": ["wss://optimism"], "contracts": ["0x1"], "abi_files": ["./hop.json"]},
+			{"chain_id": "42161", "rpc_urls": ["wss://arbitrum"], "contracts": ["0x2"], "abi_files": ["./hop.json"], "events": ["TransferFromL1Completed"], "start_block": 280000000}
+		],
+		"bridges": [
+			{"bridge_name": "hop", "event": "TransferFromL1Completed", "receiver": "recipient", "amount": "amount", "max_fee_bps": 50},
+			{"bridge_name": "across", "event": "FilledRelay", "receiver": "recipient", "deposit_id": "depositId", "source_deposit_id": "metadata", "window_minutes": 15}
+		]
+	}`)
+
+	spec, err := LoadSpec(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TransferFromL1Completed", "FilledRelay"}, spec.Chains[0].Events)
+	assert.Equal(t, []string{"TransferFromL1Completed"}, spec.Chains[1].Events)
+	assert.Equal(t, uint64(280000000), spec.Chains[1].StartBlock)
+
+	bridge, ok := spec.Bridge("TransferFromL1Completed")
+	assert.True(t, ok)
+	assert.Equal(t, defaultWindowMinutes, bridge.WindowMinutes)
+	assert.Equal(t, []string{"10", "42161"}, bridge.ChainIDs)
+	assert.True(t, bridge.Tracks("hop", "42161"))
+	assert.False(t, bridge.Tracks("hop", "1"))
+
+	bridge, _ = spec.Bridge("FilledRelay")
+	assert.Equal(t, 15, bridge.WindowMinutes)
+
+	_, ok = spec.Bridge("TransferSent")
+	assert.False(t, ok)
+}
+
+func TestLoadSpec_Empty(t *testing.T) {
+	spec, err := LoadSpec("")
+
+	assert.NoError(t, err)
+	assert.Empty(t, spec.Chains)
+	assert.Empty(t, spec.Bridges)
+}
+
+func TestLoadSpec_Invalid(t *testing.T) {
+	for name, contents := range map[string]string{
+		"chain without endpoints": `{"chains": [{"chain_id": "10", "contracts": ["0x1"], "abi_files": ["./hop.json"]}]}`,
+		"chain listed twice": `{"chains": [
+			{"chain_id": "10", "rpc_urls": ["wss://a"], "contracts": ["0x1"], "abi_files": ["./hop.json"]},
+			{"chain_id": "10", "rpc_urls": ["wss://b"], "contracts": ["0x1"], "abi_files": ["./hop.json"]}
+		]}`,
+		"bridge without receiver": `{"bridges": [{"bridge_name": "hop", "event": "TransferFromL1Completed"}]}`,
+		"event of two bridges": `{"bridges": [
+			{"bridge_name": "hop", "event": "Filled", "receiver": "recipient"},
+			{"bridge_name": "across", "event": "Filled", "receiver": "recipient"}
+		]}`,
+		"deposit id of the fill only": `{"bridges": [{"bridge_name": "across", "event": "FilledRelay", "receiver": "recipient", "deposit_id": "depositId"}]}`,
+		"whole amount as fee":         `{"bridges": [{"bridge_name": "hop", "event": "TransferFromL1Completed", "receiver": "recipient", "max_fee_bps": 10000}]}`,
+		"not json":                    `chains: []`,
+	} {
+		_, err := LoadSpec(writeSpec(t, contents))
+		assert.Error(t, err, name)
+	}
+}
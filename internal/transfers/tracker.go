@@ -0,0 +1,117 @@
+package transfers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/eth-bridging/internal/repositories"
+	ethereum "github.com/eth-bridging/pkg/go-eth"
+)
+
+// Destination is a destination chain along with the client its fills are read with
+type Destination struct {
+	Chain  ChainSpec
+	Client *ethereum.EthereumClient
+}
+
+// DialDestinations connects to every chain of the spec and starts health checks of its endpoints,
+// only the fill events of the chain are watched
+func DialDestinations(spec *Spec, opts ethereum.PoolOptions) ([]Destination, error) {
+	destinations := make([]Destination, 0, len(spec.Chains))
+	for _, chain := range spec.Chains {
+		endpoints, err := ethereum.NewEndpointPool(chain.RPCURLs, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to chain %s: %w", chain.ChainID, err)
+		}
+
+		contractABIs, err := ethereum.ReadABIFiles(chain.ABIFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ABIs of chain %s: %w", chain.ChainID, err)
+		}
+		client, err := ethereum.NewEventClient(endpoints, chain.Contracts, contractABIs, chain.Events)
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch chain %s: %w", chain.ChainID, err)
+		}
+
+		endpoints.Start()
+		destinations = append(destinations, Destination{Chain: chain, Client: client})
+	}
+
+	return destinations, nil
+}
+
+// Tracker ingests fills of every destination chain and matches them to their transfers
+type Tracker struct {
+	destinations []Destination
+	ingesters    []ethereum.EthereumClientInterface
+	spec         *Spec
+	repo         repositories.TransferRepository
+	matcher      *Matcher
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewTracker reads fills of the destinations with a subscription or by polling, see ethereum.NewIngester,
+// every chain is polled from its own start block
+func NewTracker(destinations []Destination, spec *Spec, repo repositories.TransferRepository, ingestMode string, pollOpts ethereum.PollerOptions, opts MatcherOptions) (*Tracker, error) {
+	ingesters := make([]ethereum.EthereumClientInterface, 0, len(destinations))
+	for _, destination := range destinations {
+		chainPollOpts := pollOpts
+		chainPollOpts.StartBlock = destination.Chain.StartBlock
+
+		ingester, err := ethereum.NewIngester(destination.Client, ingestMode, chainPollOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to ingest chain %s: %w", destination.Chain.ChainID, err)
+		}
+		ingesters = append(ingesters, ingester)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Tracker{
+		destinations: destinations,
+		ingesters:    ingesters,
+		spec:         spec,
+		repo:         repo,
+		matcher:      NewMatcher(repo, spec, opts),
+		ctx:          ctx,
+		cancel:       cancel,
+	}, nil
+}
+
+// Start starts ingesting fills and matching them, nothing is started when no bridge is tracked
+func (t *Tracker) Start() {
+	if len(t.spec.Bridges) == 0 || len(t.destinations) == 0 {
+		return
+	}
+
+	for i, ingester := range t.ingesters {
+		chainID := t.destinations[i].Chain.ChainID
+
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+
+			recorder := NewRecorder(chainID, t.spec, t.repo)
+			if err := ingester.StartBridgingEventPublisher(t.ctx, recorder); err != nil && t.ctx.Err() == nil {
+				log.Printf("Error ingesting fills of chain %s: %v", chainID, err)
+			}
+		}()
+	}
+
+	t.matcher.Start()
+}
+
+// Stop stops ingestion and matching, along with the health checks of the chains' endpoints
+func (t *Tracker) Stop() {
+	t.cancel()
+	t.wg.Wait()
+	t.matcher.Stop()
+
+	for _, destination := range t.destinations {
+		destination.Client.Endpoints().Stop()
+	}
+}
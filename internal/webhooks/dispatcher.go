@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/repositories"
+)
+
+const (
+	// SignatureHeader carries `sha256=<hex hmac>` of `<timestamp>.<body>` signed with the webhook secret
+	SignatureHeader = "X-Bridge-Signature"
+	// TimestampHeader carries the unix timestamp used in the signature, so receivers can reject replays
+	TimestampHeader = "X-Bridge-Timestamp"
+	// EventTypeBridgeEventCreated is sent for every newly persisted bridge event
+	EventTypeBridgeEventCreated = "bridge_event.created"
+)
+
+// Options tunes delivery behaviour of the Dispatcher
+type Options struct {
+	// Workers is the number of deliveries processed concurrently
+	Workers int
+	// QueueSize is the number of pending deliveries buffered before new ones are dropped
+	QueueSize int
+	// MaxAttempts is the number of attempts per delivery before it's considered failed
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, it doubles after every attempt
+	InitialBackoff time.Duration
+	// DisableAfter is the number of consecutive failed deliveries after which a webhook is disabled
+	DisableAfter int
+	// Timeout is the HTTP timeout of a single attempt
+	Timeout time.Duration
+	// CacheTTL is how long active webhooks are cached before being reloaded
+	CacheTTL time.Duration
+}
+
+// DefaultOptions are sensible defaults for production use
+var DefaultOptions = Options{
+	Workers:        4,
+	QueueSize:      1000,
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	DisableAfter:   10,
+	Timeout:        10 * time.Second,
+	CacheTTL:       30 * time.Second,
+}
+
+// Payload is the JSON body POSTed to webhooks
+type Payload struct {
+	Type      string             `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      models.BridgeEvent `json:"data"`
+}
+
+type delivery struct {
+	webhook models.Webhook
+	event   models.BridgeEvent
+}
+
+// Dispatcher delivers persisted bridge events to subscribed webhooks.
+//
+// It's fed by the consumer as an event listener, deliveries are queued and
+// processed by a pool of workers, every attempt is recorded in `webhook_deliveries`
+type Dispatcher struct {
+	repo   repositories.WebhookRepository
+	client *http.Client
+	opts   Options
+	queue  chan delivery
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	cacheMu       sync.Mutex
+	cachedHooks   []models.Webhook
+	cacheLoadedAt time.Time
+}
+
+func NewDispatcher(repo repositories.WebhookRepository, opts Options) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: opts.Timeout},
+		opts:   opts,
+		queue:  make(chan delivery, opts.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start spawns the delivery workers
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.opts.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+}
+
+// Stop abandons pending deliveries and waits for in flight attempts to finish
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// OnEventSaved queues a delivery for every active webhook matching the event
+//
+//	Note: It never blocks, deliveries are dropped if the queue is full
+func (d *Dispatcher) OnEventSaved(event models.BridgeEvent) {
+	for _, webhook := range d.activeWebhooks() {
+		if !webhook.Filter().Matches(event) {
+			continue
+		}
+
+		select {
+		case d.queue <- delivery{webhook: webhook, event: event}:
+		default:
+			log.Printf("Webhook queue is full, dropping delivery of event %d to webhook %d", event.ID, webhook.ID)
+		}
+	}
+}
+
+// activeWebhooks returns active webhooks, cached for `CacheTTL`
+// so every consumed event doesn't hit the database
+func (d *Dispatcher) activeWebhooks() []models.Webhook {
+	d.cacheMu.Lock()
+	defer d.cacheMu.Unlock()
+
+	if time.Since(d.cacheLoadedAt) < d.opts.CacheTTL {
+		return d.cachedHooks
+	}
+
+	webhooks, err := d.repo.ListActive()
+	if err != nil {
+		// Keep serving stale webhooks rather than dropping deliveries
+		log.Printf("Error loading active webhooks: %v", err)
+		return d.cachedHooks
+	}
+
+	d.cachedHooks = webhooks
+	d.cacheLoadedAt = time.Now()
+	return webhooks
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case job := <-d.queue:
+			d.deliver(job)
+		}
+	}
+}
+
+// deliver attempts the delivery with exponential backoff, once all attempts fail
+// the webhook's failure counter is incremented, which eventually disables it
+func (d *Dispatcher) deliver(job delivery) {
+	body, err := json.Marshal(Payload{
+		Type:      EventTypeBridgeEventCreated,
+		CreatedAt: time.Now().UTC(),
+		Data:      job.event,
+	})
+	if err != nil {
+		log.Printf("Error encoding webhook payload for event %d: %v", job.event.ID, err)
+		return
+	}
+
+	backoff := d.opts.InitialBackoff
+	for attempt := 1; attempt <= d.opts.MaxAttempts; attempt++ {
+		if d.attempt(job, body, attempt) {
+			if err := d.repo.MarkSuccess(job.webhook.ID); err != nil {
+				log.Printf("Error resetting failures of webhook %d: %v", job.webhook.ID, err)
+			}
+			return
+		}
+
+		if attempt == d.opts.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+
+	disabled, err := d.repo.MarkFailure(job.webhook.ID, d.opts.DisableAfter)
+	if err != nil {
+		log.Printf("Error recording failure of webhook %d: %v", job.webhook.ID, err)
+		return
+	}
+	if disabled {
+		log.Printf("Webhook %d disabled after %d consecutive failed deliveries", job.webhook.ID, d.opts.DisableAfter)
+	}
+}
+
+// attempt POSTs the signed payload once and records the outcome, reports success
+func (d *Dispatcher) attempt(job delivery, body []byte, attempt int) bool {
+	record := &models.WebhookDelivery{
+		WebhookID: job.webhook.ID,
+		EventID:   job.event.ID,
+		Attempt:   attempt,
+	}
+
+	start := time.Now()
+	statusCode, err := d.post(job.webhook, body, attempt)
+	record.DurationMs = time.Since(start).Milliseconds()
+	record.StatusCode = statusCode
+
+	switch {
+	case err != nil:
+		record.Error = err.Error()
+	case statusCode < 200 || statusCode >= 300:
+		record.Error = fmt.Sprintf("unexpected status code %d", statusCode)
+	default:
+		record.Success = true
+	}
+
+	if err := d.repo.RecordDelivery(record); err != nil {
+		log.Printf("Error recording delivery of event %d to webhook %d: %v", job.event.ID, job.webhook.ID, err)
+	}
+
+	return record.Success
+}
+
+func (d *Dispatcher) post(webhook models.Webhook, body []byte, attempt int) (int, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "eth-bridging-webhooks/1.0")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, body))
+	req.Header.Set("X-Bridge-Event-Type", EventTypeBridgeEventCreated)
+	req.Header.Set("X-Bridge-Delivery-Attempt", strconv.Itoa(attempt))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body, so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for the body, receivers verify it by
+// computing HMAC-SHA256 of `<timestamp>.<body>` with the shared secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,165 @@
+package webhooks_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(webhook *models.Webhook) error {
+	return m.Called(webhook).Error(0)
+}
+
+func (m *MockWebhookRepository) GetByID(id int) (*models.Webhook, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) List() ([]models.Webhook, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListActive() ([]models.Webhook, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(webhook *models.Webhook) error {
+	return m.Called(webhook).Error(0)
+}
+
+func (m *MockWebhookRepository) Delete(id int) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockWebhookRepository) RecordDelivery(delivery *models.WebhookDelivery) error {
+	return m.Called(delivery).Error(0)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(webhookID int, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(webhookID, limit)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkSuccess(id int) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockWebhookRepository) MarkFailure(id int, disableAfter int) (bool, error) {
+	args := m.Called(id, disableAfter)
+	return args.Bool(0), args.Error(1)
+}
+
+func testOptions() webhooks.Options {
+	opts := webhooks.DefaultOptions
+	opts.Workers = 1
+	opts.MaxAttempts = 3
+	opts.InitialBackoff = time.Millisecond
+	return opts
+}
+
+func TestDispatcher_SignsAndRetriesDelivery(t *testing.T) {
+	var calls int32
+	received := make(chan webhooks.Payload, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// First attempt fails, so the delivery must be retried
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		expected := webhooks.Sign("s3cr3t", r.Header.Get(webhooks.TimestampHeader), body)
+		if r.Header.Get(webhooks.SignatureHeader) != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var payload webhooks.Payload
+		json.Unmarshal(body, &payload)
+		received <- payload
+	}))
+	defer server.Close()
+
+	repo := new(MockWebhookRepository)
+	repo.On("ListActive").Return([]models.Webhook{
+		{ID: 1, URL: server.URL, Secret: "s3cr3t", Active: true},
+		// Filtered out, event goes to a different chain
+		{ID: 2, URL: server.URL, Secret: "other", Active: true, DestChainID: "137"},
+	}, nil)
+	repo.On("RecordDelivery", mock.Anything).Return(nil)
+	done := make(chan struct{})
+	repo.On("MarkSuccess", 1).Return(nil).Run(func(mock.Arguments) {
+		close(done)
+	})
+
+	dispatcher := webhooks.NewDispatcher(repo, testOptions())
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	dispatcher.OnEventSaved(models.BridgeEvent{ID: 42, DestChainID: "10", Amount: "1000"})
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, webhooks.EventTypeBridgeEventCreated, payload.Type)
+		assert.Equal(t, 42, payload.Data.ID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("success was not recorded")
+	}
+
+	repo.AssertNumberOfCalls(t, "RecordDelivery", 2)
+}
+
+func TestDispatcher_MarksFailureAfterAllAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	done := make(chan struct{})
+
+	repo := new(MockWebhookRepository)
+	repo.On("ListActive").Return([]models.Webhook{{ID: 7, URL: server.URL, Secret: "s3cr3t", Active: true}}, nil)
+	repo.On("RecordDelivery", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return !d.Success && d.StatusCode == http.StatusInternalServerError
+	})).Return(nil)
+	repo.On("MarkFailure", 7, webhooks.DefaultOptions.DisableAfter).Return(true, nil).Run(func(mock.Arguments) {
+		close(done)
+	})
+
+	dispatcher := webhooks.NewDispatcher(repo, testOptions())
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	dispatcher.OnEventSaved(models.BridgeEvent{ID: 1})
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("failure was not recorded")
+	}
+
+	repo.AssertNumberOfCalls(t, "RecordDelivery", 3)
+	repo.AssertNotCalled(t, "MarkSuccess", mock.Anything)
+}
@@ -0,0 +1,93 @@
+// Package decimal renders integer token amounts as exact decimal strings
+//
+// On chain amounts are integers in the token's smallest unit, a token with
+// 6 decimals stores 1.5 as 1500000. Everything here uses big.Int arithmetic,
+// so amounts of any size (up to uint256 and beyond) are converted without loss
+package decimal
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidAmount is returned when an amount can't be parsed
+var ErrInvalidAmount = errors.New("invalid decimal amount")
+
+// Format renders raw scaled down by 10^decimals with exactly `decimals` fractional digits,
+// e.g. Format(1500000, 6) is "1.500000"
+func Format(raw *big.Int, decimals uint) string {
+	if decimals == 0 {
+		return raw.String()
+	}
+
+	sign := ""
+	digits := raw.String()
+	if raw.Sign() < 0 {
+		sign = "-"
+		digits = digits[1:]
+	}
+
+	// Left pad so there is at least one digit before the point
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	point := len(digits) - int(decimals)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// FormatString is Format for a base 10 integer string
+func FormatString(raw string, decimals uint) (string, error) {
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return "", ErrInvalidAmount
+	}
+
+	return Format(amount, decimals), nil
+}
+
+// Trim removes trailing zeros of the fractional part along with a dangling point,
+// e.g. "1.500000" is "1.5" and "2.000" is "2"
+func Trim(formatted string) string {
+	if !strings.Contains(formatted, ".") {
+		return formatted
+	}
+
+	return strings.TrimSuffix(strings.TrimRight(formatted, "0"), ".")
+}
+
+// Parse is the inverse of Format, it converts a decimal string to an integer amount
+// with `decimals` decimals, e.g. Parse("1.5", 6) is 1500000.
+//
+// Amounts with more fractional digits than `decimals` are rejected rather than rounded
+func Parse(formatted string, decimals uint) (*big.Int, error) {
+	whole, fraction, _ := strings.Cut(formatted, ".")
+
+	digits := strings.TrimPrefix(strings.TrimPrefix(whole, "-"), "+")
+	if digits == "" && fraction == "" {
+		return nil, ErrInvalidAmount
+	}
+	if len(fraction) > int(decimals) {
+		if strings.TrimRight(fraction[decimals:], "0") != "" {
+			return nil, ErrInvalidAmount
+		}
+		fraction = fraction[:decimals]
+	}
+
+	// Both parts must be plain digits, SetString would accept e.g. underscores and signs
+	for _, part := range []string{digits, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return nil, ErrInvalidAmount
+			}
+		}
+	}
+
+	amount, ok := new(big.Int).SetString(whole[:len(whole)-len(digits)]+digits+fraction+strings.Repeat("0", int(decimals)-len(fraction)), 10)
+	if !ok {
+		return nil, ErrInvalidAmount
+	}
+
+	return amount, nil
+}
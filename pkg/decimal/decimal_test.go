@@ -0,0 +1,142 @@
+package decimal
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		raw      string
+		decimals uint
+		expected string
+	}{
+		{"1372483935", 6, "1372.483935"},
+		{"1500000", 6, "1.500000"},
+		{"1", 18, "0.000000000000000001"},
+		{"0", 6, "0.000000"},
+		{"42", 0, "42"},
+		{"-1500", 3, "-1.500"},
+		{"-1", 2, "-0.01"},
+		// Max uint256, far beyond what float64 holds exactly
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639935", 18, "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+	}
+
+	for _, c := range cases {
+		formatted, err := FormatString(c.raw, c.decimals)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, formatted, "Format(%s, %d)", c.raw, c.decimals)
+	}
+
+	_, err := FormatString("12.5", 6)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestTrim(t *testing.T) {
+	assert.Equal(t, "1.5", Trim("1.500000"))
+	assert.Equal(t, "2", Trim("2.000"))
+	assert.Equal(t, "100", Trim("100"))
+	assert.Equal(t, "0", Trim("0.000000"))
+}
+
+func TestParse(t *testing.T) {
+	amount, err := Parse("1.5", 6)
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000", amount.String())
+
+	amount, err = Parse("-0.01", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "-1", amount.String())
+
+	// Trailing zeros beyond the decimals are harmless
+	amount, err = Parse("3.1400", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "314", amount.String())
+
+	for _, invalid := range []string{"", ".", "1.234", "1e5", "1_000", "abc", "1.-5"} {
+		_, err := Parse(invalid, 2)
+		assert.ErrorIs(t, err, ErrInvalidAmount, invalid)
+	}
+}
+
+// FuzzFormat checks Format against big.Rat and that Parse reverses it
+func FuzzFormat(f *testing.F) {
+	f.Add("1372483935", uint8(6))
+	f.Add("1", uint8(18))
+	f.Add("-987654321987654321", uint8(9))
+	f.Add("0", uint8(0))
+
+	f.Fuzz(func(t *testing.T, raw string, decimals uint8) {
+		amount, ok := new(big.Int).SetString(raw, 10)
+		if !ok {
+			t.Skip()
+		}
+
+		formatted := Format(amount, uint(decimals))
+
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+		expected := new(big.Rat).SetFrac(amount, divisor).FloatString(int(decimals))
+		if formatted != expected {
+			t.Fatalf("Format(%s, %d) = %s, expected %s", raw, decimals, formatted, expected)
+		}
+
+		parsed, err := Parse(formatted, uint(decimals))
+		if err != nil || parsed.Cmp(amount) != 0 {
+			t.Fatalf("Parse(%s, %d) = %v, %v, expected %s", formatted, decimals, parsed, err, amount)
+		}
+	})
+}
+
+// FuzzFormatPostgres checks Format against postgres `NUMERIC`, set `TEST_DATABASE_URL` to run it.
+//
+// The amount is scaled by multiplying with a negative power of ten, which unlike division
+// is exact in postgres, and rounded to `decimals` to fix the scale of the text output
+func FuzzFormatPostgres(f *testing.F) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		f.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		f.Skipf("Failed to open database: %v", err)
+	}
+	f.Cleanup(func() { db.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		f.Skipf("Database is not reachable: %v", err)
+	}
+
+	f.Add("1372483935", uint8(6))
+	f.Add("3482483968499194", uint8(18))
+	f.Add("115792089237316195423570985008687907853269984665640564039457584007913129639935", uint8(18))
+	f.Add("123456789012345678901234567890123", uint8(18))
+
+	f.Fuzz(func(t *testing.T, raw string, decimals uint8) {
+		amount, ok := new(big.Int).SetString(raw, 10)
+		// NUMERIC(78, 0) holds any uint256
+		if !ok || amount.Sign() < 0 || len(amount.String()) > 78 || decimals > 36 {
+			t.Skip()
+		}
+
+		var expected string
+		err := db.QueryRow("SELECT round($1::numeric * power(10::numeric, -$2::int), $2::int)::text", amount.String(), int(decimals)).Scan(&expected)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+
+		formatted := Format(amount, uint(decimals))
+		if formatted != expected {
+			t.Fatalf("Format(%s, %d) = %s, postgres %s", amount, decimals, formatted, expected)
+		}
+	})
+}
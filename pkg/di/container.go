@@ -3,12 +3,21 @@ package di
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/alerts"
+	"github.com/eth-bridging/internal/broadcast"
 	"github.com/eth-bridging/internal/consumer"
+	"github.com/eth-bridging/internal/coverage"
+	"github.com/eth-bridging/internal/envelope"
+	"github.com/eth-bridging/internal/maintenance"
+	"github.com/eth-bridging/internal/pricing"
 	"github.com/eth-bridging/internal/producer"
 	"github.com/eth-bridging/internal/repositories"
 	"github.com/eth-bridging/internal/services"
+	"github.com/eth-bridging/internal/transfers"
+	"github.com/eth-bridging/internal/webhooks"
 	ethereum "github.com/eth-bridging/pkg/go-eth"
 
 	"github.com/go-redis/redis/v8"
@@ -16,12 +25,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// streamBufferSize is the number of events buffered per live stream subscriber
+// before it's considered too slow and dropped
+const streamBufferSize = 256
+
 // Container holds all dependencies for the app
 // The purpose of Container is to ensure Dependency Injection(DI)
 type Container struct {
-	EventService services.BridgeEventService
-	Consumer     consumer.RedisStreamConsumer
-	Producer     producer.RedisProducer
+	Config            *config.Config
+	EventService      services.BridgeEventService
+	StatsService      services.BridgeStatsService
+	WebhookService    services.WebhookService
+	AlertService      services.AlertService
+	ArchiveService    services.ArchiveService
+	BridgeNameService services.BridgeNameService
+	TransferService   services.TransferService
+	Hub               *broadcast.Hub
+	Dispatcher        *webhooks.Dispatcher
+	AlertEngine       *alerts.Engine
+	Partitions        *maintenance.PartitionMaintainer
+	Endpoints         *ethereum.EndpointPool
+	Transfers         *transfers.Tracker
+	SLAMonitor        *transfers.SLAMonitor
+	Lifecycle         *transfers.Lifecycle
+	Coverage          *coverage.Monitor
+	Consumer          consumer.RedisStreamConsumer
+	Producer          producer.RedisProducer
 }
 
 // InitializeContainer initializes the components of the application, including
@@ -33,12 +62,9 @@ type Container struct {
 //
 //	Note:
 //	  Ideally in production, consumer should run as a separate microservice, for simplicity, we are clubbing both in a single service
-func InitializeContainer(cfg *config.Config, ethClient *ethereum.EthereumClient, wg *sync.WaitGroup) *Container {
+func InitializeContainer(cfg *config.Config, ethClient *ethereum.EthereumClient, tracked *transfers.Spec, destinations []transfers.Destination, wg *sync.WaitGroup) *Container {
 	// Initialize PostgreSQL
-	db, err := gorm.Open(postgres.Open(cfg.PostgresURL), &gorm.Config{})
-	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
-	}
+	db := OpenDatabase(cfg)
 
 	// Initialize Redis
 	redisClient := redis.NewClient(&redis.Options{
@@ -47,9 +73,132 @@ func InitializeContainer(cfg *config.Config, ethClient *ethereum.EthereumClient,
 
 	// Initialize Repository
 	eventRepo := repositories.NewBridgeEventRepository(db, cfg)
+	statsRepo := repositories.NewBridgeStatsRepository(db, cfg)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	alertRepo := repositories.NewAlertRepository(db)
+	archiveRepo := repositories.NewArchiveRepository(db)
+	partitionRepo := repositories.NewPartitionRepository(db)
+	priceRepo := repositories.NewPriceRepository(db)
+	bridgeNameRepo := repositories.NewBridgeNameRepository(db)
+	transferRepo := repositories.NewTransferRepository(db)
+	slaRepo := repositories.NewSLARepository(db)
+	lifecycleRepo := repositories.NewLifecycleRepository(db)
+	coverageRepo := repositories.NewCoverageRepository(db)
+
+	// Initialize USD price source, events are valued with it as they're saved
+	prices, err := pricing.NewPriceProvider(cfg, priceRepo)
+	if err != nil {
+		log.Fatalf("Failed to initialize price source: %v", err)
+	}
+
+	// Events are read with a log subscription or by polling, see INGEST_MODE
+	pollOptions := ethereum.PollerOptions{
+		Interval:      time.Duration(cfg.PollIntervalSeconds) * time.Second,
+		Confirmations: uint64(cfg.PollConfirmations),
+		MaxRange:      uint64(cfg.PollMaxRange),
+		StartBlock:    uint64(cfg.PollStartBlock),
+	}
+	ingester, err := ethereum.NewIngester(ethClient, cfg.IngestMode, pollOptions)
+	if err != nil {
+		log.Fatalf("Failed to initialize event ingestion: %v", err)
+	}
 
 	// Initialize Service
-	eventService := services.NewBridgeEventService(eventRepo, ethClient)
+	eventService := services.NewBridgeEventService(eventRepo, ingester, prices, cfg)
+	statsService := services.NewBridgeStatsService(statsRepo)
+	webhookService := services.NewWebhookService(webhookRepo)
+	archiveService := services.NewArchiveService(archiveRepo)
+	bridgeNameService := services.NewBridgeNameService(bridgeNameRepo)
+	transferService := services.NewTransferService(transferRepo, slaRepo, lifecycleRepo, tracked.Bridges)
+
+	// Initialize alert notifiers, rules pick one of these by name
+	notifiers := []alerts.Notifier{
+		alerts.LogNotifier{},
+		alerts.NewWebhookNotifier(10 * time.Second),
+		&alerts.EmailNotifier{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.SMTPFrom,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		},
+	}
+	notifierNames := make([]string, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		notifierNames = append(notifierNames, notifier.Name())
+	}
+	alertService := services.NewAlertService(alertRepo, notifierNames)
+
+	// Initialize live stream hub, fed by the consumer once events are saved
+	hub := broadcast.NewHub(streamBufferSize)
+
+	// Initialize webhook dispatcher, fed by the consumer once events are saved
+	dispatcher := webhooks.NewDispatcher(webhookRepo, webhooks.DefaultOptions)
+	dispatcher.Start()
+
+	// Initialize alert engine, evaluates rules on every saved event
+	alertEngine := alerts.NewEngine(alertRepo, notifiers...)
+	alertEngine.Start()
+
+	// Keep monthly bridge_events partitions created ahead of time
+	partitions := maintenance.NewPartitionMaintainer(partitionRepo)
+	partitions.Start()
+
+	// Initialize Redis Stream Producer, the consumer reads events of any encoding
+	codec, err := envelope.CodecByName(cfg.StreamEncoding)
+	if err != nil {
+		log.Fatalf("Failed to initialize stream encoding: %v", err)
+	}
+	streamProducer := producer.NewRedisProducer(redisClient, cfg.RedisStreamName, cfg.StreamProducerID, codec, wg)
+
+	// Record the block ranges ingested on every chain, gaps between them are backfilled. Clients
+	// record their coverage from the moment ingestion starts so this comes first
+	chains := []coverage.Chain{{ChainID: cfg.SourceChainID, Client: ethClient, Producer: streamProducer}}
+	for _, destination := range destinations {
+		chainID := destination.Chain.ChainID
+		chains = append(chains, coverage.Chain{
+			ChainID:  chainID,
+			Client:   destination.Client,
+			Producer: transfers.NewRecorder(chainID, tracked, transferRepo),
+		})
+	}
+	coverageMonitor := coverage.NewMonitor(coverageRepo, chains, coverage.Options{
+		Interval:      time.Duration(cfg.CoverageCheckIntervalSeconds) * time.Second,
+		BackfillAfter: time.Duration(cfg.CoverageBackfillAfterMinutes) * time.Minute,
+		RangeSize:     uint64(cfg.PollMaxRange),
+	})
+	ethClient.TrackCoverage(coverageMonitor.Recorder(cfg.SourceChainID))
+	for _, destination := range destinations {
+		destination.Client.TrackCoverage(coverageMonitor.Recorder(destination.Chain.ChainID))
+	}
+	coverageMonitor.Start()
+
+	// Ingest fills of tracked bridges on their destination chains and match them to their transfers
+	tracker, err := transfers.NewTracker(destinations, tracked, transferRepo, cfg.IngestMode, pollOptions, transfers.MatcherOptions{
+		Interval:  time.Duration(cfg.TransferMatchIntervalSeconds) * time.Second,
+		RetryFor:  time.Duration(cfg.TransferMatchRetryMinutes) * time.Minute,
+		BatchSize: transfers.DefaultMatcherOptions.BatchSize,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize transfer tracking: %v", err)
+	}
+	tracker.Start()
+
+	// Flag transfers not completed within the SLA of their route
+	slaMonitor := transfers.NewSLAMonitor(slaRepo, tracked, transfers.SLAOptions{
+		Interval:      time.Duration(cfg.SLACheckIntervalSeconds) * time.Second,
+		Lookback:      time.Duration(cfg.SLALookbackHours) * time.Hour,
+		MetricsWindow: time.Duration(cfg.SLAMetricsWindowHours) * time.Hour,
+	})
+	slaMonitor.Start()
+
+	// Move transfers through their lifecycle as their source blocks are confirmed and finalized
+	lifecycle := transfers.NewLifecycle(lifecycleRepo, transferRepo, ethClient.Endpoints(), transfers.LifecycleOptions{
+		Interval:      time.Duration(cfg.LifecycleIntervalSeconds) * time.Second,
+		Confirmations: uint64(cfg.LifecycleConfirmations),
+		Lookback:      time.Duration(cfg.LifecycleLookbackHours) * time.Hour,
+		BatchSize:     transfers.DefaultLifecycleOptions.BatchSize,
+	})
+	lifecycle.Start()
 
 	// Initialize Redis Stream Consumer
 	input := &consumer.NewConsumerInput{
@@ -60,6 +209,7 @@ func InitializeContainer(cfg *config.Config, ethClient *ethereum.EthereumClient,
 		Service:    eventService,
 		Wg:         wg,
 		Cfg:        cfg,
+		Listeners:  []consumer.EventListener{hub, dispatcher, alertEngine},
 	}
 	streamConsumer := consumer.NewRedisStreamConsumer(input)
 
@@ -67,17 +217,41 @@ func InitializeContainer(cfg *config.Config, ethClient *ethereum.EthereumClient,
 	wg.Add(1)
 	go streamConsumer.Consume()
 
-	// Initialize Redis Stream Producer
-	streamProducer := producer.NewRedisProducer(redisClient, cfg.RedisStreamName, wg)
-
 	// Start processing the incoming bridging events
 	// Ideally, in production, processing should be a separate microservice
 	wg.Add(1)
 	go eventService.ProcessIncomingBridgeEvents(streamProducer)
 
 	return &Container{
-		EventService: eventService,
-		Consumer:     *streamConsumer,
-		Producer:     *streamProducer,
+		Config:            cfg,
+		EventService:      eventService,
+		StatsService:      statsService,
+		WebhookService:    webhookService,
+		AlertService:      alertService,
+		ArchiveService:    archiveService,
+		BridgeNameService: bridgeNameService,
+		TransferService:   transferService,
+		Hub:               hub,
+		Dispatcher:        dispatcher,
+		AlertEngine:       alertEngine,
+		Partitions:        partitions,
+		Endpoints:         ethClient.Endpoints(),
+		Transfers:         tracker,
+		SLAMonitor:        slaMonitor,
+		Lifecycle:         lifecycle,
+		Coverage:          coverageMonitor,
+		Consumer:          *streamConsumer,
+		Producer:          *streamProducer,
+	}
+}
+
+// OpenDatabase connects to PostgreSQL using `DATABASE_URL`,
+// it's shared by the server and one off commands
+func OpenDatabase(cfg *config.Config) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(cfg.PostgresURL), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
 	}
+
+	return db
 }
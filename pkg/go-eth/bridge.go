@@ -14,8 +14,16 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// resubscribeBackoff is the wait before subscribing again when no endpoint accepts the subscription
+	resubscribeBackoff = 5 * time.Second
+	// blockTimeCacheSize is the number of block times kept, live logs and backfills
+	// read different blocks at the same time
+	blockTimeCacheSize = 256
 )
 
 // Event data structure
@@ -36,119 +44,375 @@ type EthereumClientInterface interface {
 
 // Ethereum client wrapper
 type EthereumClient struct {
-	client  *ethclient.Client
-	address common.Address
-	topic   common.Hash
-	abi     abi.ABI
+	endpoints *EndpointPool
+	addresses []common.Address
+	topics    []common.Hash
+	decoders  *DecoderRegistry
+
+	receipts *ReceiptEnricher
+	// coverage is told about the blocks whose logs were all published, nil if it isn't tracked
+	coverage CoverageRecorder
+	// backfillRange is the number of blocks read at once when backfilling after resubscribing
+	backfillRange uint64
+
+	// Times of recently seen blocks, logs of a block arrive together so this
+	// saves a header request for all but the first log of a block
+	blockTimes *lru.Cache[uint64, time.Time]
 }
 
-// NewEthereumClient initializes the Ethereum client with parsed ABI interface
-func NewEthereumClient(url, contractAddress, contractABI, topicHex string) (*EthereumClient, error) {
-	client, err := ethclient.Dial(url)
+// NewEthereumClient initializes the Ethereum client watching the contracts for events with the
+// topics, given as hashes or event names, or every event with a decoder when there are none.
+// Events are looked up in the ABIs, calls go to the healthiest of the endpoints and
+// bridge names of SocketBridge events are decoded using bridgeNames
+func NewEthereumClient(endpoints *EndpointPool, contractAddresses, contractABIs, topics []string, bridgeNames *BridgeNameDecoder) (*EthereumClient, error) {
+	decoders, err := NewDecoderRegistry(contractABIs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to the Ethereum client: %w", err)
+		return nil, err
+	}
+	if err := decoders.Register(SocketBridgeEvent, newSocketBridgeDecoder(bridgeNames)); err != nil {
+		return nil, err
 	}
 
-	address := common.HexToAddress(contractAddress)
-	socketTopicHash := common.HexToHash(topicHex)
+	return newEthereumClient(endpoints, contractAddresses, topics, decoders)
+}
 
-	parsedABI, err := abi.JSON(strings.NewReader(contractABI))
+// NewEventClient initializes a client watching the contracts for events with the topics,
+// which are only decoded into their payload, e.g. events of bridges on destination chains
+func NewEventClient(endpoints *EndpointPool, contractAddresses, contractABIs, topics []string) (*EthereumClient, error) {
+	decoders, err := NewDecoderRegistry(contractABIs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse contract ABI: %w", err)
+		return nil, err
+	}
+
+	return newEthereumClient(endpoints, contractAddresses, topics, decoders)
+}
+
+func newEthereumClient(endpoints *EndpointPool, contractAddresses, topics []string, decoders *DecoderRegistry) (*EthereumClient, error) {
+	// Events without typed columns are only kept as their payload
+	decoders.RegisterDefault(decodeGenericEvent)
+
+	watched, err := decoders.ResolveTopics(topics)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]common.Address, 0, len(contractAddresses))
+	for _, address := range contractAddresses {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, common.HexToAddress(address))
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, errors.New("no contract addresses to watch")
 	}
 
 	return &EthereumClient{
-		client:  client,
-		address: address,
-		topic:   socketTopicHash,
-		abi:     parsedABI,
+		endpoints: endpoints,
+		addresses: addresses,
+		topics:    watched,
+		decoders:  decoders,
+
+		receipts:      NewReceiptEnricher(endpoints, receiptCacheSize),
+		backfillRange: DefaultPollerOptions.MaxRange,
+		blockTimes:    lru.NewCache[uint64, time.Time](blockTimeCacheSize),
 	}, nil
 }
 
+// Endpoints returns the RPC endpoints the client reads from
+func (ec *EthereumClient) Endpoints() *EndpointPool {
+	return ec.endpoints
+}
+
 // StartBridgingEventPublisher listens for bridging events from an Ethereum contract
 // and publishes them to the Redis stream via the provided streamProducer.
 // It subscribes to Ethereum logs, decodes the events, and processes each
 // bridging event by publishing it to Redis.
 // This way, even if something fails during consuming, the messages can be retried as it's queue based.
+//
+// The subscription moves to the next healthiest endpoint when it fails or its endpoint
+// turns unhealthy, logs since the last processed head are backfilled so none are missed
+// while switching. It only returns once ctx is done
 func (ec *EthereumClient) StartBridgingEventPublisher(ctx context.Context, streamProducer producer.Producer) error {
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{ec.address},
-		Topics:    [][]common.Hash{{ec.topic}},
-	}
+	query := ec.filterQuery()
 
-	// Channel to receive results of the streaming filter query
-	logs := make(chan types.Log)
+	// Channel to receive results of the streaming filter query, buffered so
+	// logs of a block can be picked up together, see below
+	logs := make(chan types.Log, receiptBatchSize)
 
-	// Subscribe to the logs with the filter query
-	sub, err := ec.client.SubscribeFilterLogs(ctx, query, logs)
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to contract events: %w", err)
+	// processed is the last block whose logs were all delivered, 0 until a head was seen
+	var processed uint64
+	for {
+		// Subscribe to the logs with the filter query
+		sub, ep, err := ec.endpoints.subscribeFilterLogs(ctx, query, logs)
+		if err != nil {
+			log.Printf("Failed to subscribe to contract events, retrying: %v", err)
+			if !sleepContext(ctx, resubscribeBackoff) {
+				return ctx.Err()
+			}
+			continue
+		}
+		log.Printf("Subscribed to contract events on %s", ep.name)
+
+		// Blocks after the head are delivered by the subscription
+		head, err := ec.endpoints.BlockNumber(ctx)
+		if err != nil {
+			log.Printf("Error fetching head after subscribing: %v", err)
+		}
+
+		// Blocks since the previous subscription, whether they had logs or not.
+		// Redelivered logs are dropped by the consumer, see the `bridge_event_keys` table
+		if processed > 0 && head > processed {
+			if err := ec.Backfill(ctx, processed+1, head, ec.backfillRange, streamProducer); err != nil {
+				log.Printf("Error backfilling blocks %d to %d, they're left as a coverage gap: %v", processed+1, head, err)
+			}
+		}
+
+		live := &liveCoverage{}
+		if head > 0 {
+			live.next, live.seen = head+1, head
+		}
+		err = ec.publishLogs(ctx, sub, ep, logs, streamProducer, live)
+		// Without a head the blocks before subscribing weren't backfilled, they are next time
+		if head > 0 && live.next > 0 {
+			processed = live.next - 1
+		}
+		sub.Unsubscribe()
+		ec.endpoints.unsubscribed(ep, err)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("Moving subscription off %s: %v", ep.name, err)
 	}
-	defer sub.Unsubscribe()
+}
+
+// publishLogs publishes logs of the subscription until it fails, ctx is done or ep turns unhealthy,
+// live follows the blocks whose logs were delivered
+func (ec *EthereumClient) publishLogs(ctx context.Context, sub ethereum.Subscription, ep *endpoint, logs chan types.Log, streamProducer producer.Producer, live *liveCoverage) error {
+	healthCheck := time.NewTicker(ec.endpoints.opts.CheckInterval)
+	defer healthCheck.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			return ctx.Err()
 		case err := <-sub.Err():
-			// Error while subscribing, log and return the error
+			// Error while subscribing, move on to another endpoint
 			return fmt.Errorf("error while subscribing to logs: %w", err)
+		case <-healthCheck.C:
+			if ec.endpoints.shouldFailOver(ep) {
+				return errors.New("endpoint is unhealthy")
+			}
+			live.advance(ctx, ec)
 		case vLog := <-logs:
-			// Decode the log into a bridging event
-			// If decoding fails, skip the log and continue as other logs might not be failing
-			// Create the BridgeEvent struct
-			// Publish the event to the Redis stream
-			err := ec.handleFilterLog(vLog, streamProducer)
-			// incase error happens while handling a log stream
-			// move to next log stream
-			if err != nil {
-				continue
+			// Logs of a block are delivered together, take the ones already waiting
+			// as well, so their receipts are fetched in a single batch
+			vLogs := []types.Log{vLog}
+		drain:
+			for len(vLogs) < receiptBatchSize {
+				select {
+				case next := <-logs:
+					vLogs = append(vLogs, next)
+				default:
+					break drain
+				}
+			}
+
+			if err := ec.handleFilterLogs(ctx, vLogs, streamProducer, false); err != nil {
+				log.Printf("Error handling logs of block %d: %v", vLogs[len(vLogs)-1].BlockNumber, err)
+				live.fail(vLogs[len(vLogs)-1].BlockNumber)
 			}
 		}
 	}
 }
 
-// handleFilterLog decodes the log data from streaming filter query to a BridgingEvent struct.
-// if successful, then it will publish an event to provided redis stream
-// returns the error if any of these two steps fails
-func (ec *EthereumClient) handleFilterLog(vLog types.Log, streamProducer producer.Producer) error {
-	// Decode vLog into BridgingEvent using ABI
-	bridgingEvent, err := decodeSocketBridgeEvent(ec.abi, vLog)
-	if err != nil || bridgingEvent == nil {
+// filterQuery matches the watched events of any of the contracts
+func (ec *EthereumClient) filterQuery() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: ec.addresses,
+		Topics:    [][]common.Hash{ec.topics},
+	}
+}
+
+// handleLogBatches handles logs fetched in bulk, in batches as large as a receipt batch,
+// returns the errors of every batch, see handleFilterLogs
+func (ec *EthereumClient) handleLogBatches(ctx context.Context, vLogs []types.Log, streamProducer producer.Producer, replay bool) error {
+	var errs []error
+	for start := 0; start < len(vLogs); start += receiptBatchSize {
+		end := min(start+receiptBatchSize, len(vLogs))
+		if err := ec.handleFilterLogs(ctx, vLogs[start:end], streamProducer, replay); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleFilterLogs decodes the logs, enriches them with their transaction receipts
+// and publishes them to the provided redis stream.
+//
+// Logs failing to decode or publish are skipped, as other logs might not be failing,
+// the returned error counts them so their blocks aren't recorded as covered.
+// replay is set for logs of past blocks, see decodeFilterLog
+func (ec *EthereumClient) handleFilterLogs(ctx context.Context, vLogs []types.Log, streamProducer producer.Producer, replay bool) error {
+	events, failures := ec.decodeLogs(ctx, vLogs, replay)
+
+	failed := len(failures)
+	for _, event := range events {
+		// Publish Event to redis
+		if err := streamProducer.PublishEvent(*event); err != nil {
+			log.Printf("Error publishing event to Redis, event: %+v error: %+v\n", *event, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d logs failed to decode or publish", failed, len(vLogs))
+	}
+	return nil
+}
+
+// DecodeFailure is a watched log which couldn't be decoded into an event
+type DecodeFailure struct {
+	TransactionHash string
+	LogIndex        uint
+	BlockNumber     uint64
+	Err             error
+}
+
+// FetchEvents reads the watched events of the blocks [from, to] the same way they're ingested,
+// along with the logs failing to decode. It's used to check stored events against the chain,
+// events are timed by their block as they're republished when repairing
+func (ec *EthereumClient) FetchEvents(ctx context.Context, from, to uint64) ([]*models.BridgeEvent, []DecodeFailure, error) {
+	query := ec.filterQuery()
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(to)
+
+	vLogs, err := ec.endpoints.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make([]*models.BridgeEvent, 0, len(vLogs))
+	var failures []DecodeFailure
+	for start := 0; start < len(vLogs); start += receiptBatchSize {
+		end := min(start+receiptBatchSize, len(vLogs))
+		decoded, failed := ec.decodeLogs(ctx, vLogs[start:end], true)
+		events = append(events, decoded...)
+		failures = append(failures, failed...)
+	}
+
+	return events, failures, nil
+}
+
+// decodeLogs decodes the logs and enriches them with their transaction receipts,
+// logs failing to decode are skipped and returned as failures
+func (ec *EthereumClient) decodeLogs(ctx context.Context, vLogs []types.Log, replay bool) ([]*models.BridgeEvent, []DecodeFailure) {
+	events := make([]*models.BridgeEvent, 0, len(vLogs))
+	var failures []DecodeFailure
+	for _, vLog := range vLogs {
+		event, err := ec.decodeFilterLog(ctx, vLog, replay)
+		if err != nil {
+			failures = append(failures, DecodeFailure{
+				TransactionHash: vLog.TxHash.Hex(),
+				LogIndex:        vLog.Index,
+				BlockNumber:     vLog.BlockNumber,
+				Err:             err,
+			})
+			continue
+		}
+		events = append(events, event)
+	}
+
+	ec.receipts.Enrich(ctx, events)
+	return events, failures
+}
+
+// decodeFilterLog decodes the log data from streaming filter query to a BridgeEvent
+// with the decoder registered for its topic, returns the error if it can't be decoded.
+//
+// Events are timed when they're ingested, replayed logs of past blocks are timed by their
+// block instead, so they don't all land at the time of the replay
+func (ec *EthereumClient) decodeFilterLog(ctx context.Context, vLog types.Log, replay bool) (*models.BridgeEvent, error) {
+	bridgeEvent, err := ec.decoders.Decode(vLog)
+	if err != nil {
 		errMessage := fmt.Sprintf("Error decoding event: %+v, log: %+v\n", err, vLog)
 		log.Println(errMessage)
-		return errors.New(errMessage)
+		return nil, errors.New(errMessage)
 	}
 
-	bridgeEvent := &models.BridgeEvent{
-		TransactionHash: bridgingEvent.TxHash,
-		FromChain:       bridgingEvent.Sender.Hex(),
-		ToChain:         bridgingEvent.Receiver.Hex(),
-		Amount:          fmt.Sprint(bridgingEvent.Amount),
-		Token:           fmt.Sprint(bridgingEvent.Token),
-		Timestamp:       time.Now(),
+	bridgeEvent.TransactionHash = vLog.TxHash.Hex()
+	bridgeEvent.Timestamp = time.Now()
+	bridgeEvent.BlockNumber = vLog.BlockNumber
+	bridgeEvent.LogIndex = vLog.Index
+	bridgeEvent.BlockTimestamp = ec.blockTime(ctx, vLog.BlockNumber)
+	if replay && bridgeEvent.BlockTimestamp != nil {
+		bridgeEvent.Timestamp = *bridgeEvent.BlockTimestamp
 	}
 
-	// Publish Event to redis
-	if err := streamProducer.PublishEvent(*bridgeEvent); err != nil {
-		log.Printf("Error publishing event to Redis, event: %+v error: %+v\n", *bridgeEvent, err)
-		return err
+	return bridgeEvent, nil
+}
+
+// blockTime returns the time of the block, nil if its header can't be fetched.
+// The event is still published then, it's valued at ingestion time instead
+func (ec *EthereumClient) blockTime(ctx context.Context, blockNumber uint64) *time.Time {
+	if blockTime, ok := ec.blockTimes.Get(blockNumber); ok {
+		return &blockTime
 	}
 
-	return nil
+	header, err := ec.endpoints.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		log.Printf("Error fetching header of block %d: %v", blockNumber, err)
+		return nil
+	}
+
+	blockTime := time.Unix(int64(header.Time), 0).UTC()
+	ec.blockTimes.Add(blockNumber, blockTime)
+
+	return &blockTime
 }
 
 // decodeSocketBridgeEvent decodes the log data from streaming filter query into a
-// BridgingEvent struct. It uses the provided ABI to unpack the log data and
-// fills the fields of the BridgingEvent, including the transaction hash.
-func decodeSocketBridgeEvent(parsedABI abi.ABI, vLog types.Log) (*BridgingEvent, error) {
+// BridgingEvent struct. It uses the event's ABI definition to unpack the log data and
+// indexed topics, filling the fields of the BridgingEvent, including the transaction hash.
+func decodeSocketBridgeEvent(event abi.Event, vLog types.Log) (*BridgingEvent, error) {
 	eventData := BridgingEvent{
 		TxHash: vLog.TxHash.Hex(),
 	}
 
-	err := parsedABI.UnpackIntoInterface(&eventData, "SocketBridge", vLog.Data)
+	values, err := event.Inputs.Unpack(vLog.Data)
+	if err == nil {
+		err = event.Inputs.Copy(&eventData, values)
+	}
 	if err != nil {
 		log.Printf("Failed to unpack log data: %v", err)
 		return nil, err
 	}
 
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if len(indexed) > 0 && len(vLog.Topics) > 1 {
+		if err := abi.ParseTopics(&eventData, indexed, vLog.Topics[1:]); err != nil {
+			log.Printf("Failed to parse log topics: %v", err)
+			return nil, err
+		}
+	}
+
 	return &eventData, nil
 }
+
+// sleepContext waits for d, false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,87 @@
+package ethereum
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BridgeNameDecoder turns the bytes32 `bridgeName` of SocketBridge events into a readable name
+//
+// Bridges identify themselves either with a short ASCII string right padded with zeros,
+// e.g. bytes32("hop"), or with the keccak256 hash of their name, which can only be
+// reversed with a list of known names
+type BridgeNameDecoder struct {
+	hashes map[common.Hash]string
+}
+
+// NewBridgeNameDecoder builds a decoder from known bridge names, entries are either a
+// plain name, which is hashed with keccak256, or `0x<hash>=name` for any other hash
+func NewBridgeNameDecoder(names []string) (*BridgeNameDecoder, error) {
+	decoder := &BridgeNameDecoder{hashes: make(map[common.Hash]string)}
+
+	for _, entry := range names {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		hash, name, mapped := strings.Cut(entry, "=")
+		if !mapped {
+			decoder.hashes[crypto.Keccak256Hash([]byte(entry))] = entry
+			continue
+		}
+
+		raw, err := hexutil.Decode(hash)
+		if err != nil || len(raw) != common.HashLength || name == "" {
+			return nil, fmt.Errorf("invalid bridge name mapping %q, must be 0x<32 byte hash>=name", entry)
+		}
+		decoder.hashes[common.BytesToHash(raw)] = name
+	}
+
+	return decoder, nil
+}
+
+// Decode returns the name of the bridge, known is false when the value is neither
+// a known hash nor ASCII, the raw value is returned as hex then
+func (d *BridgeNameDecoder) Decode(raw [32]byte) (name string, known bool) {
+	if name, ok := d.hashes[raw]; ok {
+		return name, true
+	}
+	if name, ok := asciiBridgeName(raw); ok {
+		return name, true
+	}
+
+	return hexutil.Encode(raw[:]), false
+}
+
+// DecodeHex is Decode for a hex encoded value as stored in `bridge_name_raw`
+func (d *BridgeNameDecoder) DecodeHex(rawHex string) (name string, known bool) {
+	raw, err := hexutil.Decode(rawHex)
+	if err != nil || len(raw) != common.HashLength {
+		return rawHex, false
+	}
+
+	return d.Decode(common.BytesToHash(raw))
+}
+
+// asciiBridgeName reads raw as a zero padded string of printable ASCII
+func asciiBridgeName(raw [32]byte) (string, bool) {
+	trimmed := bytes.TrimRight(raw[:], "\x00")
+	if len(trimmed) == 0 {
+		return "", false
+	}
+
+	for _, c := range trimmed {
+		if c < 0x20 || c > 0x7e {
+			return "", false
+		}
+	}
+
+	name := strings.TrimSpace(string(trimmed))
+	return name, name != ""
+}
@@ -0,0 +1,59 @@
+package ethereum
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBridgeNameDecoder_Decode(t *testing.T) {
+	mappedHash := "0x1111111111111111111111111111111111111111111111111111111111111111"
+	decoder, err := NewBridgeNameDecoder([]string{"across", " cctp ", mappedHash + "=stargate"})
+	assert.NoError(t, err)
+
+	var ascii [32]byte
+	copy(ascii[:], "hop")
+
+	var binary [32]byte
+	binary[0], binary[1] = 0x01, 0xff
+
+	unknownHash := crypto.Keccak256Hash([]byte("unknown"))
+
+	cases := []struct {
+		raw   [32]byte
+		name  string
+		known bool
+	}{
+		{raw: ascii, name: "hop", known: true},
+		{raw: crypto.Keccak256Hash([]byte("across")), name: "across", known: true},
+		{raw: crypto.Keccak256Hash([]byte("cctp")), name: "cctp", known: true},
+		{raw: common.HexToHash(mappedHash), name: "stargate", known: true},
+		{raw: unknownHash, name: unknownHash.Hex(), known: false},
+		{raw: binary, name: common.Hash(binary).Hex(), known: false},
+		{raw: [32]byte{}, name: common.Hash{}.Hex(), known: false},
+	}
+
+	for _, c := range cases {
+		name, known := decoder.Decode(c.raw)
+		assert.Equal(t, c.name, name)
+		assert.Equal(t, c.known, known, c.name)
+	}
+
+	// Values stored before the raw column existed are hex as well
+	name, known := decoder.DecodeHex(common.Hash(ascii).Hex())
+	assert.Equal(t, "hop", name)
+	assert.True(t, known)
+
+	name, known = decoder.DecodeHex("hop")
+	assert.Equal(t, "hop", name)
+	assert.False(t, known)
+}
+
+func TestNewBridgeNameDecoder_InvalidMapping(t *testing.T) {
+	for _, entry := range []string{"0x1234=short", "0x1111111111111111111111111111111111111111111111111111111111111111=", "hop=0x12"} {
+		_, err := NewBridgeNameDecoder([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
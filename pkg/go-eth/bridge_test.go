@@ -41,7 +41,7 @@ func TestDecodeSocketBridgeEvent(t *testing.T) {
 
 	sampleLog := createSampleLog()
 
-	event, err := decodeSocketBridgeEvent(parsedABI, sampleLog)
+	event, err := decodeSocketBridgeEvent(parsedABI.Events["SocketBridge"], sampleLog)
 
 	assert.Error(t, err)
 	assert.Nil(t, event)
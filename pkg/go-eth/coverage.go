@@ -0,0 +1,118 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/eth-bridging/internal/producer"
+)
+
+// CoverageRecorder is told about every range of blocks whose logs were all published,
+// so holes left by restarts or dropped subscriptions can be found
+type CoverageRecorder interface {
+	RecordCoverage(from, to uint64)
+	// LastCovered returns the highest block recorded as covered, false when none was
+	LastCovered() (uint64, bool, error)
+}
+
+// TrackCoverage reports the blocks covered by the client, whether subscribed, polling or backfilling, to the recorder
+func (ec *EthereumClient) TrackCoverage(recorder CoverageRecorder) {
+	ec.coverage = recorder
+}
+
+// recordCoverage reports the blocks [from, to] as covered, if coverage is tracked
+func (ec *EthereumClient) recordCoverage(from, to uint64) {
+	if ec.coverage != nil && from <= to {
+		ec.coverage.RecordCoverage(from, to)
+	}
+}
+
+// lastCovered returns the highest block recorded as covered, false when none was or coverage isn't tracked
+func (ec *EthereumClient) lastCovered() (uint64, bool, error) {
+	if ec.coverage == nil {
+		return 0, false, nil
+	}
+	return ec.coverage.LastCovered()
+}
+
+// Backfill publishes logs of the blocks [from, to] in ranges of at most rangeSize blocks, timed by
+// their block, each range is recorded as covered once its logs are all published. Ranges whose logs
+// failed to decode or publish are left uncovered and reported once the rest is backfilled
+func (ec *EthereumClient) Backfill(ctx context.Context, from, to, rangeSize uint64, streamProducer producer.Producer) error {
+	query := ec.filterQuery()
+	var failures []error
+
+	for start := from; start <= to && ctx.Err() == nil; start += rangeSize {
+		end := min(to, start+rangeSize-1)
+		query.FromBlock = new(big.Int).SetUint64(start)
+		query.ToBlock = new(big.Int).SetUint64(end)
+
+		vLogs, err := ec.endpoints.FilterLogs(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to fetch logs of blocks %d to %d: %w", start, end, err)
+		}
+
+		if err := ec.handleLogBatches(ctx, vLogs, streamProducer, true); err != nil {
+			failures = append(failures, fmt.Errorf("failed to publish logs of blocks %d to %d: %w", start, end, err))
+			continue
+		}
+		ec.recordCoverage(start, end)
+		log.Printf("Backfilled %d logs of blocks %d to %d", len(vLogs), start, end)
+	}
+
+	return errors.Join(append(failures, ctx.Err())...)
+}
+
+// liveCoverage follows the blocks covered by a subscription. Blocks up to the head seen at
+// one health check are recorded as covered at the next, by then their logs have been delivered.
+// Blocks before next are where a backfill continues from after resubscribing
+type liveCoverage struct {
+	// next is the first block not recorded yet, 0 until the head is known.
+	// seen is the head at the previous check
+	next uint64
+	seen uint64
+	// failed are blocks not recorded yet whose logs failed to decode or publish
+	failed []uint64
+}
+
+// fail marks the logs of the block as not published, so it isn't recorded as covered
+func (c *liveCoverage) fail(block uint64) {
+	c.failed = append(c.failed, block)
+}
+
+// advance records the blocks up to the previously seen head as covered and remembers the current one
+func (c *liveCoverage) advance(ctx context.Context, ec *EthereumClient) {
+	head, err := ec.endpoints.BlockNumber(ctx)
+	if err != nil {
+		return
+	}
+
+	// The head wasn't known when subscribing, blocks after the current one are covered
+	if c.next == 0 {
+		c.next, c.seen = head+1, head
+		return
+	}
+
+	if c.seen >= c.next {
+		// The blocks are left uncovered when a log of any of them failed, failures of later blocks are kept
+		pending := c.failed[:0]
+		covered := true
+		for _, block := range c.failed {
+			if block <= c.seen {
+				covered = covered && block < c.next
+				continue
+			}
+			pending = append(pending, block)
+		}
+		c.failed = pending
+
+		if covered {
+			ec.recordCoverage(c.next, c.seen)
+		}
+		c.next = c.seen + 1
+	}
+	c.seen = head
+}
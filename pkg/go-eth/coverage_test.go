@@ -0,0 +1,159 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/eth-bridging/internal/producer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const transferABI = `[{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"}],"name":"Transfer","type":"event"}]`
+
+// fakeCoverageRecorder keeps the covered ranges
+type fakeCoverageRecorder struct {
+	covered [][2]uint64
+}
+
+func (r *fakeCoverageRecorder) RecordCoverage(from, to uint64) {
+	r.covered = append(r.covered, [2]uint64{from, to})
+}
+
+func (r *fakeCoverageRecorder) LastCovered() (uint64, bool, error) {
+	var last uint64
+	for _, rng := range r.covered {
+		last = max(last, rng[1])
+	}
+	return last, len(r.covered) > 0, nil
+}
+
+func transferLog(client *EthereumClient, block uint64) types.Log {
+	return types.Log{
+		Address:     client.addresses[0],
+		Topics:      []common.Hash{client.topics[0]},
+		Data:        common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+		BlockNumber: block,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(block)),
+	}
+}
+
+func TestEthereumClient_BackfillFailedPublishStaysUncovered(t *testing.T) {
+	service := &fakeEthService{head: 200}
+	client, err := NewEventClient(newTestPool(t, service), []string{"0x3a23F943181408EAC424116Af7b7790c94Cb97a5"}, []string{transferABI}, []string{"Transfer"})
+	assert.NoError(t, err)
+	recorder := &fakeCoverageRecorder{}
+	client.TrackCoverage(recorder)
+	service.logs = []types.Log{transferLog(client, 100)}
+
+	streamProducer := new(MockRedisProducer)
+	streamProducer.On("PublishEvent", mock.Anything).Return(errors.New("redis: connection refused"))
+
+	err = client.Backfill(context.Background(), 100, 101, 10, streamProducer)
+
+	assert.ErrorContains(t, err, "failed to publish logs of blocks 100 to 101")
+	assert.Empty(t, recorder.covered)
+
+	// Covered once the logs are published
+	streamProducer = new(MockRedisProducer)
+	streamProducer.On("PublishEvent", mock.Anything).Return(nil)
+
+	assert.NoError(t, client.Backfill(context.Background(), 100, 101, 10, streamProducer))
+	assert.Equal(t, [][2]uint64{{100, 101}}, recorder.covered)
+}
+
+func TestEthereumClient_BackfillTimesEventsByBlock(t *testing.T) {
+	service := &fakeEthService{head: 200}
+	client, err := NewEventClient(newTestPool(t, service), []string{"0x3a23F943181408EAC424116Af7b7790c94Cb97a5"}, []string{transferABI}, []string{"Transfer"})
+	assert.NoError(t, err)
+	service.logs = []types.Log{transferLog(client, 100)}
+
+	var published []models.BridgeEvent
+	streamProducer := new(MockRedisProducer)
+	streamProducer.On("PublishEvent", mock.Anything).Run(func(args mock.Arguments) {
+		published = append(published, args.Get(0).(models.BridgeEvent))
+	}).Return(nil)
+
+	assert.NoError(t, client.Backfill(context.Background(), 100, 100, 10, streamProducer))
+
+	// Replayed events are timed by their block rather than when they were backfilled
+	blockTime := time.Unix(1734188400+100*60, 0).UTC()
+	if assert.Len(t, published, 1) && assert.NotNil(t, published[0].BlockTimestamp) {
+		assert.Equal(t, blockTime, *published[0].BlockTimestamp)
+		assert.Equal(t, blockTime, published[0].Timestamp)
+	}
+}
+
+func TestEthereumClient_BackfillAlongsideLiveLogs(t *testing.T) {
+	service := &fakeEthService{head: 200}
+	client, err := NewEventClient(newTestPool(t, service), []string{"0x3a23F943181408EAC424116Af7b7790c94Cb97a5"}, []string{transferABI}, []string{"Transfer"})
+	assert.NoError(t, err)
+	for block := uint64(100); block < 120; block++ {
+		service.logs = append(service.logs, transferLog(client, block))
+	}
+
+	// The coverage monitor backfills with the client the subscription publishes live logs with
+	var backfilled []models.BridgeEvent
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		backfilled = publishedBy(t, func(streamProducer producer.Producer) {
+			assert.NoError(t, client.Backfill(context.Background(), 100, 119, 5, streamProducer))
+		})
+	}()
+	live := publishedBy(t, func(streamProducer producer.Producer) {
+		for block := uint64(150); block < 170; block++ {
+			vLogs := []types.Log{transferLog(client, block), transferLog(client, block)}
+			assert.NoError(t, client.handleFilterLogs(context.Background(), vLogs, streamProducer, false))
+		}
+	})
+	wg.Wait()
+
+	// Every event is timed by its own block
+	assert.Len(t, backfilled, 4*20)
+	assert.Len(t, live, 2*20)
+	for _, event := range append(backfilled, live...) {
+		if assert.NotNil(t, event.BlockTimestamp) {
+			assert.Equal(t, time.Unix(1734188400+int64(event.BlockNumber)*60, 0).UTC(), *event.BlockTimestamp)
+		}
+	}
+}
+
+// publishedBy returns the events published by fn
+func publishedBy(t *testing.T, fn func(streamProducer producer.Producer)) []models.BridgeEvent {
+	var published []models.BridgeEvent
+	streamProducer := new(MockRedisProducer)
+	streamProducer.On("PublishEvent", mock.Anything).Run(func(args mock.Arguments) {
+		published = append(published, args.Get(0).(models.BridgeEvent))
+	}).Return(nil)
+
+	fn(streamProducer)
+	return published
+}
+
+func TestLiveCoverage_FailedBlocksStayUncovered(t *testing.T) {
+	service := &fakeEthService{head: 110}
+	client := &EthereumClient{endpoints: newTestPool(t, service)}
+	recorder := &fakeCoverageRecorder{}
+	client.TrackCoverage(recorder)
+
+	live := &liveCoverage{next: 101, seen: 100}
+	live.advance(context.Background(), client)
+	live.fail(112)
+	service.head = 120
+	live.advance(context.Background(), client)
+	service.head = 130
+	live.advance(context.Background(), client)
+
+	// Blocks up to 110 were delivered before the failure, 111 to 120 may hold the failed log
+	assert.Equal(t, [][2]uint64{{101, 110}}, recorder.covered)
+	assert.Equal(t, uint64(121), live.next)
+}
@@ -0,0 +1,231 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SocketBridgeEvent is the name of the event emitted by SocketGate for every bridging transaction
+const SocketBridgeEvent = models.EventSocketBridge
+
+// ErrNoDecoder is returned for logs whose topic0 has no decoder registered
+var ErrNoDecoder = errors.New("no decoder registered for the log")
+
+// EventDecoder decodes the fields specific to an event, fields every log has,
+// like its transaction hash and block, are filled in by the client,
+// while the event name and payload are filled in by the registry.
+//
+// event is the ABI definition the log matched, from whichever ABI defines it
+type EventDecoder func(event abi.Event, vLog types.Log) (*models.BridgeEvent, error)
+
+// DecoderRegistry routes logs to decoders by their topic0, the event signature hash.
+//
+// Events are looked up in every ABI the registry was created with,
+// so decoders can be registered for events of any of the watched contracts
+type DecoderRegistry struct {
+	events   map[common.Hash]abi.Event
+	decoders map[common.Hash]EventDecoder
+}
+
+// NewDecoderRegistry parses the ABIs, given as JSON, the registry starts without decoders
+func NewDecoderRegistry(contractABIs ...string) (*DecoderRegistry, error) {
+	registry := &DecoderRegistry{
+		events:   make(map[common.Hash]abi.Event),
+		decoders: make(map[common.Hash]EventDecoder),
+	}
+
+	for i, contractABI := range contractABIs {
+		parsed, err := abi.JSON(strings.NewReader(contractABI))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse contract ABI %d: %w", i, err)
+		}
+		// Events with the same signature have the same ID, whichever ABI comes first wins
+		for _, event := range parsed.Events {
+			if _, ok := registry.events[event.ID]; !ok {
+				registry.events[event.ID] = event
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+// ReadABIFiles reads the ABI JSON of each file, a file may also hold
+// a compiler artifact with the ABI under its `abi` key
+func ReadABIFiles(paths []string) ([]string, error) {
+	contractABIs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ABI file: %w", err)
+		}
+		contractABIs = append(contractABIs, extractABI(contents))
+	}
+
+	return contractABIs, nil
+}
+
+// extractABI returns the `abi` of a compiler artifact (hardhat, foundry, truffle), or the contents as they are
+func extractABI(contents []byte) string {
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(contents, &artifact); err == nil && len(artifact.ABI) > 0 {
+		return string(artifact.ABI)
+	}
+	return string(contents)
+}
+
+// Register routes logs of every event named name to the decoder,
+// events can be overloaded so there may be several of them
+func (r *DecoderRegistry) Register(name string, decoder EventDecoder) error {
+	registered := false
+	for id, event := range r.events {
+		if event.Name == name {
+			r.decoders[id] = decoder
+			registered = true
+		}
+	}
+
+	if !registered {
+		return fmt.Errorf("event %s isn't defined in any of the ABIs", name)
+	}
+	return nil
+}
+
+// RegisterDefault routes logs of every event without a decoder to this one
+func (r *DecoderRegistry) RegisterDefault(decoder EventDecoder) {
+	for id := range r.events {
+		if _, ok := r.decoders[id]; !ok {
+			r.decoders[id] = decoder
+		}
+	}
+}
+
+// Decode decodes the log with the decoder registered for its topic0,
+// every argument of the event is kept in its payload as well
+func (r *DecoderRegistry) Decode(vLog types.Log) (*models.BridgeEvent, error) {
+	if len(vLog.Topics) == 0 {
+		return nil, ErrNoDecoder
+	}
+
+	decoder, ok := r.decoders[vLog.Topics[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w, topic %s", ErrNoDecoder, vLog.Topics[0].Hex())
+	}
+
+	event := r.events[vLog.Topics[0]]
+	decoded, err := decoder(event, vLog)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := DecodePayload(event, vLog)
+	if err != nil {
+		return nil, err
+	}
+	decoded.EventName = event.Name
+	decoded.Payload = payload
+
+	return decoded, nil
+}
+
+// Topics returns the topic0 of every event a decoder is registered for, in a stable order
+func (r *DecoderRegistry) Topics() []common.Hash {
+	topics := make([]common.Hash, 0, len(r.decoders))
+	for id := range r.decoders {
+		topics = append(topics, id)
+	}
+
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].Cmp(topics[j]) < 0
+	})
+	return topics
+}
+
+// ResolveTopics turns topics given as hashes or event names into topic0 hashes,
+// all registered events are watched when none are given
+func (r *DecoderRegistry) ResolveTopics(topics []string) ([]common.Hash, error) {
+	var resolved []common.Hash
+	for _, topic := range topics {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+
+		if strings.HasPrefix(topic, "0x") {
+			hash := common.HexToHash(topic)
+			if _, ok := r.decoders[hash]; !ok {
+				return nil, fmt.Errorf("topic %s has no decoder registered", topic)
+			}
+			resolved = append(resolved, hash)
+			continue
+		}
+
+		found := false
+		for id, event := range r.events {
+			if event.Name != topic {
+				continue
+			}
+			if _, ok := r.decoders[id]; !ok {
+				return nil, fmt.Errorf("event %s has no decoder registered", topic)
+			}
+			resolved = append(resolved, id)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("event %s isn't defined in any of the ABIs", topic)
+		}
+	}
+
+	if len(resolved) == 0 {
+		return r.Topics(), nil
+	}
+	return resolved, nil
+}
+
+// decodeGenericEvent leaves every typed column empty, the event is only kept as its payload
+func decodeGenericEvent(event abi.Event, vLog types.Log) (*models.BridgeEvent, error) {
+	return &models.BridgeEvent{}, nil
+}
+
+// newSocketBridgeDecoder decodes SocketBridge events, bridge names are decoded using bridgeNames
+func newSocketBridgeDecoder(bridgeNames *BridgeNameDecoder) EventDecoder {
+	return func(event abi.Event, vLog types.Log) (*models.BridgeEvent, error) {
+		bridgingEvent, err := decodeSocketBridgeEvent(event, vLog)
+		if err != nil {
+			return nil, err
+		}
+
+		bridgeName, known := bridgeNames.Decode(bridgingEvent.BridgeName)
+		if !known {
+			log.Printf("Unknown bridge name %s in tx %s, add it to BRIDGE_NAMES", bridgeName, bridgingEvent.TxHash)
+		}
+
+		return &models.BridgeEvent{
+			FromChain:     bridgingEvent.Sender.Hex(),
+			ToChain:       bridgingEvent.Receiver.Hex(),
+			Amount:        fmt.Sprint(bridgingEvent.Amount),
+			Token:         fmt.Sprint(bridgingEvent.Token),
+			DestChainID:   fmt.Sprint(bridgingEvent.ToChainId),
+			BridgeName:    bridgeName,
+			BridgeNameRaw: hexutil.Encode(bridgingEvent.BridgeName[:]),
+		}, nil
+	}
+}
@@ -0,0 +1,121 @@
+package ethereum
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eth-bridging/config"
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const routeABI = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"routeId","type":"uint256"},{"indexed":false,"internalType":"address","name":"route","type":"address"}],"name":"NewRouteAdded","type":"event"}]`
+
+func newTestRegistry(t *testing.T) *DecoderRegistry {
+	registry, err := NewDecoderRegistry(config.LoadConfig().ContractABI, routeABI)
+	assert.NoError(t, err)
+
+	bridgeNames, err := NewBridgeNameDecoder(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Register(SocketBridgeEvent, newSocketBridgeDecoder(bridgeNames)))
+	return registry
+}
+
+func TestDecoderRegistry_DecodeSocketBridge(t *testing.T) {
+	registry := newTestRegistry(t)
+	event := registry.events[common.HexToHash("0x74594da9e31ee4068e17809037db37db496702bf7d8d63afe6f97949277d1609")]
+
+	var hop [32]byte
+	copy(hop[:], "hop")
+	data, err := event.Inputs.Pack(
+		big.NewInt(1500), common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"), big.NewInt(10), hop,
+		common.HexToAddress("0x01"), common.HexToAddress("0x02"), [32]byte{},
+	)
+	assert.NoError(t, err)
+
+	decoded, err := registry.Decode(types.Log{Topics: []common.Hash{event.ID}, Data: data})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1500", decoded.Amount)
+	assert.Equal(t, "10", decoded.DestChainID)
+	assert.Equal(t, "hop", decoded.BridgeName)
+	assert.Equal(t, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", decoded.Token)
+	assert.Equal(t, SocketBridgeEvent, decoded.EventName)
+	assert.Equal(t, "1500", decoded.Payload["amount"])
+	assert.Equal(t, "0x"+common.Bytes2Hex(hop[:]), decoded.Payload["bridgeName"])
+}
+
+func TestDecoderRegistry_RoutesByTopic(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	var routes []uint64
+	err := registry.Register("NewRouteAdded", func(event abi.Event, vLog types.Log) (*models.BridgeEvent, error) {
+		routes = append(routes, new(big.Int).SetBytes(vLog.Topics[1].Bytes()).Uint64())
+		return &models.BridgeEvent{}, nil
+	})
+	assert.NoError(t, err)
+
+	routeAdded := registry.events[crypto.Keccak256Hash([]byte("NewRouteAdded(uint256,address)"))]
+	data, err := routeAdded.Inputs.NonIndexed().Pack(common.HexToAddress("0x3a23f943181408eac424116af7b7790c94cb97a5"))
+	assert.NoError(t, err)
+
+	decoded, err := registry.Decode(types.Log{Topics: []common.Hash{routeAdded.ID, common.BigToHash(big.NewInt(404))}, Data: data})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{404}, routes)
+	assert.Equal(t, "NewRouteAdded", decoded.EventName)
+	assert.Equal(t, models.EventPayload{
+		"routeId": "404",
+		"route":   "0x3a23F943181408EAC424116Af7b7790c94Cb97a5",
+	}, decoded.Payload)
+
+	_, err = registry.Decode(types.Log{Topics: []common.Hash{common.HexToHash("0x01")}})
+	assert.ErrorIs(t, err, ErrNoDecoder)
+
+	assert.Len(t, registry.Topics(), 2)
+}
+
+func TestDecoderRegistry_ResolveTopics(t *testing.T) {
+	registry := newTestRegistry(t)
+	socketBridge := common.HexToHash("0x74594da9e31ee4068e17809037db37db496702bf7d8d63afe6f97949277d1609")
+
+	topics, err := registry.ResolveTopics([]string{"", "SocketBridge"})
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Hash{socketBridge}, topics)
+
+	topics, err = registry.ResolveTopics([]string{socketBridge.Hex()})
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Hash{socketBridge}, topics)
+
+	// Everything with a decoder is watched by default
+	topics, err = registry.ResolveTopics(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Hash{socketBridge}, topics)
+
+	// Defined, but there's nothing to decode it with
+	_, err = registry.ResolveTopics([]string{"NewRouteAdded"})
+	assert.Error(t, err)
+
+	_, err = registry.ResolveTopics([]string{"Transfer"})
+	assert.Error(t, err)
+}
+
+func TestReadABIFiles(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "route.json")
+	artifact := filepath.Join(dir, "Artifact.json")
+	assert.NoError(t, os.WriteFile(plain, []byte(routeABI), 0o600))
+	assert.NoError(t, os.WriteFile(artifact, []byte(`{"contractName":"Route","abi":`+routeABI+`,"bytecode":"0x"}`), 0o600))
+
+	contractABIs, err := ReadABIFiles([]string{plain, artifact})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{routeABI, routeABI}, contractABIs)
+
+	_, err = ReadABIFiles([]string{filepath.Join(dir, "missing.json")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,491 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// healthSmoothing is the weight of the latest observation in the moving averages of
+// latency and error rate, a handful of failures in a row is enough to mark an endpoint unhealthy
+const healthSmoothing = 0.2
+
+// ErrNoEndpoint is returned when none of the endpoints can serve a call
+var ErrNoEndpoint = errors.New("no RPC endpoint available")
+
+// PoolOptions tunes health checks of the EndpointPool
+type PoolOptions struct {
+	// CheckInterval is how often every endpoint's head is polled
+	CheckInterval time.Duration
+	// CallTimeout bounds health checks and dialing
+	CallTimeout time.Duration
+	// MaxHeadLag is the number of blocks an endpoint can be behind the highest head and still be healthy
+	MaxHeadLag uint64
+	// MaxErrorRate is the share of failing calls above which an endpoint is unhealthy
+	MaxErrorRate float64
+}
+
+// DefaultPoolOptions are sensible defaults for production use
+var DefaultPoolOptions = PoolOptions{
+	CheckInterval: 15 * time.Second,
+	CallTimeout:   5 * time.Second,
+	MaxHeadLag:    3,
+	MaxErrorRate:  0.5,
+}
+
+// endpoint is a single RPC endpoint along with its health
+type endpoint struct {
+	url  string
+	name string
+
+	// rpc and client are nil until the endpoint is dialed
+	rpc    *rpc.Client
+	client *ethclient.Client
+
+	head      uint64
+	headAt    time.Time
+	latency   time.Duration
+	errorRate float64
+	lastError string
+	checkedAt time.Time
+}
+
+// subscribable reports whether the transport supports `eth_subscribe`, http endpoints don't
+func (e *endpoint) subscribable() bool {
+	return strings.HasPrefix(e.url, "ws://") || strings.HasPrefix(e.url, "wss://") || !strings.Contains(e.url, "://")
+}
+
+// EndpointPool routes calls to the healthiest of several RPC endpoints.
+//
+// Endpoints are health checked in the background, polling their head for freshness
+// and latency, failing calls routed through the pool count towards their error rate.
+// A call failing on one endpoint is retried on the next healthiest one
+type EndpointPool struct {
+	opts PoolOptions
+
+	mu         sync.RWMutex
+	endpoints  []*endpoint
+	subscribed *endpoint
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEndpointPool dials every endpoint, endpoints failing to dial are retried on every
+// health check. It fails only if none of them can be dialed
+func NewEndpointPool(urls []string, opts PoolOptions) (*EndpointPool, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := &EndpointPool{
+		opts:   opts,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for _, rawURL := range urls {
+		rawURL = strings.TrimSpace(rawURL)
+		if rawURL == "" {
+			continue
+		}
+		pool.endpoints = append(pool.endpoints, &endpoint{url: rawURL, name: redactURL(rawURL)})
+	}
+	if len(pool.endpoints) == 0 {
+		cancel()
+		return nil, errors.New("no RPC endpoints configured")
+	}
+
+	dialed := 0
+	for _, ep := range pool.endpoints {
+		if pool.dial(ep) == nil {
+			dialed++
+		}
+	}
+	if dialed == 0 {
+		cancel()
+		return nil, fmt.Errorf("failed to connect to any of %d RPC endpoints: %w", len(pool.endpoints), ErrNoEndpoint)
+	}
+
+	return pool, nil
+}
+
+// Start checks the health of every endpoint right away and then every `CheckInterval`
+func (p *EndpointPool) Start() {
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Stop waits for in flight health checks to finish and closes the connections
+func (p *EndpointPool) Stop() {
+	p.cancel()
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, ep := range p.endpoints {
+		if ep.rpc != nil {
+			ep.rpc.Close()
+		}
+	}
+}
+
+// Statuses returns the health of every endpoint in the configured order
+func (p *EndpointPool) Statuses() []models.RPCEndpointStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	bestHead := p.bestHead()
+	statuses := make([]models.RPCEndpointStatus, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		status := models.RPCEndpointStatus{
+			Endpoint:   ep.name,
+			Connected:  ep.rpc != nil,
+			Healthy:    p.healthy(ep, bestHead),
+			Subscribed: ep == p.subscribed,
+			Head:       ep.head,
+			HeadLag:    bestHead - min(ep.head, bestHead),
+			LatencyMs:  ep.latency.Milliseconds(),
+			ErrorRate:  ep.errorRate,
+			LastError:  ep.lastError,
+		}
+		if !ep.headAt.IsZero() {
+			headAt := ep.headAt
+			status.HeadAt = &headAt
+		}
+		if !ep.checkedAt.IsZero() {
+			checkedAt := ep.checkedAt
+			status.CheckedAt = &checkedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// BatchCallContext sends the batch to the healthiest endpoint, so the pool can be used by the ReceiptEnricher
+func (p *EndpointPool) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	return p.call(ctx, false, func(ep *endpoint) error {
+		return ep.rpc.BatchCallContext(ctx, batch)
+	})
+}
+
+// HeaderByNumber fetches the block header from the healthiest endpoint
+func (p *EndpointPool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var header *types.Header
+	err := p.call(ctx, false, func(ep *endpoint) error {
+		var err error
+		header, err = ep.client.HeaderByNumber(ctx, number)
+		return err
+	})
+
+	return header, err
+}
+
+// BlockNumber returns the head of the healthiest endpoint
+func (p *EndpointPool) BlockNumber(ctx context.Context) (uint64, error) {
+	var head uint64
+	err := p.call(ctx, false, func(ep *endpoint) error {
+		var err error
+		head, err = ep.client.BlockNumber(ctx)
+		return err
+	})
+
+	return head, err
+}
+
+// Subscribable reports whether any of the endpoints supports log subscriptions
+func (p *EndpointPool) Subscribable() bool {
+	for _, ep := range p.endpoints {
+		if ep.subscribable() {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterLogs runs the query on the healthiest endpoint
+func (p *EndpointPool) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	err := p.call(ctx, false, func(ep *endpoint) error {
+		var err error
+		logs, err = ep.client.FilterLogs(ctx, query)
+		return err
+	})
+
+	return logs, err
+}
+
+// subscribeFilterLogs subscribes on the healthiest endpoint supporting subscriptions,
+// the endpoint is returned so the subscriber can tell when to fail over, see shouldFailOver
+func (p *EndpointPool) subscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, *endpoint, error) {
+	var (
+		sub        ethereum.Subscription
+		subscribed *endpoint
+	)
+	err := p.call(ctx, true, func(ep *endpoint) error {
+		var err error
+		sub, err = ep.client.SubscribeFilterLogs(ctx, query, ch)
+		subscribed = ep
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.mu.Lock()
+	p.subscribed = subscribed
+	p.mu.Unlock()
+
+	return sub, subscribed, nil
+}
+
+// unsubscribed records the subscription on ep failed, counting towards its error rate
+func (p *EndpointPool) unsubscribed(ep *endpoint, err error) {
+	p.mu.Lock()
+	if p.subscribed == ep {
+		p.subscribed = nil
+	}
+	p.mu.Unlock()
+
+	if err != nil {
+		p.observe(ep, 0, err)
+	}
+}
+
+// shouldFailOver reports whether ep became unhealthy while another endpoint
+// supporting subscriptions is healthy, so the subscription should be moved
+func (p *EndpointPool) shouldFailOver(ep *endpoint) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	bestHead := p.bestHead()
+	if p.healthy(ep, bestHead) {
+		return false
+	}
+	for _, other := range p.endpoints {
+		if other != ep && other.subscribable() && p.healthy(other, bestHead) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// call runs fn on endpoints from the healthiest down until it succeeds
+func (p *EndpointPool) call(ctx context.Context, subscription bool, fn func(ep *endpoint) error) error {
+	ranked := p.ranked(subscription)
+	if len(ranked) == 0 {
+		return ErrNoEndpoint
+	}
+
+	var errs []error
+	for _, ep := range ranked {
+		start := time.Now()
+		err := fn(ep)
+		// A range with too many logs fails on every endpoint, it's the request at fault
+		if err != nil && isTooManyResults(err) {
+			p.observe(ep, time.Since(start), nil)
+			return err
+		}
+
+		p.observe(ep, time.Since(start), err)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", ep.name, err))
+		// Nothing to fail over if the caller gave up
+		if ctx.Err() != nil {
+			break
+		}
+		log.Printf("RPC call failed on %s, trying the next endpoint: %v", ep.name, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ranked returns the connected endpoints, healthy ones first and ordered by score within each group
+func (p *EndpointPool) ranked(subscription bool) []*endpoint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	bestHead := p.bestHead()
+	ranked := make([]*endpoint, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		if ep.rpc == nil || (subscription && !ep.subscribable()) {
+			continue
+		}
+		ranked = append(ranked, ep)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		healthyI, healthyJ := p.healthy(ranked[i], bestHead), p.healthy(ranked[j], bestHead)
+		if healthyI != healthyJ {
+			return healthyI
+		}
+		return score(ranked[i], bestHead) < score(ranked[j], bestHead)
+	})
+
+	return ranked
+}
+
+// healthy reports whether ep is connected, recently reported a head close to the
+// highest one and isn't failing too many calls. Endpoints not checked yet are healthy
+func (p *EndpointPool) healthy(ep *endpoint, bestHead uint64) bool {
+	if ep.rpc == nil || ep.errorRate > p.opts.MaxErrorRate {
+		return false
+	}
+	if ep.headAt.IsZero() {
+		return ep.checkedAt.IsZero()
+	}
+
+	return time.Since(ep.headAt) <= 3*p.opts.CheckInterval && ep.head+p.opts.MaxHeadLag >= bestHead
+}
+
+// score is lower the better, every block behind weighs as much as a second of latency
+// and a call failing in ten as much as a second as well
+func score(ep *endpoint, bestHead uint64) time.Duration {
+	lag := time.Duration(bestHead-min(ep.head, bestHead)) * time.Second
+	failures := time.Duration(ep.errorRate * float64(10*time.Second))
+
+	return ep.latency + lag + failures
+}
+
+// bestHead is the highest head reported by any endpoint, the caller holds the lock
+func (p *EndpointPool) bestHead() uint64 {
+	var best uint64
+	for _, ep := range p.endpoints {
+		best = max(best, ep.head)
+	}
+	return best
+}
+
+// observe updates the moving averages of ep with the outcome of a call
+func (p *EndpointPool) observe(ep *endpoint, latency time.Duration, err error) {
+	// Calls given up by the caller say nothing about the endpoint
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	failed := 0.0
+	if err != nil {
+		failed = 1
+		ep.lastError = err.Error()
+	} else if ep.latency == 0 {
+		ep.latency = latency
+	} else {
+		ep.latency = time.Duration((1-healthSmoothing)*float64(ep.latency) + healthSmoothing*float64(latency))
+	}
+	ep.errorRate = (1-healthSmoothing)*ep.errorRate + healthSmoothing*failed
+}
+
+func (p *EndpointPool) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.checkAll()
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll checks every endpoint concurrently, so a hanging one doesn't delay the others
+func (p *EndpointPool) checkAll() {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.check(ep)
+		}()
+	}
+	wg.Wait()
+}
+
+// check redials ep if needed and polls its head
+func (p *EndpointPool) check(ep *endpoint) {
+	p.mu.RLock()
+	connected := ep.rpc != nil
+	p.mu.RUnlock()
+
+	if !connected {
+		if err := p.dial(ep); err != nil {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, p.opts.CallTimeout)
+	defer cancel()
+
+	start := time.Now()
+	head, err := ep.client.BlockNumber(ctx)
+	p.observe(ep, time.Since(start), err)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ep.checkedAt = time.Now()
+	if err != nil {
+		return
+	}
+	// headAt only moves along with the head, so a node which stopped syncing goes stale, see healthy
+	if head > ep.head || ep.headAt.IsZero() {
+		ep.head = head
+		ep.headAt = ep.checkedAt
+	}
+}
+
+// dial connects to ep, failures are recorded as its last error
+func (p *EndpointPool) dial(ep *endpoint) error {
+	ctx, cancel := context.WithTimeout(p.ctx, p.opts.CallTimeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, ep.url)
+	if err != nil {
+		log.Printf("Failed to connect to RPC endpoint %s: %v", ep.name, err)
+		p.observe(ep, 0, err)
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.rpc = client
+	ep.client = ethclient.NewClient(client)
+	return nil
+}
+
+// redactURL strips the path, query and credentials off the URL,
+// providers put API keys in them and statuses are served over the API
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "endpoint"
+	}
+	// IPC paths carry no secrets
+	if parsed.Host == "" {
+		return rawURL
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
@@ -0,0 +1,144 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DecodePayload decodes every argument of the event, from the log's data as well as its
+// indexed topics. Indexed arguments of dynamic types (strings, bytes, arrays and tuples)
+// are only available as the keccak256 hash of their value, which is what's kept for them.
+//
+// Arguments without a name are keyed by their position, e.g. `arg2`
+func DecodePayload(event abi.Event, vLog types.Log) (models.EventPayload, error) {
+	values, err := event.Inputs.Unpack(vLog.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s data: %w", event.Name, err)
+	}
+
+	payload := make(models.EventPayload, len(event.Inputs))
+	topics := vLog.Topics
+	if !event.Anonymous && len(topics) > 0 {
+		topics = topics[1:]
+	}
+
+	nonIndexed := 0
+	for i, input := range event.Inputs {
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+
+		if !input.Indexed {
+			payload[name] = jsonValue(reflect.ValueOf(values[nonIndexed]))
+			nonIndexed++
+			continue
+		}
+
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("%s log is missing the topic of %s", event.Name, name)
+		}
+		topic := topics[0]
+		topics = topics[1:]
+
+		if isHashedTopic(input.Type) {
+			payload[name] = topic.Hex()
+			continue
+		}
+
+		// ParseTopicsIntoMap keys by name, so the argument is renamed to the one it's stored as
+		parsed := make(map[string]interface{}, 1)
+		argument := abi.Argument{Name: "value", Type: input.Type, Indexed: true}
+		if err := abi.ParseTopicsIntoMap(parsed, abi.Arguments{argument}, []common.Hash{topic}); err != nil {
+			return nil, fmt.Errorf("failed to parse %s topic %s: %w", event.Name, name, err)
+		}
+		payload[name] = jsonValue(reflect.ValueOf(parsed["value"]))
+	}
+
+	return payload, nil
+}
+
+// isHashedTopic reports whether indexed arguments of the type are stored as the hash of their value
+func isHashedTopic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
+// jsonValue converts a value unpacked by the abi package to one that survives a JSON round trip
+func jsonValue(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch v := value.Interface().(type) {
+	case *big.Int:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	}
+
+	switch value.Kind() {
+	case reflect.Bool, reflect.String:
+		return value.Interface()
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return value.Int()
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return value.Uint()
+	// Larger than a JSON number holds exactly
+	case reflect.Int, reflect.Int64:
+		return fmt.Sprint(value.Int())
+	case reflect.Uint, reflect.Uint64:
+		return fmt.Sprint(value.Uint())
+	case reflect.Ptr:
+		return jsonValue(value.Elem())
+	case reflect.Array:
+		// bytesN
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			raw := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(raw), value)
+			return hexutil.Encode(raw)
+		}
+		return jsonSlice(value)
+	case reflect.Slice:
+		return jsonSlice(value)
+	case reflect.Struct:
+		// Tuples are unpacked into structs with the ABI names as json tags
+		object := make(map[string]interface{}, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name := field.Tag.Get("json")
+			if name == "" {
+				name = field.Name
+			}
+			object[name] = jsonValue(value.Field(i))
+		}
+		return object
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+func jsonSlice(value reflect.Value) []interface{} {
+	items := make([]interface{}, value.Len())
+	for i := range items {
+		items[i] = jsonValue(value.Index(i))
+	}
+	return items
+}
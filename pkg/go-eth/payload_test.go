@@ -0,0 +1,87 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const payloadABI = `[{"anonymous":false,"name":"Filled","type":"event","inputs":[
+	{"indexed":true,"name":"depositId","type":"uint32"},
+	{"indexed":true,"name":"relayer","type":"address"},
+	{"indexed":true,"name":"message","type":"string"},
+	{"indexed":false,"name":"amount","type":"uint256"},
+	{"indexed":false,"name":"fillDeadline","type":"uint64"},
+	{"indexed":false,"name":"","type":"bool"},
+	{"indexed":false,"name":"chainIds","type":"uint16[]"},
+	{"indexed":false,"name":"route","type":"tuple","components":[{"name":"target","type":"address"},{"name":"data","type":"bytes"}]}
+]}]`
+
+func TestDecodePayload(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(payloadABI))
+	assert.NoError(t, err)
+	event := parsed.Events["Filled"]
+
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	route := struct {
+		Target common.Address `json:"target"`
+		Data   []byte         `json:"data"`
+	}{common.HexToAddress("0x02"), []byte{0xca, 0xfe}}
+	data, err := event.Inputs.NonIndexed().Pack(amount, uint64(1734185823), true, []uint16{10, 137}, route)
+	assert.NoError(t, err)
+
+	vLog := types.Log{
+		Topics: []common.Hash{
+			event.ID,
+			common.BigToHash(big.NewInt(77)),
+			common.BytesToHash(common.HexToAddress("0x01").Bytes()),
+			crypto.Keccak256Hash([]byte("hello")),
+		},
+		Data: data,
+	}
+
+	payload, err := DecodePayload(event, vLog)
+	assert.NoError(t, err)
+
+	// Stored as jsonb and read back, wide integers stay exact
+	encoded, err := payload.Value()
+	assert.NoError(t, err)
+	var stored models.EventPayload
+	assert.NoError(t, stored.Scan(encoded))
+
+	assert.Equal(t, json.Number("77"), stored["depositId"])
+	assert.Equal(t, "0x0000000000000000000000000000000000000001", stored["relayer"])
+	assert.Equal(t, crypto.Keccak256Hash([]byte("hello")).Hex(), stored["message"])
+	assert.Equal(t, "123456789012345678901234567890", stored["amount"])
+	assert.Equal(t, "1734185823", stored["fillDeadline"])
+	assert.Equal(t, true, stored["arg5"])
+	assert.Equal(t, []interface{}{json.Number("10"), json.Number("137")}, stored["chainIds"])
+	assert.Equal(t, map[string]interface{}{"target": "0x0000000000000000000000000000000000000002", "data": "0xcafe"}, stored["route"])
+
+	field, ok := stored.Field("depositId")
+	assert.True(t, ok)
+	assert.Equal(t, "77", field)
+}
+
+func TestDecodePayload_MissingTopic(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(payloadABI))
+	assert.NoError(t, err)
+	event := parsed.Events["Filled"]
+
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(1), uint64(1), true, []uint16{}, struct {
+		Target common.Address `json:"target"`
+		Data   []byte         `json:"data"`
+	}{})
+	assert.NoError(t, err)
+
+	_, err = DecodePayload(event, types.Log{Topics: []common.Hash{event.ID}, Data: data})
+	assert.Error(t, err)
+}
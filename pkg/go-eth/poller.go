@@ -0,0 +1,217 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eth-bridging/internal/producer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Ingestion modes, `auto` polls only when none of the endpoints supports subscriptions
+const (
+	IngestModeAuto      = "auto"
+	IngestModeSubscribe = "subscribe"
+	IngestModePoll      = "poll"
+)
+
+// PollerOptions tunes the LogPoller
+type PollerOptions struct {
+	// Interval is the wait between polls once caught up with the head
+	Interval time.Duration
+	// Confirmations is the number of blocks logs are held back for, so they're
+	// only read once they're unlikely to be reorged out
+	Confirmations uint64
+	// MaxRange is the largest number of blocks requested in a single `eth_getLogs` call
+	MaxRange uint64
+	// StartBlock is the first block read, 0 continues after the last block recorded as covered,
+	// see CoverageRecorder, or starts at the confirmed head when coverage isn't tracked yet
+	StartBlock uint64
+}
+
+// DefaultPollerOptions are sensible defaults for mainnet
+var DefaultPollerOptions = PollerOptions{
+	Interval:      12 * time.Second,
+	Confirmations: 12,
+	MaxRange:      2000,
+}
+
+// tooManyResultsErrors match the errors providers return when a range
+// holds too many logs or spans too many blocks
+var tooManyResultsErrors = []*regexp.Regexp{
+	regexp.MustCompile(`query returned more than \d+ results`),
+	regexp.MustCompile(`response size exceeded`),
+	regexp.MustCompile(`response size is larger`),
+	regexp.MustCompile(`block range is too wide`),
+	regexp.MustCompile(`block range too large`),
+	regexp.MustCompile(`exceed maximum block range`),
+	regexp.MustCompile(`range is too large`),
+	regexp.MustCompile(`eth_getlogs is limited to a [\d,]+ (block )?range`),
+}
+
+// logSource is the part of the EndpointPool the poller reads from, so it can be faked in tests
+type logSource interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// LogPoller reads bridging events with `eth_getLogs` instead of a subscription,
+// for providers only exposing HTTP endpoints.
+//
+// It follows the head with `eth_blockNumber`, reading logs of confirmed blocks in ranges
+// of at most `MaxRange` blocks. Ranges are halved whenever the provider rejects them as too
+// large and grow back gradually once they succeed
+type LogPoller struct {
+	source  logSource
+	query   ethereum.FilterQuery
+	publish func(ctx context.Context, vLogs []types.Log, streamProducer producer.Producer) error
+	covered func(from, to uint64)
+	// lastCovered is where polling continues from after a restart, see CoverageRecorder
+	lastCovered func() (uint64, bool, error)
+	opts        PollerOptions
+
+	// next is the first block not read yet, rangeSize the number of blocks read at once
+	next      uint64
+	rangeSize uint64
+}
+
+// NewLogPoller polls for the events of the client, decoding and publishing them the same way it does
+func NewLogPoller(ec *EthereumClient, opts PollerOptions) *LogPoller {
+	return &LogPoller{
+		source: ec.endpoints,
+		query:  ec.filterQuery(),
+		publish: func(ctx context.Context, vLogs []types.Log, streamProducer producer.Producer) error {
+			// Confirmed blocks are read as they come, events are timed when they're ingested
+			return ec.handleLogBatches(ctx, vLogs, streamProducer, false)
+		},
+		covered:     ec.recordCoverage,
+		lastCovered: ec.lastCovered,
+		opts:        opts,
+		next:        opts.StartBlock,
+		rangeSize:   max(opts.MaxRange, 1),
+	}
+}
+
+// NewIngester returns the client itself when events are read with a subscription,
+// or a LogPoller for it when they're polled for. Backfills after resubscribing read
+// as many blocks at once as polls
+func NewIngester(ec *EthereumClient, mode string, opts PollerOptions) (EthereumClientInterface, error) {
+	if opts.MaxRange > 0 {
+		ec.backfillRange = opts.MaxRange
+	}
+
+	switch mode {
+	case IngestModeSubscribe:
+		return ec, nil
+	case IngestModePoll:
+		return NewLogPoller(ec, opts), nil
+	case IngestModeAuto:
+		if ec.endpoints.Subscribable() {
+			return ec, nil
+		}
+		log.Println("None of the RPC endpoints supports subscriptions, polling for logs")
+		return NewLogPoller(ec, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown ingest mode %q, must be auto, subscribe or poll", mode)
+	}
+}
+
+// StartBridgingEventPublisher polls for bridging events and publishes them to the Redis stream
+// via the provided streamProducer. Failed polls are retried from the same block,
+// it only returns once ctx is done
+func (p *LogPoller) StartBridgingEventPublisher(ctx context.Context, streamProducer producer.Producer) error {
+	for {
+		if err := p.poll(ctx, streamProducer); err != nil && ctx.Err() == nil {
+			log.Printf("Error polling logs from block %d: %v", p.next, err)
+		}
+
+		if !sleepContext(ctx, p.opts.Interval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// poll reads logs from the next block up to the confirmed head
+func (p *LogPoller) poll(ctx context.Context, streamProducer producer.Producer) error {
+	head, err := p.source.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch head: %w", err)
+	}
+	if head < p.opts.Confirmations {
+		return nil
+	}
+
+	confirmed := head - p.opts.Confirmations
+	if p.next == 0 {
+		if p.next, err = p.start(confirmed); err != nil {
+			return err
+		}
+		log.Printf("Polling logs from block %d", p.next)
+	}
+
+	for p.next <= confirmed && ctx.Err() == nil {
+		to := min(confirmed, p.next+p.rangeSize-1)
+
+		query := p.query
+		query.FromBlock = new(big.Int).SetUint64(p.next)
+		query.ToBlock = new(big.Int).SetUint64(to)
+
+		vLogs, err := p.source.FilterLogs(ctx, query)
+		if err != nil && isTooManyResults(err) && p.rangeSize > 1 {
+			p.rangeSize = max(p.rangeSize/2, 1)
+			log.Printf("Too many logs in blocks %d-%d, reading %d blocks at once", p.next, to, p.rangeSize)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Failed logs aren't retried here so they can't hold up polling, the range is left
+		// uncovered instead and backfilled again, see CoverageRecorder
+		if err := p.publish(ctx, vLogs, streamProducer); err != nil {
+			log.Printf("Error publishing logs of blocks %d to %d: %v", p.next, to, err)
+		} else if p.covered != nil {
+			p.covered(p.next, to)
+		}
+		p.next = to + 1
+
+		// Grow by a quarter so a range which just failed isn't requested again right away
+		p.rangeSize = min(p.rangeSize+p.rangeSize/4+1, max(p.opts.MaxRange, 1))
+	}
+
+	return nil
+}
+
+// start returns the block polling starts at without a StartBlock, the one after the last covered block
+// so blocks published before a restart aren't skipped, or the confirmed head when none was covered
+func (p *LogPoller) start(confirmed uint64) (uint64, error) {
+	if p.lastCovered == nil {
+		return confirmed, nil
+	}
+
+	last, ok, err := p.lastCovered()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch last covered block: %w", err)
+	}
+	if !ok {
+		return confirmed, nil
+	}
+	return last + 1, nil
+}
+
+// isTooManyResults reports whether the provider rejected the range as too large
+func isTooManyResults(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, pattern := range tooManyResultsErrors {
+		if pattern.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eth-bridging/internal/producer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLogSource has a log in every block and rejects ranges larger than maxRange
+type fakeLogSource struct {
+	head     uint64
+	maxRange uint64
+	ranges   [][2]uint64
+}
+
+func (s *fakeLogSource) BlockNumber(ctx context.Context) (uint64, error) {
+	return s.head, nil
+}
+
+func (s *fakeLogSource) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	s.ranges = append(s.ranges, [2]uint64{from, to})
+
+	if s.maxRange > 0 && to-from+1 > s.maxRange {
+		return nil, errors.New("query returned more than 10000 results. Try with this block range [0x64, 0x66].")
+	}
+
+	var vLogs []types.Log
+	for block := from; block <= to; block++ {
+		vLogs = append(vLogs, types.Log{BlockNumber: block})
+	}
+	return vLogs, nil
+}
+
+// newTestPoller returns a poller recording the blocks of the logs it publishes
+func newTestPoller(source *fakeLogSource, opts PollerOptions) (*LogPoller, *[]uint64) {
+	var published []uint64
+	poller := &LogPoller{
+		source: source,
+		publish: func(ctx context.Context, vLogs []types.Log, streamProducer producer.Producer) error {
+			for _, vLog := range vLogs {
+				published = append(published, vLog.BlockNumber)
+			}
+			return nil
+		},
+		opts:      opts,
+		next:      opts.StartBlock,
+		rangeSize: opts.MaxRange,
+	}
+	return poller, &published
+}
+
+func blockRange(from, to uint64) []uint64 {
+	var blocks []uint64
+	for block := from; block <= to; block++ {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestLogPoller_ConfirmedRanges(t *testing.T) {
+	source := &fakeLogSource{head: 120}
+	poller, published := newTestPoller(source, PollerOptions{Confirmations: 10, MaxRange: 4, StartBlock: 100})
+
+	assert.NoError(t, poller.poll(context.Background(), nil))
+
+	assert.Equal(t, [][2]uint64{{100, 103}, {104, 107}, {108, 110}}, source.ranges)
+	assert.Equal(t, blockRange(100, 110), *published)
+
+	// Nothing new is confirmed until the head moves
+	assert.NoError(t, poller.poll(context.Background(), nil))
+	assert.Len(t, source.ranges, 3)
+
+	source.head = 122
+	assert.NoError(t, poller.poll(context.Background(), nil))
+	assert.Equal(t, [2]uint64{111, 112}, source.ranges[3])
+}
+
+func TestLogPoller_RecordsCoverage(t *testing.T) {
+	source := &fakeLogSource{head: 120}
+	poller, _ := newTestPoller(source, PollerOptions{Confirmations: 10, MaxRange: 4, StartBlock: 100})
+	var covered [][2]uint64
+	poller.covered = func(from, to uint64) {
+		covered = append(covered, [2]uint64{from, to})
+	}
+
+	assert.NoError(t, poller.poll(context.Background(), nil))
+
+	// Ranges are covered once their logs are published
+	assert.Equal(t, [][2]uint64{{100, 103}, {104, 107}, {108, 110}}, covered)
+}
+
+func TestLogPoller_FailedRangeStaysUncovered(t *testing.T) {
+	source := &fakeLogSource{head: 120}
+	poller, _ := newTestPoller(source, PollerOptions{Confirmations: 10, MaxRange: 4, StartBlock: 100})
+	poller.publish = func(ctx context.Context, vLogs []types.Log, streamProducer producer.Producer) error {
+		if vLogs[0].BlockNumber == 104 {
+			return errors.New("1 of 4 logs failed to decode or publish")
+		}
+		return nil
+	}
+	var covered [][2]uint64
+	poller.covered = func(from, to uint64) {
+		covered = append(covered, [2]uint64{from, to})
+	}
+
+	assert.NoError(t, poller.poll(context.Background(), nil))
+
+	// Polling goes on, the failed range is left as a gap to backfill
+	assert.Equal(t, [][2]uint64{{100, 103}, {108, 110}}, covered)
+	assert.Equal(t, uint64(111), poller.next)
+}
+
+func TestLogPoller_StartsAtConfirmedHead(t *testing.T) {
+	source := &fakeLogSource{head: 1000}
+	poller, published := newTestPoller(source, DefaultPollerOptions)
+
+	assert.NoError(t, poller.poll(context.Background(), nil))
+
+	assert.Equal(t, []uint64{988}, *published)
+}
+
+func TestLogPoller_ResumesAfterLastCovered(t *testing.T) {
+	source := &fakeLogSource{head: 1000}
+	poller, published := newTestPoller(source, PollerOptions{Confirmations: 12, MaxRange: 10})
+	poller.lastCovered = func() (uint64, bool, error) {
+		return 980, true, nil
+	}
+
+	assert.NoError(t, poller.poll(context.Background(), nil))
+
+	// Blocks since the last covered one aren't skipped after a restart
+	assert.Equal(t, blockRange(981, 988), *published)
+}
+
+func TestLogPoller_RetriesLastCoveredError(t *testing.T) {
+	source := &fakeLogSource{head: 1000}
+	poller, published := newTestPoller(source, PollerOptions{Confirmations: 12, MaxRange: 10})
+	poller.lastCovered = func() (uint64, bool, error) {
+		return 0, false, errors.New("connection refused")
+	}
+
+	assert.Error(t, poller.poll(context.Background(), nil))
+
+	// Rather than starting at the head and skipping blocks
+	assert.Zero(t, poller.next)
+	assert.Empty(t, *published)
+}
+
+func TestLogPoller_ShrinksRangeOnTooManyResults(t *testing.T) {
+	source := &fakeLogSource{head: 130, maxRange: 3}
+	poller, published := newTestPoller(source, PollerOptions{MaxRange: 8, StartBlock: 100})
+
+	assert.NoError(t, poller.poll(context.Background(), nil))
+
+	// Every block is published once and in order, no matter how often ranges were rejected
+	assert.Equal(t, blockRange(100, 130), *published)
+	assert.Equal(t, [][2]uint64{{100, 107}, {100, 103}, {100, 101}}, source.ranges[:3])
+	assert.LessOrEqual(t, poller.rangeSize, uint64(8))
+}
+
+func TestLogPoller_StopsOnContextDone(t *testing.T) {
+	poller, _ := newTestPoller(&fakeLogSource{head: 100}, PollerOptions{Interval: time.Hour, MaxRange: 10})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, poller.StartBridgingEventPublisher(ctx, nil), context.Canceled)
+}
+
+func TestIsTooManyResults(t *testing.T) {
+	assert.True(t, isTooManyResults(errors.New("query returned more than 10000 results")))
+	assert.True(t, isTooManyResults(errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range")))
+	assert.True(t, isTooManyResults(errors.New("eth_getLogs is limited to a 10,000 range")))
+	assert.True(t, isTooManyResults(errors.New("eth_getLogs is limited to a 10000 block range")))
+	assert.False(t, isTooManyResults(errors.New("query returned more than expected")))
+	assert.False(t, isTooManyResults(errors.New("requests are limited to 10 per second")))
+	assert.False(t, isTooManyResults(errors.New("429 Too Many Requests")))
+	assert.False(t, isTooManyResults(context.DeadlineExceeded))
+}
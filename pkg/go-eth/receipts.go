@@ -0,0 +1,176 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// receiptBatchSize bounds the number of receipts requested in a single JSON-RPC batch,
+	// providers reject or throttle larger batches
+	receiptBatchSize = 50
+	// receiptCacheSize is the number of receipts kept, a transaction often emits
+	// several events and redelivered logs are common after reconnecting
+	receiptCacheSize = 4096
+)
+
+// batchCaller is the part of rpc.Client used to fetch receipts, so it can be faked in tests
+type batchCaller interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// Receipt holds the fields of `eth_getTransactionReceipt` events are enriched with
+//
+// types.Receipt doesn't decode `from` and `to`, hence the separate type
+type Receipt struct {
+	Status            hexutil.Uint64  `json:"status"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+}
+
+// ReceiptEnricher adds gas, status and sender details of the transaction to events
+type ReceiptEnricher struct {
+	rpc   batchCaller
+	cache *lru.Cache[common.Hash, *Receipt]
+}
+
+func NewReceiptEnricher(rpc batchCaller, cacheSize int) *ReceiptEnricher {
+	return &ReceiptEnricher{
+		rpc:   rpc,
+		cache: lru.NewCache[common.Hash, *Receipt](cacheSize),
+	}
+}
+
+// Enrich fills in the receipt fields of the events, receipts which aren't cached are
+// fetched with as few batch calls as possible. Events are left as they are when their
+// receipt can't be fetched, enrichment never holds back an event
+func (e *ReceiptEnricher) Enrich(ctx context.Context, events []*models.BridgeEvent) {
+	receipts := make(map[common.Hash]*Receipt)
+
+	var missing []common.Hash
+	for _, event := range events {
+		hash := common.HexToHash(event.TransactionHash)
+		if _, seen := receipts[hash]; seen {
+			continue
+		}
+
+		receipt, _ := e.cache.Get(hash)
+		receipts[hash] = receipt
+		if receipt == nil {
+			missing = append(missing, hash)
+		}
+	}
+
+	for start := 0; start < len(missing); start += receiptBatchSize {
+		end := min(start+receiptBatchSize, len(missing))
+		e.fetch(ctx, missing[start:end], receipts)
+	}
+
+	for _, event := range events {
+		if receipt := receipts[common.HexToHash(event.TransactionHash)]; receipt != nil {
+			applyReceipt(event, receipt)
+		}
+	}
+}
+
+// fetch requests the receipts in a single batch, adding the ones found to receipts and the cache
+func (e *ReceiptEnricher) fetch(ctx context.Context, hashes []common.Hash, receipts map[common.Hash]*Receipt) {
+	results := make([]*Receipt, len(hashes))
+	batch := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash},
+			Result: &results[i],
+		}
+	}
+
+	if err := e.rpc.BatchCallContext(ctx, batch); err != nil {
+		log.Printf("Error fetching %d receipts: %v", len(hashes), err)
+		return
+	}
+
+	for i, elem := range batch {
+		if elem.Error != nil {
+			log.Printf("Error fetching receipt of tx %s: %v", hashes[i].Hex(), elem.Error)
+			continue
+		}
+		// Nodes return null for receipts they don't have yet, those aren't cached so they're retried
+		if results[i] == nil {
+			continue
+		}
+
+		e.cache.Add(hashes[i], results[i])
+		receipts[hashes[i]] = results[i]
+	}
+}
+
+func applyReceipt(event *models.BridgeEvent, receipt *Receipt) {
+	gasUsed := uint64(receipt.GasUsed)
+	status := uint8(receipt.Status)
+	from := receipt.From.Hex()
+
+	event.GasUsed = &gasUsed
+	event.TxStatus = &status
+	event.TxFrom = &from
+
+	if receipt.EffectiveGasPrice != nil {
+		price := (*big.Int)(receipt.EffectiveGasPrice).String()
+		event.EffectiveGasPrice = &price
+	}
+	// Contract creations have no `to`
+	if receipt.To != nil {
+		to := receipt.To.Hex()
+		event.TxTo = &to
+	}
+}
+
+// inclusion holds the fields of `eth_getTransactionReceipt` telling where the transaction is included
+type inclusion struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+// InclusionBlocks returns the block every transaction is included in, transactions which aren't
+// in the chain are left out. Receipts are always fetched, cached ones could be of a reorged block.
+// Any failed request fails the whole lookup, so a transaction is never taken as missing by mistake
+func InclusionBlocks(ctx context.Context, caller batchCaller, txHashes []string) (map[string]uint64, error) {
+	blocks := make(map[string]uint64, len(txHashes))
+
+	for start := 0; start < len(txHashes); start += receiptBatchSize {
+		hashes := txHashes[start:min(start+receiptBatchSize, len(txHashes))]
+
+		results := make([]*inclusion, len(hashes))
+		batch := make([]rpc.BatchElem, len(hashes))
+		for i, hash := range hashes {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{common.HexToHash(hash)},
+				Result: &results[i],
+			}
+		}
+
+		if err := caller.BatchCallContext(ctx, batch); err != nil {
+			return nil, fmt.Errorf("failed to fetch %d receipts: %w", len(hashes), err)
+		}
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, fmt.Errorf("failed to fetch receipt of tx %s: %w", hashes[i], elem.Error)
+			}
+			if results[i] != nil {
+				blocks[hashes[i]] = uint64(results[i].BlockNumber)
+			}
+		}
+	}
+
+	return blocks, nil
+}
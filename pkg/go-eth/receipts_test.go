@@ -0,0 +1,146 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/eth-bridging/internal/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBatchCaller answers receipt requests from a map, receipts missing from it are null.
+// Requests of hashes in failures fail individually, err fails the whole batch
+type fakeBatchCaller struct {
+	receipts map[common.Hash]string
+	failures map[common.Hash]error
+	batches  [][]common.Hash
+	err      error
+}
+
+func (f *fakeBatchCaller) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	var hashes []common.Hash
+	for i := range batch {
+		hash := batch[i].Args[0].(common.Hash)
+		hashes = append(hashes, hash)
+
+		if err, ok := f.failures[hash]; ok {
+			batch[i].Error = err
+			continue
+		}
+
+		raw, ok := f.receipts[hash]
+		if !ok {
+			raw = "null"
+		}
+		batch[i].Error = json.Unmarshal([]byte(raw), batch[i].Result)
+	}
+	f.batches = append(f.batches, hashes)
+
+	return nil
+}
+
+const testReceipt = `{
+	"status": "0x1",
+	"gasUsed": "0x5208",
+	"effectiveGasPrice": "0x2540be400",
+	"from": "0x0041b0239420debf7885433d09ae4f274d3d8ac3",
+	"to": "0x3a23f943181408eac424116af7b7790c94cb97a5"
+}`
+
+func TestReceiptEnricher_Enrich(t *testing.T) {
+	txA := common.HexToHash("0xaa")
+	txB := common.HexToHash("0xbb")
+	caller := &fakeBatchCaller{receipts: map[common.Hash]string{txA: testReceipt}}
+	enricher := NewReceiptEnricher(caller, 16)
+
+	// Two events of the same transaction and one without a receipt yet
+	events := []*models.BridgeEvent{
+		{TransactionHash: txA.Hex(), LogIndex: 1},
+		{TransactionHash: txA.Hex(), LogIndex: 2},
+		{TransactionHash: txB.Hex()},
+	}
+	enricher.Enrich(context.Background(), events)
+
+	assert.Equal(t, [][]common.Hash{{txA, txB}}, caller.batches)
+	for _, event := range events[:2] {
+		assert.Equal(t, uint64(21000), *event.GasUsed)
+		assert.Equal(t, "10000000000", *event.EffectiveGasPrice)
+		assert.Equal(t, uint8(1), *event.TxStatus)
+		assert.Equal(t, "0x0041B0239420DebF7885433d09AE4f274d3d8AC3", *event.TxFrom)
+		assert.Equal(t, "0x3a23F943181408EAC424116Af7b7790c94Cb97a5", *event.TxTo)
+	}
+	assert.Nil(t, events[2].GasUsed)
+
+	// Cached receipts aren't requested again, missing ones are retried
+	again := []*models.BridgeEvent{{TransactionHash: txA.Hex()}, {TransactionHash: txB.Hex()}}
+	enricher.Enrich(context.Background(), again)
+
+	assert.Equal(t, []common.Hash{txB}, caller.batches[1])
+	assert.Equal(t, uint64(21000), *again[0].GasUsed)
+}
+
+func TestReceiptEnricher_BatchError(t *testing.T) {
+	enricher := NewReceiptEnricher(&fakeBatchCaller{err: errors.New("connection refused")}, 16)
+
+	event := &models.BridgeEvent{TransactionHash: common.HexToHash("0xaa").Hex()}
+	enricher.Enrich(context.Background(), []*models.BridgeEvent{event})
+
+	// Published without the receipt rather than not at all
+	assert.Nil(t, event.GasUsed)
+	assert.Nil(t, event.TxFrom)
+}
+
+func TestReceiptEnricher_ReceiptError(t *testing.T) {
+	txA := common.HexToHash("0xaa")
+	txB := common.HexToHash("0xbb")
+	caller := &fakeBatchCaller{
+		receipts: map[common.Hash]string{txA: testReceipt, txB: testReceipt},
+		failures: map[common.Hash]error{txB: errors.New("header not found")},
+	}
+	enricher := NewReceiptEnricher(caller, 16)
+
+	events := []*models.BridgeEvent{{TransactionHash: txA.Hex()}, {TransactionHash: txB.Hex()}}
+	enricher.Enrich(context.Background(), events)
+
+	// The rest of the batch is still applied
+	assert.Equal(t, uint64(21000), *events[0].GasUsed)
+	assert.Nil(t, events[1].GasUsed)
+	assert.Nil(t, events[1].TxStatus)
+
+	// The failed receipt isn't cached, it's requested again
+	delete(caller.failures, txB)
+	again := []*models.BridgeEvent{{TransactionHash: txB.Hex()}}
+	enricher.Enrich(context.Background(), again)
+
+	assert.Equal(t, []common.Hash{txB}, caller.batches[1])
+	assert.Equal(t, uint64(21000), *again[0].GasUsed)
+}
+
+func TestInclusionBlocks(t *testing.T) {
+	txA := common.HexToHash("0xaa")
+	txB := common.HexToHash("0xbb")
+	caller := &fakeBatchCaller{receipts: map[common.Hash]string{txA: `{"blockNumber": "0x6c"}`}}
+
+	// Reorged transactions have no receipt
+	blocks, err := InclusionBlocks(context.Background(), caller, []string{txA.Hex(), txB.Hex()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint64{txA.Hex(): 108}, blocks)
+
+	// A failed lookup doesn't leave transactions out
+	caller.failures = map[common.Hash]error{txB: errors.New("header not found")}
+	_, err = InclusionBlocks(context.Background(), caller, []string{txA.Hex(), txB.Hex()})
+	assert.ErrorContains(t, err, "header not found")
+
+	caller.err = errors.New("connection reset")
+	_, err = InclusionBlocks(context.Background(), caller, []string{txA.Hex()})
+	assert.Error(t, err)
+}